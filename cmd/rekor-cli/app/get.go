@@ -32,7 +32,10 @@ import (
 	"github.com/sigstore/rekor/pkg/generated/client/entries"
 	"github.com/sigstore/rekor/pkg/generated/models"
 	"github.com/sigstore/rekor/pkg/log"
+	"github.com/sigstore/rekor/pkg/pki/minisign"
+	"github.com/sigstore/rekor/pkg/sharding"
 	"github.com/sigstore/rekor/pkg/types"
+	rekord_v001 "github.com/sigstore/rekor/pkg/types/rekord/v0.0.1"
 )
 
 type getCmdOutput struct {
@@ -40,6 +43,7 @@ type getCmdOutput struct {
 	LogIndex       int
 	IntegratedTime int64
 	UUID           string
+	TrustedComment string `json:",omitempty"`
 }
 
 func (g *getCmdOutput) String() string {
@@ -47,6 +51,9 @@ func (g *getCmdOutput) String() string {
 	dt := time.Unix(g.IntegratedTime, 0).UTC().Format(time.RFC3339)
 	s += fmt.Sprintf("IntegratedTime: %s\n", dt)
 	s += fmt.Sprintf("UUID: %s\n", g.UUID)
+	if g.TrustedComment != "" {
+		s += fmt.Sprintf("TrustedComment: %s\n", g.TrustedComment)
+	}
 	var b bytes.Buffer
 	e := json.NewEncoder(&b)
 	e.SetIndent("", "  ")
@@ -100,8 +107,13 @@ var getCmd = &cobra.Command{
 				return nil, err
 			}
 
+			// the UUID may have been given without the tree ID prefix of the entry returned
+			want, err := sharding.UUIDFromEntryID(uuid)
+			if err != nil {
+				return nil, err
+			}
 			for k, entry := range resp.Payload {
-				if k != uuid {
+				if got, err := sharding.UUIDFromEntryID(k); err != nil || got != want {
 					continue
 				}
 				return parseEntry(k, entry)
@@ -132,11 +144,25 @@ func parseEntry(uuid string, e models.LogEntryAnon) (interface{}, error) {
 		UUID:           uuid,
 		IntegratedTime: e.IntegratedTime,
 		LogIndex:       int(*e.LogIndex),
+		TrustedComment: trustedComment(eimpl),
 	}
 
 	return &obj, nil
 }
 
+// trustedComment returns the signed comment carried by minisign signatures, if the entry has one
+func trustedComment(eimpl types.EntryImpl) string {
+	re, ok := eimpl.(*rekord_v001.V001Entry)
+	if !ok || re.RekordObj.Signature == nil || re.RekordObj.Signature.Format != minisign.FORMAT {
+		return ""
+	}
+	sig, err := minisign.NewSignature(bytes.NewReader(re.RekordObj.Signature.Content))
+	if err != nil {
+		return ""
+	}
+	return sig.TrustedComment()
+}
+
 func init() {
 	if err := addUUIDPFlags(getCmd, false); err != nil {
 		log.Logger.Fatal("Error parsing cmd line args:", err)
@@ -17,22 +17,27 @@ package app
 
 import (
 	"bytes"
-	"crypto/x509"
 	"encoding/hex"
-	"encoding/pem"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
 	"github.com/google/trillian/merkle/logverifier"
 	rfc6962 "github.com/google/trillian/merkle/rfc6962/hasher"
+	"github.com/google/trillian/types"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
 	"github.com/sigstore/rekor/cmd/rekor-cli/app/format"
 	"github.com/sigstore/rekor/cmd/rekor-cli/app/state"
+	"github.com/sigstore/rekor/pkg/generated/client"
+	"github.com/sigstore/rekor/pkg/generated/client/pubkey"
 	"github.com/sigstore/rekor/pkg/generated/client/tlog"
+	"github.com/sigstore/rekor/pkg/generated/models"
 	"github.com/sigstore/rekor/pkg/log"
 	"github.com/sigstore/rekor/pkg/verify"
 )
@@ -41,16 +46,51 @@ type logInfoCmdOutput struct {
 	TreeSize       int64
 	RootHash       string
 	TimestampNanos uint64
+	TreeID         string                `json:",omitempty"`
+	InactiveShards []inactiveShardOutput `json:",omitempty"`
+	// number of witnesses that cosigned a tree head consistent with this one, if a quorum was required
+	Witnesses        int      `json:",omitempty"`
+	SupportedFormats []string `json:",omitempty"`
+}
+
+// inactiveShardOutput is the verified state of a frozen shard of the log
+type inactiveShardOutput struct {
+	TreeID         string
+	StartIndex     int64
+	TreeSize       int64
+	RootHash       string
+	TimestampNanos uint64
 }
 
 func (l *logInfoCmdOutput) String() string {
 	// Verification is always successful if we return an object.
 	ts := time.Unix(0, int64(l.TimestampNanos)).UTC().Format(time.RFC3339)
-	return fmt.Sprintf(`Verification Successful!
+	s := fmt.Sprintf(`Verification Successful!
 Tree Size: %v
 Root Hash: %s
 Timestamp: %s
 `, l.TreeSize, l.RootHash, ts)
+	if l.TreeID != "" {
+		s += fmt.Sprintf("TreeID: %s\n", l.TreeID)
+	}
+	if l.Witnesses > 0 {
+		s += fmt.Sprintf("Cosigned by Witnesses: %v\n", l.Witnesses)
+	}
+	if len(l.SupportedFormats) > 0 {
+		s += fmt.Sprintf("Supported Formats: %s\n", strings.Join(l.SupportedFormats, ", "))
+	}
+	for _, shard := range l.InactiveShards {
+		ts := time.Unix(0, int64(shard.TimestampNanos)).UTC().Format(time.RFC3339)
+		s += fmt.Sprintf(`
+Inactive Shard:
+  TreeID: %s
+  Start Index: %v
+  Tree Size: %v
+  Root Hash: %s
+  Timestamp: %s
+`, shard.TreeID, shard.StartIndex, shard.TreeSize, shard.RootHash, ts)
+	}
+	return s
 }
 
 // logInfoCmd represents the current information about the transparency log
@@ -58,6 +98,12 @@ var logInfoCmd = &cobra.Command{
 	Use:   "loginfo",
 	Short: "Rekor loginfo command",
 	Long:  `Prints info about the transparency log`,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		// these are bound here so that they are not overwritten by other commands
+		if err := viper.BindPFlags(cmd.Flags()); err != nil {
+			log.Logger.Fatal("Error initializing cmd line args: ", err)
+		}
+	},
 	Run: format.WrapCmd(func(args []string) (interface{}, error) {
 		serverURL := viper.GetString("rekor_server")
 		rekorClient, err := GetRekorClient(serverURL)
@@ -72,73 +118,82 @@ var logInfoCmd = &cobra.Command{
 
 		logInfo := result.GetPayload()
 
-		logRoot := *logInfo.SignedTreeHead.LogRoot
-		if logRoot == nil {
-			return nil, errors.New("logroot should not be nil")
-		}
-		signature := *logInfo.SignedTreeHead.Signature
-		if signature == nil {
-			return nil, errors.New("signature should not be nil")
-		}
 		publicKey := viper.GetString("rekor_server_public_key")
 		if publicKey == "" {
-			// fetch key from server
-			keyResp, err := rekorClient.Pubkey.GetPublicKey(nil)
+			// fetch every key the log has signed with from the server; each tree head names the key it was signed
+			// with in its key hint
+			params := pubkey.NewGetPublicKeyParams()
+			params.All = swag.Bool(true)
+			keyResp, err := rekorClient.Pubkey.GetPublicKey(params)
 			if err != nil {
 				return nil, err
 			}
 			publicKey = keyResp.Payload
 		}
-
-		block, _ := pem.Decode([]byte(publicKey))
-		if block == nil {
-			return nil, errors.New("failed to decode public key of server")
-		}
-
-		pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+		keys, err := verify.ParseLogKeys([]byte(publicKey))
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("failed to decode public key of server: %w", err)
 		}
 
-		lr, err := verify.SignedLogRoot(pub, logRoot, signature)
+		sth := logInfo.SignedTreeHead
+		lr, err := verifyTreeHead(keys, sth.KeyHint, *sth.LogRoot, *sth.Signature, *logInfo.TreeSize, *logInfo.RootHash)
 		if err != nil {
 			return nil, err
 		}
 		cmdOutput := &logInfoCmdOutput{
-			TreeSize:       *logInfo.TreeSize,
-			RootHash:       *logInfo.RootHash,
-			TimestampNanos: lr.TimestampNanos,
+			TreeSize:         *logInfo.TreeSize,
+			RootHash:         *logInfo.RootHash,
+			TimestampNanos:   lr.TimestampNanos,
+			TreeID:           logInfo.TreeID,
+			SupportedFormats: logInfo.SupportedFormats,
+		}
+
+		if required := viper.GetInt("require-witnesses"); required > 0 {
+			witnessKeys := viper.GetStringSlice("witness-key")
+			if len(witnessKeys) == 0 {
+				return nil, errors.New("--witness-key must be given with --require-witnesses")
+			}
+			witnesses, err := verify.LoadLogKeys(witnessKeys)
+			if err != nil {
+				return nil, fmt.Errorf("loading witness keys: %w", err)
+			}
+			cmdOutput.Witnesses = countWitnesses(rekorClient, witnesses, logInfo, lr)
+			if cmdOutput.Witnesses < required {
+				return nil, fmt.Errorf("tree head is cosigned by %d witnesses, fewer than the %d required", cmdOutput.Witnesses, required)
+			}
 		}
 
-		if lr.TreeSize != uint64(*logInfo.TreeSize) {
-			return nil, errors.New("tree size in signed tree head does not match value returned in API call")
+		for _, shard := range logInfo.InactiveShards {
+			sth := shard.SignedTreeHead
+			shardRoot, err := verifyTreeHead(keys, sth.KeyHint, *sth.LogRoot, *sth.Signature, *shard.TreeSize, *shard.RootHash)
+			if err != nil {
+				return nil, fmt.Errorf("shard %v: %w", *shard.TreeID, err)
+			}
+			cmdOutput.InactiveShards = append(cmdOutput.InactiveShards, inactiveShardOutput{
+				TreeID:         *shard.TreeID,
+				StartIndex:     *shard.StartIndex,
+				TreeSize:       *shard.TreeSize,
+				RootHash:       *shard.RootHash,
+				TimestampNanos: shardRoot.TimestampNanos,
+			})
 		}
 
-		if !strings.EqualFold(hex.EncodeToString(lr.RootHash), *logInfo.RootHash) {
-			return nil, errors.New("root hash in signed tree head does not match value returned in API call")
+		// state is kept for each log on the server, and for each tree, as the active shard changes when the log is
+		// sharded
+		stateKey := serverURL
+		if name := viper.GetString("log"); name != "" {
+			stateKey = fmt.Sprintf("%v/logs/%v", serverURL, name)
+		}
+		if logInfo.TreeID != "" {
+			stateKey = fmt.Sprintf("%v#%v", stateKey, logInfo.TreeID)
 		}
 
-		oldState := state.Load(serverURL)
+		oldState := state.Load(stateKey)
 		if oldState != nil {
 			persistedSize := oldState.TreeSize
 			if persistedSize < lr.TreeSize {
 				log.CliLogger.Infof("Found previous log state, proving consistency between %d and %d", oldState.TreeSize, lr.TreeSize)
-				params := tlog.NewGetLogProofParams()
-				firstSize := int64(persistedSize)
-				params.FirstSize = &firstSize
-				params.LastSize = int64(lr.TreeSize)
-				proof, err := rekorClient.Tlog.GetLogProof(params)
-				if err != nil {
-					return nil, err
-				}
-				hashes := [][]byte{}
-				for _, h := range proof.Payload.Hashes {
-					b, _ := hex.DecodeString(h)
-					hashes = append(hashes, b)
-				}
-				v := logverifier.New(rfc6962.DefaultHasher)
-				if err := v.VerifyConsistencyProof(firstSize, int64(lr.TreeSize), oldState.RootHash,
-					lr.RootHash, hashes); err != nil {
+				if err := ProveConsistency(rekorClient, logInfo.TreeID, int64(persistedSize), oldState.RootHash, int64(lr.TreeSize), lr.RootHash); err != nil {
 					return nil, err
 				}
 				log.CliLogger.Infof("Consistency proof valid!")
@@ -155,7 +210,7 @@ var logInfoCmd = &cobra.Command{
 		}
 
 		if viper.GetBool("store_tree_state") {
-			if err := state.Dump(serverURL, lr); err != nil {
+			if err := state.Dump(stateKey, lr); err != nil {
 				log.CliLogger.Infof("Unable to store previous state: %v", err)
 			}
 		}
@@ -163,6 +218,111 @@ var logInfoCmd = &cobra.Command{
 	}),
 }
 
+// countWitnesses returns the number of distinct witnesses among those given that cosigned a tree head of the
+// active shard that is consistent with the verified log root. Cosignatures over larger tree heads than the log
+// root cannot be checked against it, and cosignatures over the empty tree head attest nothing about the log, so
+// neither is counted.
+func countWitnesses(c *client.Rekor, witnesses verify.LogKeys, logInfo *models.LogInfo, lr *types.LogRootV1) int {
+	treeID, err := strconv.ParseInt(logInfo.TreeID, 10, 64)
+	if err != nil {
+		return 0
+	}
+	counted := map[string]bool{}
+	for _, cs := range logInfo.Cosignatures {
+		if cs == nil || cs.Validate(strfmt.Default) != nil {
+			continue
+		}
+		witness := hex.EncodeToString(*cs.KeyHint)
+		if *cs.TreeID != logInfo.TreeID || counted[witness] {
+			continue
+		}
+		root, err := witnesses.Cosignature(*cs.KeyHint, treeID, *cs.LogRoot, *cs.Signature)
+		if err != nil {
+			log.CliLogger.Infof("Ignoring cosignature: %v", err)
+			continue
+		}
+		switch {
+		case root.TreeSize == 0:
+			log.CliLogger.Infof("Ignoring cosignature of witness %v over the empty tree head", witness)
+			continue
+		case root.TreeSize > lr.TreeSize:
+			log.CliLogger.Infof("Ignoring cosignature of witness %v over tree size %d, which is larger than %d", witness, root.TreeSize, lr.TreeSize)
+			continue
+		case root.TreeSize == lr.TreeSize:
+			if !bytes.Equal(root.RootHash, lr.RootHash) {
+				log.CliLogger.Infof("Witness %v cosigned a different root hash for tree size %d", witness, root.TreeSize)
+				continue
+			}
+		default:
+			if err := ProveConsistency(c, logInfo.TreeID, int64(root.TreeSize), root.RootHash, int64(lr.TreeSize), lr.RootHash); err != nil {
+				log.CliLogger.Infof("Tree head cosigned by witness %v is not consistent with the log: %v", witness, err)
+				continue
+			}
+		}
+		counted[witness] = true
+	}
+	return len(counted)
+}
+
+// ErrInconsistentTree is returned by ProveConsistency if the proof fetched does not verify
+var ErrInconsistentTree = errors.New("consistency proof did not verify")
+
+// ProveConsistency fetches a consistency proof between two sizes of the tree with the ID given, or of the active
+// shard if the ID is empty, and verifies it against the root hashes of the tree at those sizes
+func ProveConsistency(c *client.Rekor, treeID string, firstSize int64, firstRoot []byte, lastSize int64, lastRoot []byte) error {
+	params := tlog.NewGetLogProofParams()
+	params.FirstSize = &firstSize
+	params.LastSize = lastSize
+	if treeID != "" {
+		params.TreeID = &treeID
+	}
+	proof, err := c.Tlog.GetLogProof(params)
+	if err != nil {
+		return err
+	}
+	hashes := [][]byte{}
+	for _, h := range proof.Payload.Hashes {
+		b, _ := hex.DecodeString(h)
+		hashes = append(hashes, b)
+	}
+	v := logverifier.New(rfc6962.DefaultHasher)
+	if err := v.VerifyConsistencyProof(firstSize, lastSize, firstRoot, lastRoot, hashes); err != nil {
+		return fmt.Errorf("%w: %v", ErrInconsistentTree, err)
+	}
+	return nil
+}
+
+// verifyTreeHead verifies the signature over the log root with the key of the log named by the key hint, and that
+// the root matches the tree size and root hash returned alongside it
+func verifyTreeHead(keys verify.LogKeys, keyHint *strfmt.Base64, logRoot, signature []byte, treeSize int64, rootHash string) (*types.LogRootV1, error) {
+	if logRoot == nil {
+		return nil, errors.New("logroot should not be nil")
+	}
+	if signature == nil {
+		return nil, errors.New("signature should not be nil")
+	}
+
+	var hint []byte
+	if keyHint != nil {
+		hint = *keyHint
+	}
+	lr, err := keys.SignedLogRoot(hint, logRoot, signature)
+	if err != nil {
+		return nil, err
+	}
+
+	if lr.TreeSize != uint64(treeSize) {
+		return nil, errors.New("tree size in signed tree head does not match value returned in API call")
+	}
+
+	if !strings.EqualFold(hex.EncodeToString(lr.RootHash), rootHash) {
+		return nil, errors.New("root hash in signed tree head does not match value returned in API call")
+	}
+	return lr, nil
+}
+
 func init() {
+	logInfoCmd.Flags().Int("require-witnesses", 0, "number of witnesses that must have cosigned a tree head consistent with the current one")
+	logInfoCmd.Flags().StringSlice("witness-key", []string{}, "PEM files holding the public keys of trusted witnesses")
 	rootCmd.AddCommand(logInfoCmd)
 }
@@ -0,0 +1,104 @@
+//
+// Copyright 2021 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+	rfc6962 "github.com/google/trillian/merkle/rfc6962/hasher"
+	"github.com/google/trillian/types"
+
+	"github.com/sigstore/rekor/pkg/generated/models"
+	"github.com/sigstore/rekor/pkg/signer"
+	"github.com/sigstore/rekor/pkg/verify"
+)
+
+func TestCountWitnesses(t *testing.T) {
+	ctx := context.Background()
+	var witnesses verify.LogKeys
+	var signers []*signer.Memory
+	for i := 0; i < 4; i++ {
+		s, err := signer.NewMemory()
+		if err != nil {
+			t.Fatalf("getting signer: %v", err)
+		}
+		pub, err := s.PublicKey(ctx)
+		if err != nil {
+			t.Fatalf("getting public key: %v", err)
+		}
+		key, err := verify.NewLogKey(pub)
+		if err != nil {
+			t.Fatalf("getting log key: %v", err)
+		}
+		signers = append(signers, s)
+		witnesses = append(witnesses, key)
+	}
+
+	rootHash := bytes.Repeat([]byte{1}, 32)
+	lr := &types.LogRootV1{TreeSize: 5, RootHash: rootHash}
+	cosign := func(witness int, treeID string, treeSize uint64, rootHash []byte) *models.Cosignature {
+		t.Helper()
+		logRoot, err := (&types.LogRootV1{TreeSize: treeSize, RootHash: rootHash}).MarshalBinary()
+		if err != nil {
+			t.Fatalf("marshalling log root: %v", err)
+		}
+		id := map[string]int64{"42": 42, "43": 43}[treeID]
+		sig, _, err := signers[witness].Sign(ctx, verify.CosignedMessage(id, logRoot))
+		if err != nil {
+			t.Fatalf("signing: %v", err)
+		}
+		keyHint := strfmt.Base64(witnesses[witness].ID)
+		lrb := strfmt.Base64(logRoot)
+		sigb := strfmt.Base64(sig)
+		return &models.Cosignature{KeyHint: &keyHint, LogRoot: &lrb, Signature: &sigb, TreeID: swag.String(treeID)}
+	}
+
+	tests := []struct {
+		name         string
+		cosignatures []*models.Cosignature
+		want         int
+	}{
+		{"current tree head", []*models.Cosignature{cosign(0, "42", 5, rootHash)}, 1},
+		{"repeated witness", []*models.Cosignature{cosign(0, "42", 5, rootHash), cosign(0, "42", 5, rootHash)}, 1},
+		{"several witnesses", []*models.Cosignature{cosign(0, "42", 5, rootHash), cosign(1, "42", 5, rootHash)}, 2},
+		{"empty tree head", []*models.Cosignature{cosign(0, "42", 0, rfc6962.DefaultHasher.EmptyRoot())}, 0},
+		{"empty tree heads of every witness", []*models.Cosignature{
+			cosign(0, "42", 0, rfc6962.DefaultHasher.EmptyRoot()),
+			cosign(1, "42", 0, rfc6962.DefaultHasher.EmptyRoot()),
+			cosign(2, "42", 0, rfc6962.DefaultHasher.EmptyRoot()),
+		}, 0},
+		{"larger tree head", []*models.Cosignature{cosign(0, "42", 7, rootHash)}, 0},
+		{"different root hash", []*models.Cosignature{cosign(0, "42", 5, bytes.Repeat([]byte{2}, 32))}, 0},
+		{"another tree", []*models.Cosignature{cosign(0, "43", 5, rootHash)}, 0},
+		{"mixed", []*models.Cosignature{
+			cosign(0, "42", 0, rfc6962.DefaultHasher.EmptyRoot()),
+			cosign(1, "42", 5, rootHash),
+			cosign(2, "43", 5, rootHash),
+			cosign(3, "42", 7, rootHash),
+		}, 1},
+	}
+	for _, tt := range tests {
+		logInfo := &models.LogInfo{TreeID: "42", Cosignatures: tt.cosignatures}
+		// none of the cases need a consistency proof, so no client is given
+		if got := countWitnesses(nil, witnesses, logInfo, lr); got != tt.want {
+			t.Errorf("%v: expected %d witnesses, got %d", tt.name, tt.want, got)
+		}
+	}
+}
@@ -82,6 +82,9 @@ var logProofCmd = &cobra.Command{
 		params := tlog.NewGetLogProofParams()
 		params.FirstSize = &firstSize
 		params.LastSize = lastSize
+		if treeID := viper.GetString("tree-id"); treeID != "" {
+			params.TreeID = &treeID
+		}
 
 		result, err := rekorClient.Tlog.GetLogProof(params)
 		if err != nil {
@@ -99,6 +102,7 @@ var logProofCmd = &cobra.Command{
 func init() {
 	logProofCmd.Flags().Uint64("first-size", 1, "the size of the log where the proof should begin")
 	logProofCmd.Flags().Uint64("last-size", 0, "the size of the log where the proof should end")
+	logProofCmd.Flags().String("tree-id", "", "the tree ID of the shard of the log to prove consistency within; defaults to the active shard")
 	if err := logProofCmd.MarkFlagRequired("last-size"); err != nil {
 		fmt.Println(err)
 		os.Exit(1)
@@ -0,0 +1,347 @@
+//
+// Copyright 2021 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/go-openapi/swag"
+	homedir "github.com/mitchellh/go-homedir"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/sigstore/rekor/cmd/rekor-cli/app/format"
+	"github.com/sigstore/rekor/pkg/generated/client"
+	"github.com/sigstore/rekor/pkg/generated/client/entries"
+	"github.com/sigstore/rekor/pkg/generated/models"
+	"github.com/sigstore/rekor/pkg/log"
+	"github.com/sigstore/rekor/pkg/monitor"
+	"github.com/sigstore/rekor/pkg/pki"
+)
+
+type monitorCmdOutput struct {
+	NextIndex   int64
+	Matches     int
+	Undecodable int
+}
+
+func (m *monitorCmdOutput) String() string {
+	return fmt.Sprintf("Found %d matching entries and %d entries that could not be decoded; the next entry to check is at index %d\n",
+		m.Matches, m.Undecodable, m.NextIndex)
+}
+
+// monitorCheckpoints holds the index of the next entry to check of each log, keyed by the URL of the log
+type monitorCheckpoints map[string]int64
+
+// monitorCmd represents the monitor command
+var monitorCmd = &cobra.Command{
+	Use:   "monitor",
+	Short: "Rekor monitor command",
+	Long: `Reports entries of the transparency log whose signer is on a watch list of public keys, key
+fingerprints and identities. Each entry added to the log since the last checkpoint is fetched and decoded, and
+each match is written to stdout as a line of JSON and posted to --webhook-url if set. Entries that cannot be
+decoded are reported in the same way, with the reason in the Error field. The checkpoint is kept in
+the --checkpoint file, so that a restarted monitor resumes where it stopped.
+
+Entries are checked once unless --interval is set, in which case the log is polled until the command is stopped.`,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		// these are bound here so that they are not overwritten by other commands
+		if err := viper.BindPFlags(cmd.Flags()); err != nil {
+			log.Logger.Fatal("Error initializing cmd line args: ", err)
+		}
+	},
+	Run: format.WrapCmd(func(args []string) (interface{}, error) {
+		serverURL := viper.GetString("rekor_server")
+		rekorClient, err := GetRekorClient(serverURL)
+		if err != nil {
+			return nil, err
+		}
+		watchList, err := loadWatchList()
+		if err != nil {
+			return nil, err
+		}
+		if viper.GetInt64("batch-size") < 1 {
+			return nil, errors.New("--batch-size must be at least 1")
+		}
+
+		checkpointPath := viper.GetString("checkpoint")
+		if checkpointPath == "" {
+			home, err := homedir.Dir()
+			if err != nil {
+				return nil, err
+			}
+			checkpointPath = filepath.Join(home, ".rekor", "monitor.json")
+		}
+		checkpoints, err := loadMonitorCheckpoints(checkpointPath)
+		if err != nil {
+			return nil, err
+		}
+		checkpointKey := serverURL
+		if name := viper.GetString("log"); name != "" {
+			checkpointKey = fmt.Sprintf("%v/logs/%v", serverURL, name)
+		}
+
+		next, ok := checkpoints[checkpointKey]
+		if !ok {
+			if next = viper.GetInt64("start-index"); next < 0 {
+				// tail the log from its current end
+				if next, err = logEnd(rekorClient); err != nil {
+					return nil, err
+				}
+			}
+			log.CliLogger.Infof("No checkpoint found; checking entries from index %d", next)
+		}
+
+		output := &monitorCmdOutput{}
+		report := func(m *monitor.Match) error {
+			if m.Error != "" {
+				output.Undecodable++
+			} else {
+				output.Matches++
+			}
+			b, err := json.Marshal(m)
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(b))
+			if url := viper.GetString("webhook-url"); url != "" {
+				return postMatch(url, b)
+			}
+			return nil
+		}
+		save := func(next int64) error {
+			checkpoints[checkpointKey] = next
+			return saveMonitorCheckpoints(checkpointPath, checkpoints)
+		}
+
+		interval := viper.GetDuration("interval")
+		for {
+			next, err = monitorEntries(rekorClient, watchList, next, viper.GetInt64("batch-size"), report, save)
+			if interval == 0 {
+				if err != nil {
+					return nil, err
+				}
+				output.NextIndex = next
+				return output, nil
+			}
+			if err != nil {
+				log.CliLogger.Errorf("Error monitoring log: %v", err)
+			}
+			time.Sleep(interval)
+		}
+	}),
+}
+
+// loadWatchList returns the watch list given by the watch flags
+func loadWatchList() (*monitor.WatchList, error) {
+	var keys []pki.PublicKey
+	af := pki.NewArtifactFactory(viper.GetString("pki-format"))
+	for _, path := range viper.GetStringSlice("watch-key") {
+		b, err := ioutil.ReadFile(filepath.Clean(path))
+		if err != nil {
+			return nil, errors.Wrap(err, "reading watched key")
+		}
+		key, err := af.NewPublicKey(bytes.NewReader(b))
+		if err != nil {
+			return nil, fmt.Errorf("watched key %v: %w", path, err)
+		}
+		keys = append(keys, key)
+	}
+	return monitor.NewWatchList(viper.GetStringSlice("watch-fingerprint"), viper.GetStringSlice("watch-identity"), keys)
+}
+
+// indexRange is a range [start, end) of indexes of entries in the log
+type indexRange struct {
+	start, end int64
+}
+
+// logRanges returns the ranges of indexes that hold entries, in order; in a sharded log, there is one range for
+// each shard, and the indexes between the ranges are unused
+func logRanges(c *client.Rekor) ([]indexRange, error) {
+	resp, err := c.Tlog.GetLogInfo(nil)
+	if err != nil {
+		return nil, err
+	}
+	logInfo := resp.Payload
+	var ranges []indexRange
+	var activeStart int64
+	for _, shard := range logInfo.InactiveShards {
+		r := indexRange{start: swag.Int64Value(shard.StartIndex)}
+		r.end = r.start + swag.Int64Value(shard.TreeSize)
+		ranges = append(ranges, r)
+		if r.end > activeStart {
+			activeStart = r.end
+		}
+	}
+	// servers that do not report the start index of the active shard continue the log where the frozen shards end
+	if logInfo.StartIndex != 0 {
+		activeStart = logInfo.StartIndex
+	}
+	ranges = append(ranges, indexRange{start: activeStart, end: activeStart + swag.Int64Value(logInfo.TreeSize)})
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].start < ranges[j].start })
+	return ranges, nil
+}
+
+// logEnd returns the index that the next entry added to the log will have; in a sharded log, this is the end of
+// the active shard
+func logEnd(c *client.Rekor) (int64, error) {
+	ranges, err := logRanges(c)
+	if err != nil {
+		return 0, err
+	}
+	return ranges[len(ranges)-1].end, nil
+}
+
+// monitorEntries checks each entry from the index given to the end of the log in batches, and returns the index
+// of the next entry to check. Unused indexes between the shards of the log are passed over. The checkpoint is
+// saved after each batch; entries are only passed over once every match in them has been reported.
+func monitorEntries(c *client.Rekor, watchList *monitor.WatchList, next, batchSize int64, report func(*monitor.Match) error, save func(int64) error) (int64, error) {
+	ranges, err := logRanges(c)
+	if err != nil {
+		return next, err
+	}
+	for _, r := range ranges {
+		if next < r.start {
+			next = r.start
+		}
+		if next, err = monitorRange(c, watchList, next, r.end, batchSize, report, save); err != nil {
+			return next, err
+		}
+	}
+	return next, nil
+}
+
+// monitorRange checks each entry from the index given to the end of the range in batches
+func monitorRange(c *client.Rekor, watchList *monitor.WatchList, next, end, batchSize int64, report func(*monitor.Match) error, save func(int64) error) (int64, error) {
+	for next < end {
+		last := next + batchSize
+		if last > end {
+			last = end
+		}
+		query := &models.SearchLogQuery{}
+		for i := next; i < last; i++ {
+			query.LogIndexes = append(query.LogIndexes, swag.Int64(i))
+		}
+		params := entries.NewSearchLogQueryParams()
+		params.SetEntry(query)
+		resp, err := c.Entries.SearchLogQuery(params)
+		if err != nil {
+			return next, err
+		}
+
+		type found struct {
+			uuid  string
+			entry models.LogEntryAnon
+		}
+		byIndex := map[int64]found{}
+		for _, logEntry := range resp.Payload {
+			for uuid, e := range logEntry {
+				if e.LogIndex != nil {
+					byIndex[*e.LogIndex] = found{uuid: uuid, entry: e}
+				}
+			}
+		}
+		for i := next; i < last; i++ {
+			f, ok := byIndex[i]
+			if !ok {
+				return next, fmt.Errorf("entry %d was not returned by the log", i)
+			}
+			m, err := watchList.MatchEntry(f.uuid, f.entry)
+			if err != nil {
+				// the signer of an entry that cannot be decoded cannot be ruled out
+				m = monitor.UndecodableEntry(f.uuid, f.entry, err)
+			}
+			if m != nil {
+				if err := report(m); err != nil {
+					return next, errors.Wrapf(err, "reporting entry %d", i)
+				}
+			}
+			next = i + 1
+		}
+		if err := save(next); err != nil {
+			return next, errors.Wrap(err, "saving checkpoint")
+		}
+	}
+	return next, nil
+}
+
+// postMatch posts a match, encoded as JSON, to the webhook
+func postMatch(url string, b []byte) error {
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned status %v", resp.Status)
+	}
+	return nil
+}
+
+func loadMonitorCheckpoints(path string) (monitorCheckpoints, error) {
+	checkpoints := monitorCheckpoints{}
+	b, err := ioutil.ReadFile(filepath.Clean(path))
+	if os.IsNotExist(err) {
+		return checkpoints, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "reading checkpoint")
+	}
+	if err := json.Unmarshal(b, &checkpoints); err != nil {
+		return nil, errors.Wrap(err, "parsing checkpoint")
+	}
+	return checkpoints, nil
+}
+
+// saveMonitorCheckpoints writes the checkpoints to a temporary file and renames it, so that the checkpoints are
+// never lost to a partial write
+func saveMonitorCheckpoints(path string, checkpoints monitorCheckpoints) error {
+	b, err := json.Marshal(checkpoints)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, b, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func init() {
+	monitorCmd.Flags().StringSlice("watch-key", []string{}, "public key files, in the format given by --pki-format, to report entries signed with")
+	monitorCmd.Flags().Var(&pkiFormatFlag{value: "pgp"}, "pki-format", "format of the watched public keys")
+	monitorCmd.Flags().StringSlice("watch-fingerprint", []string{}, "hex encoded SHA-256 fingerprints of public keys to report entries signed with; either of the key as stored in entries, or of its DER encoded SubjectPublicKeyInfo")
+	monitorCmd.Flags().StringSlice("watch-identity", []string{}, "identities, such as certificate email addresses, URIs and common names or PGP user IDs, to report entries signed by")
+	monitorCmd.Flags().String("checkpoint", "", "file that the index of the next entry to check is kept in (default is $HOME/.rekor/monitor.json)")
+	monitorCmd.Flags().Int64("start-index", -1, "index to start checking from if there is no checkpoint for the log (default the current end of the log)")
+	monitorCmd.Flags().Int64("batch-size", 10, "number of entries to fetch in each request")
+	monitorCmd.Flags().Duration("interval", 0, "interval to poll the log at; if zero, the entries are checked once")
+	monitorCmd.Flags().String("webhook-url", "", "URL that each match is posted to as JSON")
+	rootCmd.AddCommand(monitorCmd)
+}
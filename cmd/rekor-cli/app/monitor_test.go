@@ -0,0 +1,128 @@
+//
+// Copyright 2021 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/go-openapi/swag"
+
+	"github.com/sigstore/rekor/pkg/generated/client"
+	"github.com/sigstore/rekor/pkg/generated/client/entries"
+	"github.com/sigstore/rekor/pkg/generated/client/tlog"
+	"github.com/sigstore/rekor/pkg/generated/models"
+	"github.com/sigstore/rekor/pkg/monitor"
+)
+
+// stubLogInfo serves the log info given
+type stubLogInfo struct {
+	tlog.ClientService
+	logInfo *models.LogInfo
+}
+
+func (s *stubLogInfo) GetLogInfo(params *tlog.GetLogInfoParams, opts ...tlog.ClientOption) (*tlog.GetLogInfoOK, error) {
+	return &tlog.GetLogInfoOK{Payload: s.logInfo}, nil
+}
+
+// stubEntries records the indexes of the entries searched for, and returns an entry for each
+type stubEntries struct {
+	entries.ClientService
+	searched []int64
+}
+
+func (s *stubEntries) SearchLogQuery(params *entries.SearchLogQueryParams, opts ...entries.ClientOption) (*entries.SearchLogQueryOK, error) {
+	var payload []models.LogEntry
+	for _, i := range params.Entry.LogIndexes {
+		s.searched = append(s.searched, *i)
+		payload = append(payload, models.LogEntry{fmt.Sprint(*i): models.LogEntryAnon{LogIndex: swag.Int64(*i), Body: "e30="}})
+	}
+	return &entries.SearchLogQueryOK{Payload: payload}, nil
+}
+
+func TestMonitorEntriesSkipsUnusedIndexes(t *testing.T) {
+	logInfo := &models.LogInfo{
+		TreeSize:   swag.Int64(2),
+		StartIndex: 10,
+		InactiveShards: []*models.InactiveShardLogInfo{
+			{StartIndex: swag.Int64(0), TreeSize: swag.Int64(3)},
+		},
+	}
+	watchList, err := monitor.NewWatchList(nil, []string{"someone@example.com"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		next         int64
+		wantSearched []int64
+		wantNext     int64
+	}{
+		{name: "from the start", next: 0, wantSearched: []int64{0, 1, 2, 10, 11}, wantNext: 12},
+		{name: "from the end of the frozen shard", next: 3, wantSearched: []int64{10, 11}, wantNext: 12},
+		{name: "from the active shard", next: 11, wantSearched: []int64{11}, wantNext: 12},
+		{name: "at the end", next: 12, wantNext: 12},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubEntries{}
+			c := &client.Rekor{Tlog: &stubLogInfo{logInfo: logInfo}, Entries: stub}
+			var saved, reported []int64
+			report := func(m *monitor.Match) error {
+				reported = append(reported, m.LogIndex)
+				return nil
+			}
+			save := func(next int64) error {
+				saved = append(saved, next)
+				return nil
+			}
+			next, err := monitorEntries(c, watchList, tt.next, 2, report, save)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if next != tt.wantNext {
+				t.Errorf("next = %d, want %d", next, tt.wantNext)
+			}
+			if !reflect.DeepEqual(stub.searched, tt.wantSearched) {
+				t.Errorf("searched %v, want %v", stub.searched, tt.wantSearched)
+			}
+			// the entries are undecodable, and so are all reported
+			if !reflect.DeepEqual(reported, tt.wantSearched) {
+				t.Errorf("reported %v, want %v", reported, tt.wantSearched)
+			}
+			if len(saved) > 0 && saved[len(saved)-1] != tt.wantNext {
+				t.Errorf("last checkpoint saved = %d, want %d", saved[len(saved)-1], tt.wantNext)
+			}
+		})
+	}
+
+	// servers that do not report the start index continue the log at the end of the frozen shards
+	end, err := logEnd(&client.Rekor{Tlog: &stubLogInfo{logInfo: &models.LogInfo{
+		TreeSize:       swag.Int64(2),
+		InactiveShards: logInfo.InactiveShards,
+	}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if end != 5 {
+		t.Errorf("logEnd() = %d, want 5", end)
+	}
+	if end, _ := logEnd(&client.Rekor{Tlog: &stubLogInfo{logInfo: logInfo}}); end != 12 {
+		t.Errorf("logEnd() = %d, want 12", end)
+	}
+}
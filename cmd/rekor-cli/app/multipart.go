@@ -0,0 +1,181 @@
+//
+// Copyright 2021 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-openapi/swag"
+	"github.com/spf13/viper"
+
+	"github.com/sigstore/rekor/pkg/generated/models"
+	"github.com/sigstore/rekor/pkg/util"
+)
+
+// postMultipart sends the body to the API path as the named part of a multipart/form-data request, streaming
+// each of the attachments as a file part rather than including its content in the body. The request is sent with
+// the same transport as those of the generated client, and so to the log chosen with --log.
+func postMultipart(path string, query url.Values, bodyPart string, body interface{}, attachments util.Attachments) (*http.Response, error) {
+	bodyBytes, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := url.Parse(strings.TrimSuffix(viper.GetString("rekor_server"), "/") + path)
+	if err != nil {
+		return nil, err
+	}
+	u.RawQuery = query.Encode()
+
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	go func() {
+		pw.CloseWithError(writeParts(mw, bodyPart, bodyBytes, attachments))
+	}()
+
+	req, err := http.NewRequest("POST", u.String(), pr)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	req.Header.Set("Accept", "application/json")
+	c := &http.Client{Transport: newRekorRuntime(u, viper.GetString("log")).Transport}
+	return c.Do(req)
+}
+
+// responseError returns the error reported by the server in the response body
+func responseError(path string, resp *http.Response) error {
+	var apiErr models.Error
+	if err := json.NewDecoder(resp.Body).Decode(&apiErr); err != nil || apiErr.Message == "" {
+		return fmt.Errorf("unexpected response from server: %v", resp.Status)
+	}
+	return fmt.Errorf("[POST %v][%d] %v", path, resp.StatusCode, apiErr.Message)
+}
+
+// createLogEntryMultipart uploads the proposed entry along with the attachments it refers to
+func createLogEntryMultipart(pe models.ProposedEntry, attachments util.Attachments, async bool) (*uploadCmdOutput, error) {
+	const path = "/api/v1/log/entries"
+	query := url.Values{}
+	if async {
+		query.Set("async", "true")
+	}
+	resp, err := postMultipart(path, query, "entry", pe, attachments)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusCreated:
+		var logEntry models.LogEntry
+		if err := json.NewDecoder(resp.Body).Decode(&logEntry); err != nil {
+			return nil, fmt.Errorf("error parsing response: %w", err)
+		}
+		var newIndex int64
+		for _, entry := range logEntry {
+			newIndex = swag.Int64Value(entry.LogIndex)
+		}
+		return &uploadCmdOutput{
+			Location: resp.Header.Get("Location"),
+			Index:    newIndex,
+		}, nil
+	case http.StatusAccepted:
+		var queued models.QueuedLogEntry
+		if err := json.NewDecoder(resp.Body).Decode(&queued); err != nil {
+			return nil, fmt.Errorf("error parsing response: %w", err)
+		}
+		return queuedOutput(&queued), nil
+	case http.StatusConflict:
+		return &uploadCmdOutput{
+			Location:      resp.Header.Get("Location"),
+			AlreadyExists: true,
+		}, nil
+	}
+	return nil, responseError(path, resp)
+}
+
+// validateLogEntryMultipart checks the proposed entry along with the attachments it refers to
+func validateLogEntryMultipart(pe models.ProposedEntry, attachments util.Attachments) (*models.ValidationResult, error) {
+	const path = "/api/v1/log/entries/validate"
+	resp, err := postMultipart(path, nil, "entry", pe, attachments)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, responseError(path, resp)
+	}
+	var result models.ValidationResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("error parsing response: %w", err)
+	}
+	return &result, nil
+}
+
+// createLogEntriesMultipart uploads the proposed entries along with the attachments they refer to
+func createLogEntriesMultipart(pes []models.ProposedEntry, attachments util.Attachments) ([]*models.BatchEntryResult, error) {
+	const path = "/api/v1/log/entries/batch"
+	resp, err := postMultipart(path, nil, "entries", pes, attachments)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, responseError(path, resp)
+	}
+	var results []*models.BatchEntryResult
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		return nil, fmt.Errorf("error parsing response: %w", err)
+	}
+	return results, nil
+}
+
+func writeParts(mw *multipart.Writer, bodyPart string, bodyBytes []byte, attachments util.Attachments) error {
+	if err := mw.WriteField(bodyPart, string(bodyBytes)); err != nil {
+		return err
+	}
+	for name, path := range attachments {
+		if err := writeFilePart(mw, name, path); err != nil {
+			return err
+		}
+	}
+	return mw.Close()
+}
+
+func writeFilePart(mw *multipart.Writer, name, path string) error {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	part, err := mw.CreateFormFile(name, filepath.Base(path))
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(part, f)
+	return err
+}
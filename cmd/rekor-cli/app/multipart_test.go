@@ -0,0 +1,87 @@
+//
+// Copyright 2021 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+
+	"github.com/sigstore/rekor/pkg/util"
+)
+
+func TestPostMultipart(t *testing.T) {
+	attachment := filepath.Join(t.TempDir(), "artifact")
+	if err := ioutil.WriteFile(attachment, []byte("artifact"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var got *http.Request
+	var gotAttachment string
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		f, _, err := r.FormFile("artifact")
+		if err != nil {
+			t.Errorf("reading attachment: %v", err)
+		} else {
+			b, _ := ioutil.ReadAll(f)
+			gotAttachment = string(b)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer testServer.Close()
+	defer viper.Reset()
+
+	tests := []struct {
+		name       string
+		log        string
+		apiKey     string
+		wantPath   string
+		wantAPIKey string
+	}{
+		{name: "default log", wantPath: "/api/v1/log/entries"},
+		{name: "named log with API key", log: "internal", apiKey: "thisIsAnAPIKey", wantPath: "/api/v1/logs/internal/log/entries", wantAPIKey: "thisIsAnAPIKey"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Set("rekor_server", testServer.URL)
+			viper.Set("log", tt.log)
+			viper.Set("api-key", tt.apiKey)
+			resp, err := postMultipart("/api/v1/log/entries", url.Values{"async": {"true"}}, "entry", map[string]string{"kind": "rekord"}, util.Attachments{"artifact": attachment})
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if got.URL.Path != tt.wantPath {
+				t.Errorf("request sent to %v, want %v", got.URL.Path, tt.wantPath)
+			}
+			if apiKey := got.URL.Query().Get("apiKey"); apiKey != tt.wantAPIKey {
+				t.Errorf("API key %q sent, want %q", apiKey, tt.wantAPIKey)
+			}
+			if got.URL.Query().Get("async") != "true" {
+				t.Errorf("query parameters not sent: %v", got.URL.RawQuery)
+			}
+			if gotAttachment != "artifact" {
+				t.Errorf("attachment %q received", gotAttachment)
+			}
+		})
+	}
+}
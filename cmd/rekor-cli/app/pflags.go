@@ -17,16 +17,19 @@ package app
 
 import (
 	"context"
+	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 
 	"github.com/go-openapi/strfmt"
 	"github.com/go-openapi/swag"
@@ -34,19 +37,23 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/sigstore/rekor/pkg/generated/models"
+	"github.com/sigstore/rekor/pkg/pki"
+	_ "github.com/sigstore/rekor/pkg/pki/formats"
+	"github.com/sigstore/rekor/pkg/sharding"
 	jar_v001 "github.com/sigstore/rekor/pkg/types/jar/v0.0.1"
 	rekord_v001 "github.com/sigstore/rekor/pkg/types/rekord/v0.0.1"
 	rpm_v001 "github.com/sigstore/rekor/pkg/types/rpm/v0.0.1"
+	"github.com/sigstore/rekor/pkg/util"
 )
 
 func addSearchPFlags(cmd *cobra.Command) error {
-	cmd.Flags().Var(&pkiFormatFlag{value: "pgp"}, "pki-format", "format of the signature and/or public key")
+	cmd.Flags().Var(&pkiFormatFlag{value: "pgp"}, "pki-format", fmt.Sprintf("format of the signature and/or public key [%s]", strings.Join(pki.SupportedFormats(), ", ")))
 
 	cmd.Flags().Var(&fileOrURLFlag{}, "public-key", "path or URL to public key file")
 
 	cmd.Flags().Var(&fileOrURLFlag{}, "artifact", "path or URL to artifact file")
 
-	cmd.Flags().Var(&uuidFlag{}, "sha", "the SHA256 sum of the artifact")
+	cmd.Flags().Var(&shaFlag{}, "sha", "the SHA256 sum of the artifact")
 	return nil
 }
 
@@ -70,7 +77,7 @@ func validateSearchPFlags() error {
 func addArtifactPFlags(cmd *cobra.Command) error {
 	cmd.Flags().Var(&fileOrURLFlag{}, "signature", "path or URL to detached signature file")
 	cmd.Flags().Var(&typeFlag{value: "rekord"}, "type", "type of entry")
-	cmd.Flags().Var(&pkiFormatFlag{value: "pgp"}, "pki-format", "format of the signature and/or public key")
+	cmd.Flags().Var(&pkiFormatFlag{value: "pgp"}, "pki-format", fmt.Sprintf("format of the signature and/or public key [%s]", strings.Join(pki.SupportedFormats(), ", ")))
 
 	cmd.Flags().Var(&fileOrURLFlag{}, "public-key", "path or URL to public key file")
 
@@ -140,6 +147,43 @@ func validateArtifactPFlags(uuidValid, indexValid bool) error {
 	return nil
 }
 
+// uploadAttachments holds the local files that are sent as file parts of a multipart/form-data request
+// rather than being included in the proposed entry
+var uploadAttachments = util.Attachments{}
+
+// attachmentPrefix is prepended to the names of attachments, so that the attachments of several entries
+// uploaded in one request do not collide
+var attachmentPrefix string
+
+// attachLocalFile returns the URL that the entry should use to refer to the local file, along with the
+// SHA256 digest of its content, if the file is larger than the configured multipart threshold; otherwise
+// it returns an empty URL and the content should be included in the entry
+func attachLocalFile(name, path string) (strfmt.URI, string, error) {
+	threshold := viper.GetInt64("multipart-threshold")
+	if threshold <= 0 {
+		return "", "", nil
+	}
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return "", "", fmt.Errorf("error reading %v file: %w", name, err)
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return "", "", fmt.Errorf("error reading %v file: %w", name, err)
+	}
+	if fi.Size() <= threshold {
+		return "", "", nil
+	}
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, f); err != nil {
+		return "", "", fmt.Errorf("error reading %v file: %w", name, err)
+	}
+	uploadAttachments[attachmentPrefix+name] = path
+	return strfmt.URI(util.AttachmentURL(attachmentPrefix + name)), hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
 func CreateJarFromPFlags() (models.ProposedEntry, error) {
 	//TODO: how to select version of item to create
 	returnVal := models.Jar{}
@@ -178,6 +222,14 @@ func CreateJarFromPFlags() (models.ProposedEntry, error) {
 		dataURL, err := url.Parse(artifact)
 		if err == nil && dataURL.IsAbs() {
 			re.JARModel.Archive.URL = strfmt.URI(artifact)
+		} else if attachmentURL, digest, err := attachLocalFile("artifact", artifact); err != nil {
+			return nil, err
+		} else if attachmentURL != "" {
+			re.JARModel.Archive.URL = attachmentURL
+			re.JARModel.Archive.Hash = &models.JarV001SchemaArchiveHash{
+				Algorithm: swag.String(models.JarV001SchemaArchiveHashAlgorithmSha256),
+				Value:     swag.String(digest),
+			}
 		} else {
 			artifactBytes, err := ioutil.ReadFile(filepath.Clean(artifact))
 			if err != nil {
@@ -193,7 +245,7 @@ func CreateJarFromPFlags() (models.ProposedEntry, error) {
 		}
 
 		if re.HasExternalEntities() {
-			if err := re.FetchExternalEntities(context.Background()); err != nil {
+			if err := re.FetchExternalEntities(util.WithAttachments(context.Background(), uploadAttachments)); err != nil {
 				return nil, fmt.Errorf("error retrieving external entities: %v", err)
 			}
 		}
@@ -243,6 +295,14 @@ func CreateRpmFromPFlags() (models.ProposedEntry, error) {
 		dataURL, err := url.Parse(artifact)
 		if err == nil && dataURL.IsAbs() {
 			re.RPMModel.Package.URL = strfmt.URI(artifact)
+		} else if attachmentURL, digest, err := attachLocalFile("artifact", artifact); err != nil {
+			return nil, err
+		} else if attachmentURL != "" {
+			re.RPMModel.Package.URL = attachmentURL
+			re.RPMModel.Package.Hash = &models.RpmV001SchemaPackageHash{
+				Algorithm: swag.String(models.RpmV001SchemaPackageHashAlgorithmSha256),
+				Value:     swag.String(digest),
+			}
 		} else {
 			artifactBytes, err := ioutil.ReadFile(filepath.Clean(artifact))
 			if err != nil {
@@ -256,6 +316,10 @@ func CreateRpmFromPFlags() (models.ProposedEntry, error) {
 		keyURL, err := url.Parse(publicKey)
 		if err == nil && keyURL.IsAbs() {
 			re.RPMModel.PublicKey.URL = strfmt.URI(publicKey)
+		} else if attachmentURL, _, err := attachLocalFile("publicKey", publicKey); err != nil {
+			return nil, err
+		} else if attachmentURL != "" {
+			re.RPMModel.PublicKey.URL = attachmentURL
 		} else {
 			keyBytes, err := ioutil.ReadFile(filepath.Clean(publicKey))
 			if err != nil {
@@ -269,7 +333,7 @@ func CreateRpmFromPFlags() (models.ProposedEntry, error) {
 		}
 
 		if re.HasExternalEntities() {
-			if err := re.FetchExternalEntities(context.Background()); err != nil {
+			if err := re.FetchExternalEntities(util.WithAttachments(context.Background(), uploadAttachments)); err != nil {
 				return nil, fmt.Errorf("error retrieving external entities: %v", err)
 			}
 		}
@@ -318,6 +382,14 @@ func CreateRekordFromPFlags() (models.ProposedEntry, error) {
 		dataURL, err := url.Parse(artifact)
 		if err == nil && dataURL.IsAbs() {
 			re.RekordObj.Data.URL = strfmt.URI(artifact)
+		} else if attachmentURL, digest, err := attachLocalFile("artifact", artifact); err != nil {
+			return nil, err
+		} else if attachmentURL != "" {
+			re.RekordObj.Data.URL = attachmentURL
+			re.RekordObj.Data.Hash = &models.RekordV001SchemaDataHash{
+				Algorithm: swag.String(models.RekordV001SchemaDataHashAlgorithmSha256),
+				Value:     swag.String(digest),
+			}
 		} else {
 			artifactBytes, err := ioutil.ReadFile(filepath.Clean(artifact))
 			if err != nil {
@@ -327,21 +399,15 @@ func CreateRekordFromPFlags() (models.ProposedEntry, error) {
 		}
 
 		re.RekordObj.Signature = &models.RekordV001SchemaSignature{}
-		pkiFormat := viper.GetString("pki-format")
-		switch pkiFormat {
-		case "pgp":
-			re.RekordObj.Signature.Format = models.RekordV001SchemaSignatureFormatPgp
-		case "minisign":
-			re.RekordObj.Signature.Format = models.RekordV001SchemaSignatureFormatMinisign
-		case "x509":
-			re.RekordObj.Signature.Format = models.RekordV001SchemaSignatureFormatX509
-		case "ssh":
-			re.RekordObj.Signature.Format = models.RekordV001SchemaSignatureFormatSSH
-		}
+		re.RekordObj.Signature.Format = viper.GetString("pki-format")
 		signature := viper.GetString("signature")
 		sigURL, err := url.Parse(signature)
 		if err == nil && sigURL.IsAbs() {
 			re.RekordObj.Signature.URL = strfmt.URI(signature)
+		} else if attachmentURL, _, err := attachLocalFile("signature", signature); err != nil {
+			return nil, err
+		} else if attachmentURL != "" {
+			re.RekordObj.Signature.URL = attachmentURL
 		} else {
 			signatureBytes, err := ioutil.ReadFile(filepath.Clean(signature))
 			if err != nil {
@@ -355,6 +421,10 @@ func CreateRekordFromPFlags() (models.ProposedEntry, error) {
 		keyURL, err := url.Parse(publicKey)
 		if err == nil && keyURL.IsAbs() {
 			re.RekordObj.Signature.PublicKey.URL = strfmt.URI(publicKey)
+		} else if attachmentURL, _, err := attachLocalFile("publicKey", publicKey); err != nil {
+			return nil, err
+		} else if attachmentURL != "" {
+			re.RekordObj.Signature.PublicKey.URL = attachmentURL
 		} else {
 			keyBytes, err := ioutil.ReadFile(filepath.Clean(publicKey))
 			if err != nil {
@@ -368,7 +438,7 @@ func CreateRekordFromPFlags() (models.ProposedEntry, error) {
 		}
 
 		if re.HasExternalEntities() {
-			if err := re.FetchExternalEntities(context.Background()); err != nil {
+			if err := re.FetchExternalEntities(util.WithAttachments(context.Background(), uploadAttachments)); err != nil {
 				return nil, fmt.Errorf("error retrieving external entities: %v", err)
 			}
 		}
@@ -446,28 +516,22 @@ func (f *pkiFormatFlag) String() string {
 }
 
 func (f *pkiFormatFlag) Set(s string) error {
-	set := map[string]struct{}{
-		"pgp":      {},
-		"minisign": {},
-		"x509":     {},
-		"ssh":      {},
-	}
-	if _, ok := set[s]; ok {
+	if _, ok := pki.GetFormat(s); ok {
 		f.value = s
 		return nil
 	}
-	return fmt.Errorf("value specified is invalid: [%s] supported values are: [pgp, minisign, x509, ssh]", s)
+	return fmt.Errorf("value specified is invalid: [%s] supported values are: [%s]", s, strings.Join(pki.SupportedFormats(), ", "))
 }
 
-type uuidFlag struct {
+type shaFlag struct {
 	hash string
 }
 
-func (u *uuidFlag) String() string {
-	return u.hash
+func (s *shaFlag) String() string {
+	return s.hash
 }
 
-func (u *uuidFlag) Set(v string) error {
+func (s *shaFlag) Set(v string) error {
 	if v == "" {
 		return errors.New("flag must be specified")
 	}
@@ -477,6 +541,30 @@ func (u *uuidFlag) Set(v string) error {
 		}
 		return fmt.Errorf("value specified is invalid: %w", err)
 	}
+	s.hash = v
+	return nil
+}
+
+func (s *shaFlag) Type() string {
+	return "sha"
+}
+
+// uuidFlag is the UUID of an entry, optionally prefixed with the ID of the tree of the shard holding it
+type uuidFlag struct {
+	hash string
+}
+
+func (u *uuidFlag) String() string {
+	return u.hash
+}
+
+func (u *uuidFlag) Set(v string) error {
+	if v == "" {
+		return errors.New("flag must be specified")
+	}
+	if _, _, err := sharding.ParseEntryID(v); err != nil {
+		return fmt.Errorf("value specified is invalid: %w", err)
+	}
 	u.hash = v
 	return nil
 }
@@ -246,6 +246,13 @@ func TestArtifactPFlags(t *testing.T) {
 			expectParseSuccess:    true,
 			expectValidateSuccess: true,
 		},
+		{
+			caseDesc:              "valid uuid with tree id",
+			uuid:                  "00000000000004d23030303030303030303030303030303030303030303030303030303030303030",
+			uuidRequired:          true,
+			expectParseSuccess:    true,
+			expectValidateSuccess: true,
+		},
 		{
 			caseDesc:              "invalid uuid",
 			uuid:                  "not_a_uuid",
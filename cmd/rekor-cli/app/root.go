@@ -18,6 +18,7 @@ package app
 import (
 	"errors"
 	"fmt"
+	"net/http"
 	"net/url"
 	"os"
 	"strings"
@@ -59,6 +60,7 @@ func init() {
 	rootCmd.PersistentFlags().Var(&formatFlag{format: "default"}, "format", "Command output format")
 
 	rootCmd.PersistentFlags().String("api-key", "", "API key for api.rekor.dev")
+	rootCmd.PersistentFlags().String("log", "", "name of the log to use on a server that hosts several (default the server's default log)")
 
 	// these are bound here and not in PreRun so that all child commands can use them
 	if err := viper.BindPFlags(rootCmd.PersistentFlags()); err != nil {
@@ -115,20 +117,76 @@ func initConfig(cmd *cobra.Command) error {
 }
 
 func GetRekorClient(rekorServerURL string) (*client.Rekor, error) {
+	return GetRekorClientForLog(rekorServerURL, viper.GetString("log"))
+}
+
+// GetRekorClientForLog returns a client for the log with the name given on the server, or for the default log of
+// the server if the name is empty
+func GetRekorClientForLog(rekorServerURL, name string) (*client.Rekor, error) {
 	url, err := url.Parse(rekorServerURL)
 	if err != nil {
 		return nil, err
 	}
+	return client.New(newRekorRuntime(url, name), strfmt.Default), nil
+}
 
+// newRekorRuntime returns the runtime that the client for the log with the name given sends requests with;
+// requests made other than with the generated client must be sent with its transport too
+func newRekorRuntime(url *url.URL, name string) *httptransport.Runtime {
 	rt := httptransport.New(url.Host, client.DefaultBasePath, []string{url.Scheme})
 	rt.Consumers["application/yaml"] = util.YamlConsumer()
 	rt.Consumers["application/x-pem-file"] = runtime.TextConsumer()
 	rt.Producers["application/yaml"] = util.YamlProducer()
+	rt.Transport = rekorTransport(rt.Transport, name)
+	return rt
+}
+
+// rekorTransport wraps the transport that requests to the server are sent with, so that the API key is sent with
+// each request and requests are sent to the paths of the log with the name given
+func rekorTransport(next http.RoundTripper, name string) http.RoundTripper {
+	if apiKey := viper.GetString("api-key"); apiKey != "" {
+		next = &apiKeyTransport{next: next, apiKey: apiKey}
+	}
+	if name != "" {
+		next = &namedLogTransport{next: next, name: name}
+	}
+	return next
+}
 
-	if viper.GetString("api-key") != "" {
-		rt.DefaultAuthentication = httptransport.APIKeyAuth("apiKey", "query", viper.GetString("api-key"))
+// apiKeyTransport adds the API key to the query of each request
+type apiKeyTransport struct {
+	next   http.RoundTripper
+	apiKey string
+}
+
+func (t *apiKeyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	query := req.URL.Query()
+	query.Set("apiKey", t.apiKey)
+	req.URL.RawQuery = query.Encode()
+	return t.next.RoundTrip(req)
+}
+
+// logPath returns the path of an operation on the log with the name given, or on the default log if the name is
+// empty
+func logPath(name, path string) string {
+	if name == "" || !strings.HasPrefix(path, "/api/v1/") {
+		return path
 	}
-	return client.New(rt, strfmt.Default), nil
+	return "/api/v1/logs/" + url.PathEscape(name) + strings.TrimPrefix(path, "/api/v1")
+}
+
+// namedLogTransport sends each request to the path of the same operation on the named log
+type namedLogTransport struct {
+	next http.RoundTripper
+	name string
+}
+
+func (t *namedLogTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Path = logPath(t.name, req.URL.Path)
+	req.URL.RawPath = ""
+	return t.next.RoundTrip(req)
 }
 
 type urlFlag struct {
@@ -35,6 +35,7 @@ import (
 	"github.com/sigstore/rekor/pkg/generated/client/index"
 	"github.com/sigstore/rekor/pkg/generated/models"
 	"github.com/sigstore/rekor/pkg/log"
+	"github.com/sigstore/rekor/pkg/pki"
 )
 
 type searchCmdOutput struct {
@@ -123,18 +124,10 @@ var searchCmd = &cobra.Command{
 		if publicKeyStr != "" {
 			params.Query.PublicKey = &models.SearchIndexPublicKey{}
 			pkiFormat := viper.GetString("pki-format")
-			switch pkiFormat {
-			case "pgp":
-				params.Query.PublicKey.Format = swag.String(models.SearchIndexPublicKeyFormatPgp)
-			case "minisign":
-				params.Query.PublicKey.Format = swag.String(models.SearchIndexPublicKeyFormatMinisign)
-			case "x509":
-				params.Query.PublicKey.Format = swag.String(models.SearchIndexPublicKeyFormatX509)
-			case "ssh":
-				params.Query.PublicKey.Format = swag.String(models.SearchIndexPublicKeyFormatSSH)
-			default:
-				return nil, fmt.Errorf("unknown pki-format %v", pkiFormat)
+			if _, found := pki.GetFormat(pkiFormat); !found {
+				return nil, fmt.Errorf("unknown pki-format %v; supported formats are %v", pkiFormat, pki.SupportedFormats())
 			}
+			params.Query.PublicKey.Format = swag.String(pkiFormat)
 			publicKey := fileOrURLFlag{}
 			if err := publicKey.Set(publicKeyStr); err != nil {
 				return nil, err
@@ -0,0 +1,93 @@
+//
+// Copyright 2021 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-openapi/swag"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/sigstore/rekor/cmd/rekor-cli/app/format"
+	"github.com/sigstore/rekor/pkg/generated/client/entries"
+	"github.com/sigstore/rekor/pkg/generated/models"
+	"github.com/sigstore/rekor/pkg/log"
+)
+
+type statusCmdOutput struct {
+	UUID           string
+	Status         string
+	QueuedTime     int64  `json:",omitempty"`
+	LogIndex       *int64 `json:",omitempty"`
+	IntegratedTime int64  `json:",omitempty"`
+}
+
+func (s *statusCmdOutput) String() string {
+	str := fmt.Sprintf("UUID: %s\n", s.UUID)
+	str += fmt.Sprintf("Status: %s\n", s.Status)
+	if s.QueuedTime != 0 {
+		str += fmt.Sprintf("QueuedTime: %s\n", time.Unix(s.QueuedTime, 0).UTC().Format(time.RFC3339))
+	}
+	if s.Status == models.LogEntryStatusStatusIntegrated {
+		str += fmt.Sprintf("Index: %d\n", swag.Int64Value(s.LogIndex))
+		str += fmt.Sprintf("IntegratedTime: %s\n", time.Unix(s.IntegratedTime, 0).UTC().Format(time.RFC3339))
+	}
+	return str
+}
+
+// statusCmd represents the status command
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Rekor status command",
+	Long:  `Get whether an entry uploaded with --async is still queued or has been included in the transparency log`,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		// these are bound here so that they are not overwritten by other commands
+		if err := viper.BindPFlags(cmd.Flags()); err != nil {
+			log.Logger.Fatal("Error initializing cmd line args: ", err)
+		}
+	},
+	Run: format.WrapCmd(func(args []string) (interface{}, error) {
+		rekorClient, err := GetRekorClient(viper.GetString("rekor_server"))
+		if err != nil {
+			return nil, err
+		}
+
+		params := entries.NewGetLogEntryStatusParams()
+		params.EntryUUID = viper.GetString("uuid")
+		resp, err := rekorClient.Entries.GetLogEntryStatus(params)
+		if err != nil {
+			return nil, err
+		}
+
+		return &statusCmdOutput{
+			UUID:           swag.StringValue(resp.Payload.UUID),
+			Status:         swag.StringValue(resp.Payload.Status),
+			QueuedTime:     resp.Payload.QueuedTime,
+			LogIndex:       resp.Payload.LogIndex,
+			IntegratedTime: resp.Payload.IntegratedTime,
+		}, nil
+	}),
+}
+
+func init() {
+	if err := addUUIDPFlags(statusCmd, true); err != nil {
+		log.Logger.Fatal("Error parsing cmd line args:", err)
+	}
+
+	rootCmd.AddCommand(statusCmd)
+}
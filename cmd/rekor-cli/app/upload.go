@@ -19,12 +19,14 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/go-openapi/swag"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
 	"github.com/sigstore/rekor/cmd/rekor-cli/app/format"
+	"github.com/sigstore/rekor/pkg/generated/client"
 	"github.com/sigstore/rekor/pkg/generated/client/entries"
 	"github.com/sigstore/rekor/pkg/generated/models"
 	"github.com/sigstore/rekor/pkg/log"
@@ -34,9 +36,21 @@ type uploadCmdOutput struct {
 	AlreadyExists bool
 	Location      string
 	Index         int64
+	DryRun        bool                     `json:",omitempty"`
+	Queued        bool                     `json:",omitempty"`
+	UUID          string                   `json:",omitempty"`
+	IndexKeys     []string                 `json:",omitempty"`
+	QueuedTime    int64                    `json:",omitempty"`
+	Promise       *models.InclusionPromise `json:",omitempty"`
 }
 
 func (u *uploadCmdOutput) String() string {
+	if u.Queued {
+		return fmt.Sprintf("Entry queued with UUID %v; its status is available at: %v%v\n", u.UUID, viper.GetString("rekor_server"), u.Location)
+	}
+	if u.DryRun {
+		return fmt.Sprintf("Entry would be accepted with UUID %v; it was not uploaded\n", u.UUID)
+	}
 	if u.AlreadyExists {
 		return fmt.Sprintf("Entry already exists; available at: %v%v\n", viper.GetString("rekor_server"), u.Location)
 	}
@@ -52,44 +66,53 @@ var uploadCmd = &cobra.Command{
 		if err := viper.BindPFlags(cmd.Flags()); err != nil {
 			log.Logger.Fatal("Error initializing cmd line args: ", err)
 		}
+		if viper.GetString("batch") != "" {
+			if viper.GetBool("dry-run") || viper.GetBool("async") {
+				log.Logger.Error("--dry-run and --async cannot be used with --batch")
+				_ = cmd.Help()
+				os.Exit(1)
+			}
+			return
+		}
 		if err := validateArtifactPFlags(false, false); err != nil {
 			log.Logger.Error(err)
 			_ = cmd.Help()
 			os.Exit(1)
 		}
 	},
-	Long: `This command takes the public key, signature and URL of the release artifact and uploads it to the rekor server.`,
+	Long: `This command takes the public key, signature and URL of the release artifact and uploads it to the rekor server.
+
+With --batch, the entries described in a JSON manifest are uploaded together. The manifest is an array of objects
+with the keys type, entry, artifact, signature, public-key and pki-format, which have the same meaning as the
+flags of the same name; relative paths are resolved against the directory containing the manifest.`,
 	Run: format.WrapCmd(func(args []string) (interface{}, error) {
 		rekorClient, err := GetRekorClient(viper.GetString("rekor_server"))
 		if err != nil {
 			return nil, err
 		}
+		if manifest := viper.GetString("batch"); manifest != "" {
+			return uploadBatch(rekorClient, manifest)
+		}
 		params := entries.NewCreateLogEntryParams()
 
-		var entry models.ProposedEntry
-		switch viper.GetString("type") {
-		case "rekord":
-			entry, err = CreateRekordFromPFlags()
-			if err != nil {
-				return nil, err
-			}
-		case "rpm":
-			entry, err = CreateRpmFromPFlags()
-			if err != nil {
-				return nil, err
-			}
-		case "jar":
-			entry, err = CreateJarFromPFlags()
-			if err != nil {
-				return nil, err
-			}
-		default:
-			return nil, errors.New("unknown type specified")
+		entry, err := createEntryFromPFlags()
+		if err != nil {
+			return nil, err
+		}
+
+		if viper.GetBool("dry-run") {
+			return validateEntry(rekorClient, entry)
+		}
+
+		async := viper.GetBool("async")
+		if len(uploadAttachments) > 0 {
+			return createLogEntryMultipart(entry, uploadAttachments, async)
 		}
 
 		params.SetProposedEntry(entry)
+		params.SetAsync(swag.Bool(async))
 
-		resp, err := rekorClient.Entries.CreateLogEntry(params)
+		resp, accepted, err := rekorClient.Entries.CreateLogEntry(params)
 		if err != nil {
 			switch e := err.(type) {
 			case *entries.CreateLogEntryConflict:
@@ -101,6 +124,9 @@ var uploadCmd = &cobra.Command{
 				return nil, err
 			}
 		}
+		if accepted != nil {
+			return queuedOutput(accepted.Payload), nil
+		}
 
 		var newIndex int64
 		for _, entry := range resp.Payload {
@@ -114,10 +140,72 @@ var uploadCmd = &cobra.Command{
 	}),
 }
 
+// queuedOutput describes an entry that the server has queued for inclusion in the log
+func queuedOutput(queued *models.QueuedLogEntry) *uploadCmdOutput {
+	return &uploadCmdOutput{
+		Queued:     true,
+		UUID:       swag.StringValue(queued.UUID),
+		Location:   queued.StatusURL.String(),
+		QueuedTime: swag.Int64Value(queued.QueuedTime),
+		Promise:    queued.Promise,
+	}
+}
+
+// createEntryFromPFlags creates a proposed entry of the type specified by the flags
+func createEntryFromPFlags() (models.ProposedEntry, error) {
+	switch viper.GetString("type") {
+	case "rekord":
+		return CreateRekordFromPFlags()
+	case "rpm":
+		return CreateRpmFromPFlags()
+	case "jar":
+		return CreateJarFromPFlags()
+	default:
+		return nil, errors.New("unknown type specified")
+	}
+}
+
+// validateEntry asks the server to check the proposed entry without adding it to the log
+func validateEntry(rekorClient *client.Rekor, pe models.ProposedEntry) (*uploadCmdOutput, error) {
+	var result *models.ValidationResult
+	if len(uploadAttachments) > 0 {
+		var err error
+		if result, err = validateLogEntryMultipart(pe, uploadAttachments); err != nil {
+			return nil, err
+		}
+	} else {
+		params := entries.NewValidateLogEntryParams()
+		params.SetProposedEntry(pe)
+		resp, err := rekorClient.Entries.ValidateLogEntry(params)
+		if err != nil {
+			return nil, err
+		}
+		result = resp.Payload
+	}
+
+	if !swag.BoolValue(result.Valid) {
+		var reasons []string
+		for _, e := range result.Errors {
+			reasons = append(reasons, fmt.Sprintf("%v: %v", swag.StringValue(e.Stage), swag.StringValue(e.Message)))
+		}
+		return nil, fmt.Errorf("entry would be rejected: %v", strings.Join(reasons, "; "))
+	}
+	return &uploadCmdOutput{
+		DryRun:    true,
+		UUID:      result.UUID,
+		IndexKeys: result.IndexKeys,
+	}, nil
+}
+
 func init() {
 	if err := addArtifactPFlags(uploadCmd); err != nil {
 		log.Logger.Fatal("Error parsing cmd line args:", err)
 	}
+	uploadCmd.Flags().Bool("dry-run", false, "check with the server that the entry would be accepted, without uploading it")
+	uploadCmd.Flags().Bool("async", false, "return once the server has queued the entry, rather than waiting for it to be included in the log; poll its status with 'rekor-cli status'")
+	uploadCmd.Flags().String("batch", "", "path to a JSON manifest describing many entries to upload at once")
+	uploadCmd.Flags().Int("batch-size", 100, "maximum number of entries from the batch manifest sent in each request")
+	uploadCmd.Flags().Int64("multipart-threshold", 1<<20, "local files larger than this size in bytes are streamed to the server as multipart/form-data parts rather than embedded in the entry; 0 disables")
 
 	rootCmd.AddCommand(uploadCmd)
 }
@@ -0,0 +1,181 @@
+//
+// Copyright 2021 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"path/filepath"
+
+	"github.com/go-openapi/swag"
+	"github.com/spf13/viper"
+
+	"github.com/sigstore/rekor/pkg/generated/client"
+	"github.com/sigstore/rekor/pkg/generated/client/entries"
+	"github.com/sigstore/rekor/pkg/generated/models"
+	"github.com/sigstore/rekor/pkg/util"
+)
+
+// batchManifestItem describes one of the entries in a batch manifest; each field has the same meaning as
+// the upload flag of the same name
+type batchManifestItem struct {
+	Type      string `json:"type"`
+	Entry     string `json:"entry"`
+	Artifact  string `json:"artifact"`
+	Signature string `json:"signature"`
+	PublicKey string `json:"public-key"`
+	PKIFormat string `json:"pki-format"`
+}
+
+type batchUploadResult struct {
+	Status   string
+	UUID     string `json:",omitempty"`
+	Location string `json:",omitempty"`
+	Index    *int64 `json:",omitempty"`
+	Error    string `json:",omitempty"`
+}
+
+type batchUploadCmdOutput struct {
+	Results []batchUploadResult
+}
+
+func (b *batchUploadCmdOutput) String() string {
+	str := ""
+	for i, r := range b.Results {
+		switch r.Status {
+		case models.BatchEntryResultStatusCreated:
+			str += fmt.Sprintf("%d: Created entry at index %d, available at: %v%v\n", i, swag.Int64Value(r.Index), viper.GetString("rekor_server"), r.Location)
+		case models.BatchEntryResultStatusConflict:
+			str += fmt.Sprintf("%d: Entry already exists; available at: %v%v\n", i, viper.GetString("rekor_server"), r.Location)
+		default:
+			str += fmt.Sprintf("%d: Entry was not created: %v\n", i, r.Error)
+		}
+	}
+	return str
+}
+
+// uploadBatch creates an entry for each item in the manifest, sending them to the server in batches
+func uploadBatch(rekorClient *client.Rekor, manifestPath string) (*batchUploadCmdOutput, error) {
+	manifestBytes, err := ioutil.ReadFile(filepath.Clean(manifestPath))
+	if err != nil {
+		return nil, fmt.Errorf("error reading batch manifest: %w", err)
+	}
+	var items []batchManifestItem
+	if err := json.Unmarshal(manifestBytes, &items); err != nil {
+		return nil, fmt.Errorf("error parsing batch manifest: %w", err)
+	}
+	if len(items) == 0 {
+		return nil, errors.New("batch manifest contains no entries")
+	}
+
+	dir := filepath.Dir(manifestPath)
+	proposedEntries := make([]models.ProposedEntry, len(items))
+	itemAttachments := make([]util.Attachments, len(items))
+	for i, item := range items {
+		if item.Type == "" {
+			item.Type = "rekord"
+		}
+		if item.PKIFormat == "" {
+			item.PKIFormat = "pgp"
+		}
+		viper.Set("type", item.Type)
+		viper.Set("pki-format", item.PKIFormat)
+		viper.Set("entry", resolveManifestPath(dir, item.Entry))
+		viper.Set("artifact", resolveManifestPath(dir, item.Artifact))
+		viper.Set("signature", resolveManifestPath(dir, item.Signature))
+		viper.Set("public-key", resolveManifestPath(dir, item.PublicKey))
+		if err := validateArtifactPFlags(false, false); err != nil {
+			return nil, fmt.Errorf("batch manifest entry %d: %w", i, err)
+		}
+
+		uploadAttachments = util.Attachments{}
+		attachmentPrefix = fmt.Sprintf("%d-", i)
+		pe, err := createEntryFromPFlags()
+		if err != nil {
+			return nil, fmt.Errorf("batch manifest entry %d: %w", i, err)
+		}
+		proposedEntries[i] = pe
+		itemAttachments[i] = uploadAttachments
+	}
+
+	batchSize := viper.GetInt("batch-size")
+	if batchSize < 1 {
+		return nil, errors.New("--batch-size must be at least 1")
+	}
+	output := &batchUploadCmdOutput{}
+	for start := 0; start < len(proposedEntries); start += batchSize {
+		end := start + batchSize
+		if end > len(proposedEntries) {
+			end = len(proposedEntries)
+		}
+
+		attachments := util.Attachments{}
+		for _, a := range itemAttachments[start:end] {
+			for name, path := range a {
+				attachments[name] = path
+			}
+		}
+
+		var results []*models.BatchEntryResult
+		if len(attachments) > 0 {
+			if results, err = createLogEntriesMultipart(proposedEntries[start:end], attachments); err != nil {
+				return nil, err
+			}
+		} else {
+			params := entries.NewCreateLogEntriesParams()
+			params.SetProposedEntries(proposedEntries[start:end])
+			resp, err := rekorClient.Entries.CreateLogEntries(params)
+			if err != nil {
+				return nil, err
+			}
+			results = resp.Payload
+		}
+		if len(results) != end-start {
+			return nil, fmt.Errorf("expected %d results from server, got %d", end-start, len(results))
+		}
+
+		for _, r := range results {
+			result := batchUploadResult{
+				Status:   swag.StringValue(r.Status),
+				UUID:     r.UUID,
+				Location: r.Location.String(),
+			}
+			for _, e := range r.Entry {
+				result.Index = e.LogIndex
+			}
+			if r.Error != nil {
+				result.Error = r.Error.Message
+			}
+			output.Results = append(output.Results, result)
+		}
+	}
+	return output, nil
+}
+
+// resolveManifestPath returns the path relative to the directory containing the manifest, unless it is a URL
+// or an absolute path
+func resolveManifestPath(dir, path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	if u, err := url.Parse(path); err == nil && u.IsAbs() {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
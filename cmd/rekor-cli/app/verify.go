@@ -31,6 +31,7 @@ import (
 	"github.com/sigstore/rekor/pkg/generated/client/entries"
 	"github.com/sigstore/rekor/pkg/generated/models"
 	"github.com/sigstore/rekor/pkg/log"
+	"github.com/sigstore/rekor/pkg/sharding"
 )
 
 type verifyCmdOutput struct {
@@ -137,7 +138,10 @@ var verifyCmd = &cobra.Command{
 		}
 		logEntry := resp.Payload[0]
 
+		// in a sharded log, the inclusion proof is within the tree of the shard holding the entry, at the entry's
+		// index in that tree
 		var o *verifyCmdOutput
+		var treeIndex int64
 		for k, v := range logEntry {
 			o = &verifyCmdOutput{
 				RootHash:  *v.InclusionProof.RootHash,
@@ -146,6 +150,7 @@ var verifyCmd = &cobra.Command{
 				Size:      *v.InclusionProof.TreeSize,
 				Hashes:    v.InclusionProof.Hashes,
 			}
+			treeIndex = *v.InclusionProof.LogIndex
 		}
 
 		hashes := [][]byte{}
@@ -155,10 +160,14 @@ var verifyCmd = &cobra.Command{
 		}
 
 		rootHash, _ := hex.DecodeString(o.RootHash)
-		leafHash, _ := hex.DecodeString(o.EntryUUID)
+		leafUUID, err := sharding.UUIDFromEntryID(o.EntryUUID)
+		if err != nil {
+			return nil, err
+		}
+		leafHash, _ := hex.DecodeString(leafUUID)
 
 		v := logverifier.New(rfc6962.DefaultHasher)
-		if err := v.VerifyInclusionProof(o.Index, o.Size, hashes, rootHash, leafHash); err != nil {
+		if err := v.VerifyInclusionProof(treeIndex, o.Size, hashes, rootHash, leafHash); err != nil {
 			return nil, err
 		}
 		return o, err
@@ -0,0 +1,408 @@
+//
+// Copyright 2021 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gocloud.dev/blob"
+	"gocloud.dev/gcerrors"
+
+	"github.com/sigstore/rekor/cmd/rekor-cli/app"
+	"github.com/sigstore/rekor/pkg/audit"
+	"github.com/sigstore/rekor/pkg/generated/client"
+	"github.com/sigstore/rekor/pkg/generated/client/entries"
+	"github.com/sigstore/rekor/pkg/generated/models"
+	"github.com/sigstore/rekor/pkg/log"
+	"github.com/sigstore/rekor/pkg/sharding"
+	"github.com/sigstore/rekor/pkg/verify"
+)
+
+// auditStateFile is the name of the file in audit.state_dir that the progress of the audit is kept in
+const auditStateFile = "audit_state.json"
+
+// auditFinding is an entry that failed the audit
+type auditFinding struct {
+	LogIndex int64
+	UUID     string
+	Reason   string
+	Time     time.Time
+}
+
+// auditState is the progress of the audit of a log; it is saved after each batch of entries so that the audit
+// resumes where it stopped
+type auditState struct {
+	// keyed by tree ID
+	Trees    map[string]audit.TreeState
+	Findings []auditFinding
+}
+
+// auditedShard is a tree of the log and the signed tree head that its rebuilt root is checked against
+type auditedShard struct {
+	treeID     string
+	startIndex int64
+	keyHint    []byte
+	logRoot    strfmt.Base64
+	signature  strfmt.Base64
+}
+
+// auditor rebuilds the trees of a log from their leaves
+type auditor struct {
+	client    *client.Rekor
+	keys      verify.LogKeys
+	artifacts audit.ArtifactFunc
+	batchSize int64
+	// entries whose artifacts were not available to check them with
+	unchecked int
+	state     auditState
+	statePath string
+}
+
+// auditCmd represents the audit command
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Audit every entry of a Rekor log by rebuilding its Merkle trees",
+	Long: `Download every leaf of the log in order and recompute the RFC 6962 leaf and node hashes locally, checking
+that the root hash of each tree matches its verified signed tree head. Each entry is also decoded and canonicalized
+again with the current type implementations, to check that the stored body is canonical and its signature valid;
+the artifacts that entries hold only the digest of are read from the audit.artifacts bucket, named by their hex
+encoded SHA-256 digest.
+
+The compact range of each tree is kept in audit.state_dir, so that a later audit only downloads the entries added
+since. Entries that fail the audit are logged and recorded in the state, and the command exits with an error.`,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		// these are bound here so that they are not overwritten by other commands
+		if err := viper.BindPFlags(cmd.Flags()); err != nil {
+			log.Logger.Fatal("Error initializing cmd line args: ", err)
+		}
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		log.ConfigureLogger(viper.GetString("log_type"))
+
+		// workaround for https://github.com/sigstore/rekor/issues/68
+		// from https://github.com/golang/glog/commit/fca8c8854093a154ff1eb580aae10276ad6b1b5f
+		_ = flag.CommandLine.Parse([]string{})
+
+		ctx := context.Background()
+		a, closeArtifacts, err := newAuditor(ctx)
+		if err != nil {
+			return err
+		}
+		defer closeArtifacts()
+
+		interval := viper.GetDuration("audit.interval")
+		for {
+			findings, err := a.auditLog(ctx)
+			if interval == 0 {
+				if err != nil {
+					return err
+				}
+				if findings > 0 {
+					return fmt.Errorf("%d entries failed the audit", findings)
+				}
+				if a.unchecked > 0 && viper.GetBool("audit.require_artifacts") {
+					return fmt.Errorf("%d entries could not be checked as their artifacts are not available", a.unchecked)
+				}
+				return nil
+			}
+			if err != nil {
+				log.Logger.Errorf("auditing log: %v", err)
+			}
+			time.Sleep(interval)
+		}
+	},
+}
+
+func init() {
+	auditCmd.Flags().String("audit.url", "", "URL of the Rekor server to audit (default is http://<rekor_server.address>:<rekor_server.port>)")
+	auditCmd.Flags().String("audit.log", "", "name of a log served under /api/v1/logs/{name} to audit, rather than the default log")
+	auditCmd.Flags().String("audit.public_key", "", "PEM file holding the keys that the log signs, or has signed, tree heads with (default is to fetch them from the log)")
+	auditCmd.Flags().String("audit.state_dir", ".", "directory that the progress of the audit is kept in")
+	auditCmd.Flags().String("audit.artifacts", "", "URL of a bucket holding the artifacts of entries, named by their hex encoded SHA-256 digest")
+	auditCmd.Flags().Bool("audit.require_artifacts", false, "fail the audit if the artifact of an entry is not available to check it with")
+	auditCmd.Flags().Int64("audit.batch_size", 100, "number of entries to fetch in each request")
+	auditCmd.Flags().Duration("audit.interval", 0, "interval to audit the entries added to the log at; if zero, the log is audited once")
+	rootCmd.AddCommand(auditCmd)
+}
+
+func newAuditor(ctx context.Context) (*auditor, func(), error) {
+	url := viper.GetString("audit.url")
+	if url == "" {
+		url = fmt.Sprintf("http://%s:%d", viper.GetString("rekor_server.address"), viper.GetUint("rekor_server.port"))
+	}
+	c, err := app.GetRekorClientForLog(url, viper.GetString("audit.log"))
+	if err != nil {
+		return nil, nil, err
+	}
+	a := &auditor{
+		client:    c,
+		batchSize: viper.GetInt64("audit.batch_size"),
+		statePath: filepath.Join(viper.GetString("audit.state_dir"), auditStateFile),
+	}
+	if a.batchSize < 1 {
+		return nil, nil, errors.New("audit.batch_size must be at least 1")
+	}
+
+	if path := viper.GetString("audit.public_key"); path != "" {
+		b, err := ioutil.ReadFile(filepath.Clean(path))
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "reading public key of log")
+		}
+		if a.keys, err = verify.ParseLogKeys(b); err != nil {
+			return nil, nil, errors.Wrap(err, "parsing public key of log")
+		}
+	} else if a.keys, err = fetchLogKeys(c); err != nil {
+		return nil, nil, err
+	}
+
+	closeArtifacts := func() {}
+	if bucketURL := viper.GetString("audit.artifacts"); bucketURL != "" {
+		bucket, err := blob.OpenBucket(ctx, bucketURL)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "opening artifacts bucket")
+		}
+		closeArtifacts = func() { bucket.Close() }
+		a.artifacts = func(ctx context.Context, digest string) ([]byte, error) {
+			b, err := bucket.ReadAll(ctx, digest)
+			if gcerrors.Code(err) == gcerrors.NotFound {
+				return nil, nil
+			}
+			return b, err
+		}
+	}
+
+	a.state = auditState{Trees: map[string]audit.TreeState{}}
+	b, err := ioutil.ReadFile(a.statePath)
+	switch {
+	case os.IsNotExist(err):
+	case err != nil:
+		closeArtifacts()
+		return nil, nil, errors.Wrap(err, "reading audit state")
+	default:
+		if err := json.Unmarshal(b, &a.state); err != nil {
+			closeArtifacts()
+			return nil, nil, errors.Wrap(err, "parsing audit state")
+		}
+		if a.state.Trees == nil {
+			a.state.Trees = map[string]audit.TreeState{}
+		}
+	}
+	return a, closeArtifacts, nil
+}
+
+// auditedShards returns the frozen shards of the log followed by its active shard, each with its signed tree head
+func auditedShards(logInfo *models.LogInfo) ([]auditedShard, error) {
+	var shards []auditedShard
+	var activeStart int64
+	for _, s := range logInfo.InactiveShards {
+		if s.SignedTreeHead == nil || s.SignedTreeHead.LogRoot == nil || s.SignedTreeHead.Signature == nil {
+			return nil, fmt.Errorf("inactive shard %v has no signed tree head", swag.StringValue(s.TreeID))
+		}
+		shard := auditedShard{
+			treeID:     swag.StringValue(s.TreeID),
+			startIndex: swag.Int64Value(s.StartIndex),
+			logRoot:    *s.SignedTreeHead.LogRoot,
+			signature:  *s.SignedTreeHead.Signature,
+		}
+		if s.SignedTreeHead.KeyHint != nil {
+			shard.keyHint = *s.SignedTreeHead.KeyHint
+		}
+		shards = append(shards, shard)
+		if end := shard.startIndex + swag.Int64Value(s.TreeSize); end > activeStart {
+			activeStart = end
+		}
+	}
+	// servers that do not report the start index of the active shard continue the log where the frozen shards end
+	if logInfo.StartIndex != 0 {
+		activeStart = logInfo.StartIndex
+	}
+	sth := logInfo.SignedTreeHead
+	if sth == nil || sth.LogRoot == nil || sth.Signature == nil {
+		return nil, errors.New("log info has no signed tree head")
+	}
+	if logInfo.TreeID == "" {
+		return nil, errors.New("log does not give the tree ID of its signed tree head")
+	}
+	active := auditedShard{
+		treeID:     logInfo.TreeID,
+		startIndex: activeStart,
+		logRoot:    *sth.LogRoot,
+		signature:  *sth.Signature,
+	}
+	if sth.KeyHint != nil {
+		active.keyHint = *sth.KeyHint
+	}
+	return append(shards, active), nil
+}
+
+// auditLog audits each tree of the log up to its current signed tree head, and returns the number of entries that
+// failed the audit
+func (a *auditor) auditLog(ctx context.Context) (int, error) {
+	a.unchecked = 0
+	li, err := a.client.Tlog.GetLogInfo(nil)
+	if err != nil {
+		return 0, errors.Wrap(err, "getting log info")
+	}
+	shards, err := auditedShards(li.Payload)
+	if err != nil {
+		return 0, err
+	}
+
+	findings := 0
+	for _, s := range shards {
+		n, err := a.auditShard(ctx, s)
+		findings += n
+		if err != nil {
+			return findings, fmt.Errorf("tree %v: %w", s.treeID, err)
+		}
+	}
+	return findings, nil
+}
+
+// auditShard appends the leaves added to the tree since the last audit to its compact range, checking each entry,
+// and then checks the root hash against the signed tree head; it returns the number of entries that failed the audit
+func (a *auditor) auditShard(ctx context.Context, s auditedShard) (int, error) {
+	lr, err := a.keys.SignedLogRoot(s.keyHint, s.logRoot, s.signature)
+	if err != nil {
+		return 0, errors.Wrap(err, "verifying signed tree head")
+	}
+	state, ok := a.state.Trees[s.treeID]
+	if !ok {
+		state.TreeID = s.treeID
+	}
+	if state.Size > lr.TreeSize {
+		return 0, fmt.Errorf("tree shrank from size %d, audited before, to %d", state.Size, lr.TreeSize)
+	}
+	tree, err := audit.NewTree(state)
+	if err != nil {
+		return 0, err
+	}
+
+	findings := 0
+	for tree.Size() < lr.TreeSize {
+		last := tree.Size() + uint64(a.batchSize)
+		if last > lr.TreeSize {
+			last = lr.TreeSize
+		}
+		n, err := a.auditEntries(ctx, tree, s.startIndex, last)
+		findings += n
+		if err != nil {
+			return findings, err
+		}
+		a.state.Trees[s.treeID] = tree.State()
+		if err := a.saveState(); err != nil {
+			return findings, err
+		}
+	}
+
+	if err := tree.VerifyRoot(lr); err != nil {
+		return findings, err
+	}
+	log.Logger.Infof("Rebuilt tree %v at size %d with root hash %x matching the signed tree head", s.treeID, lr.TreeSize, lr.RootHash)
+	return findings, nil
+}
+
+// auditEntries fetches the leaves of the tree up to the index given, appends them to the tree and checks each entry;
+// entries that fail the check are recorded as findings, and only errors that stop the audit are returned
+func (a *auditor) auditEntries(ctx context.Context, tree *audit.Tree, startIndex int64, last uint64) (int, error) {
+	query := &models.SearchLogQuery{}
+	for i := tree.Size(); i < last; i++ {
+		query.LogIndexes = append(query.LogIndexes, swag.Int64(startIndex+int64(i)))
+	}
+	params := entries.NewSearchLogQueryParams()
+	params.SetEntry(query)
+	resp, err := a.client.Entries.SearchLogQuery(params)
+	if err != nil {
+		return 0, errors.Wrap(err, "fetching entries")
+	}
+	type found struct {
+		uuid  string
+		entry models.LogEntryAnon
+	}
+	byIndex := map[int64]found{}
+	for _, logEntry := range resp.Payload {
+		for uuid, e := range logEntry {
+			if e.LogIndex != nil {
+				byIndex[*e.LogIndex] = found{uuid: uuid, entry: e}
+			}
+		}
+	}
+
+	findings := 0
+	for i := tree.Size(); i < last; i++ {
+		index := startIndex + int64(i)
+		f, ok := byIndex[index]
+		if !ok {
+			return findings, fmt.Errorf("entry %d was not returned by the log", index)
+		}
+		body, ok := f.entry.Body.(string)
+		if !ok {
+			return findings, fmt.Errorf("body of entry %d is not a string", index)
+		}
+		leaf, err := base64.StdEncoding.DecodeString(body)
+		if err != nil {
+			return findings, fmt.Errorf("decoding body of entry %d: %w", index, err)
+		}
+		leafHash, err := tree.AppendLeaf(leaf)
+		if err != nil {
+			return findings, err
+		}
+
+		reason := ""
+		if uuid, err := sharding.UUIDFromEntryID(f.uuid); err != nil || uuid != hex.EncodeToString(leafHash) {
+			reason = fmt.Sprintf("UUID is not the leaf hash %x", leafHash)
+		} else if err := audit.CheckEntry(ctx, leaf, a.artifacts); errors.Is(err, audit.ErrArtifactUnavailable) {
+			log.Logger.Warnf("Entry %d not checked: %v", index, err)
+			a.unchecked++
+		} else if err != nil {
+			reason = err.Error()
+		}
+		if reason != "" {
+			log.Logger.Errorf("Entry %d (%v) failed the audit: %v", index, f.uuid, reason)
+			a.state.Findings = append(a.state.Findings, auditFinding{LogIndex: index, UUID: f.uuid, Reason: reason, Time: time.Now().UTC()})
+			findings++
+		}
+	}
+	return findings, nil
+}
+
+// saveState writes the state of the audit to a temporary file and renames it, so that the state is never lost to a
+// partial write
+func (a *auditor) saveState() error {
+	b, err := json.Marshal(a.state)
+	if err != nil {
+		return err
+	}
+	tmp := a.statePath + ".tmp"
+	if err := ioutil.WriteFile(tmp, b, 0600); err != nil {
+		return errors.Wrap(err, "writing audit state")
+	}
+	return os.Rename(tmp, a.statePath)
+}
@@ -0,0 +1,75 @@
+//
+// Copyright 2021 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+
+	"github.com/sigstore/rekor/pkg/generated/models"
+)
+
+func TestAuditedShards(t *testing.T) {
+	lr, sig := strfmt.Base64("root"), strfmt.Base64("signature")
+	frozen := &models.InactiveShardLogInfo{
+		TreeID:         swag.String("1"),
+		StartIndex:     swag.Int64(0),
+		TreeSize:       swag.Int64(3),
+		SignedTreeHead: &models.InactiveShardLogInfoSignedTreeHead{LogRoot: &lr, Signature: &sig},
+	}
+	logInfo := func(startIndex int64) *models.LogInfo {
+		return &models.LogInfo{
+			TreeID:         "2",
+			TreeSize:       swag.Int64(2),
+			StartIndex:     startIndex,
+			InactiveShards: []*models.InactiveShardLogInfo{frozen},
+			SignedTreeHead: &models.LogInfoSignedTreeHead{LogRoot: &lr, Signature: &sig},
+		}
+	}
+
+	tests := []struct {
+		name            string
+		logInfo         *models.LogInfo
+		wantActiveStart int64
+		wantErr         bool
+	}{
+		{name: "active shard after unused indexes", logInfo: logInfo(10), wantActiveStart: 10},
+		{name: "start index not reported", logInfo: logInfo(0), wantActiveStart: 3},
+		{name: "no signed tree head", logInfo: &models.LogInfo{TreeID: "2"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shards, err := auditedShards(tt.logInfo)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("auditedShards() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if len(shards) != 2 {
+				t.Fatalf("got %d shards, want 2", len(shards))
+			}
+			if shards[0].treeID != "1" || shards[0].startIndex != 0 {
+				t.Errorf("unexpected frozen shard %+v", shards[0])
+			}
+			if shards[1].treeID != "2" || shards[1].startIndex != tt.wantActiveStart {
+				t.Errorf("active shard = %+v, want start index %d", shards[1], tt.wantActiveStart)
+			}
+		})
+	}
+}
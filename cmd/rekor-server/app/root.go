@@ -18,12 +18,15 @@ package app
 import (
 	"fmt"
 	"os"
+	"time"
 
 	homedir "github.com/mitchellh/go-homedir"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
 	"github.com/sigstore/rekor/pkg/log"
+	"github.com/sigstore/rekor/pkg/pki"
+	"github.com/sigstore/rekor/pkg/util"
 )
 
 var cfgFile string
@@ -56,11 +59,32 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.rekor-server.yaml)")
 	rootCmd.PersistentFlags().StringVar(&logType, "log_type", "dev", "logger type to use (dev/prod)")
 
+	rootCmd.PersistentFlags().String("log_backend", "trillian", "Merkle tree log that entries are stored in: [trillian, embedded]")
+	rootCmd.PersistentFlags().String("embedded_log.path", "rekor-log.db", "path to the file the log is kept in when log_backend is embedded")
 	rootCmd.PersistentFlags().String("trillian_log_server.address", "127.0.0.1", "Trillian log server address")
 	rootCmd.PersistentFlags().Uint16("trillian_log_server.port", 8091, "Trillian log server port")
 	rootCmd.PersistentFlags().Uint("trillian_log_server.tlog_id", 0, "Trillian tree id")
+	rootCmd.PersistentFlags().Bool("trillian_log_server.require_tlog_id", false, "refuse to start unless a tree id is configured for each log, rather than using an existing tree or creating one")
+	rootCmd.PersistentFlags().StringSlice("trillian_log_server.addresses", []string{}, "host:port addresses of several Trillian log servers to balance requests across; overrides trillian_log_server.address and trillian_log_server.port")
+	rootCmd.PersistentFlags().Duration("trillian_log_server.dial_timeout", 5*time.Second, "maximum time to wait for a connection to the Trillian log server at startup")
+	rootCmd.PersistentFlags().Bool("trillian_log_server.tls.enabled", false, "connect to the Trillian log server over TLS")
+	rootCmd.PersistentFlags().String("trillian_log_server.tls.ca_file", "", "path to PEM file of CA certificates used to verify the Trillian log server (default system roots)")
+	rootCmd.PersistentFlags().String("trillian_log_server.tls.cert_file", "", "path to PEM file of the client certificate presented to the Trillian log server, for mutual TLS")
+	rootCmd.PersistentFlags().String("trillian_log_server.tls.key_file", "", "path to PEM file of the private key for trillian_log_server.tls.cert_file")
+	rootCmd.PersistentFlags().String("trillian_log_server.tls.server_name", "", "name expected in the Trillian log server's certificate (default the host dialed)")
+	rootCmd.PersistentFlags().Duration("trillian_log_server.keepalive.time", 0, "interval at which the connection to the Trillian log server is pinged when idle (default no keepalive pings)")
+	rootCmd.PersistentFlags().Duration("trillian_log_server.keepalive.timeout", 20*time.Second, "time to wait for a reply to a keepalive ping before the connection is closed")
+	rootCmd.PersistentFlags().Bool("trillian_log_server.keepalive.permit_without_stream", false, "send keepalive pings even when there are no requests in flight")
+	rootCmd.PersistentFlags().Bool("trillian_log_server.health_check", true, "stop sending requests to Trillian log servers that report they are not serving via the gRPC health service")
+	rootCmd.PersistentFlags().Int("trillian_log_server.retry.max_attempts", 3, "maximum number of times a request to the Trillian log server is attempted when the server is unavailable or overloaded")
+	rootCmd.PersistentFlags().Duration("trillian_log_server.retry.initial_backoff", 100*time.Millisecond, "maximum time waited before the first retry of a request to the Trillian log server")
+	rootCmd.PersistentFlags().Duration("trillian_log_server.retry.max_backoff", 2*time.Second, "upper limit on the time waited before retrying a request to the Trillian log server")
+	rootCmd.PersistentFlags().Float64("trillian_log_server.retry.backoff_multiplier", 2, "factor the backoff grows by after each retry of a request to the Trillian log server")
+	rootCmd.PersistentFlags().Duration("trillian_log_server.retry_after", 5*time.Second, "time clients are asked to wait (in the Retry-After header) before retrying a request the Trillian log server was unavailable or too busy for")
 	rootCmd.PersistentFlags().String("rekor_server.address", "127.0.0.1", "Address to bind to")
-	rootCmd.PersistentFlags().String("rekor_server.signer", "memory", "Rekor signer to use. Current valid options include: [gcpkms, memory]")
+	rootCmd.PersistentFlags().String("rekor_server.signer", "memory", "Rekor signer to use. Current valid options include: [gcpkms, memory, file://<path to PEM encoded private key>]")
+	rootCmd.PersistentFlags().StringSlice("rekor_server.allowed_types", []string{}, "kinds of entries accepted by the default log (default all kinds)")
+	rootCmd.PersistentFlags().StringSlice("rekor_server.witness_keys", []string{}, "PEM files holding the public keys of witnesses whose cosignatures of tree heads the default log accepts")
 
 	rootCmd.PersistentFlags().Uint16("rekor_server.port", 3000, "Port to bind to")
 
@@ -68,6 +92,40 @@ func init() {
 	rootCmd.PersistentFlags().String("redis_server.address", "127.0.0.1", "Redis server address")
 	rootCmd.PersistentFlags().Uint16("redis_server.port", 6379, "Redis server port")
 
+	rootCmd.PersistentFlags().String("trust_roots.default", "", "path to PEM file of root certificates that certificate chains in entries of any type must terminate in; roots for a single type can be set with trust_roots.<type> in the config file")
+	rootCmd.PersistentFlags().String("trust_roots.untrusted_policy", string(pki.RejectUntrustedRoots), "action taken for entries whose certificate chains do not terminate in a trust root: [reject, flag]")
+	defaultPolicy := pki.DefaultPolicy()
+	rootCmd.PersistentFlags().Bool("policy.enabled", true, "reject key material and signatures that are not admitted by the policy; settings can be overridden for a single format with policy.<format>.* in the config file")
+	rootCmd.PersistentFlags().Int("policy.default.min_rsa_bits", defaultPolicy.MinRSABits, "minimum size of RSA keys, in bits")
+	rootCmd.PersistentFlags().StringSlice("policy.default.allowed_key_types", defaultPolicy.AllowedKeyTypes, "types of keys that are admitted: [rsa, ecdsa, ed25519, dsa]")
+	rootCmd.PersistentFlags().StringSlice("policy.default.allowed_curves", defaultPolicy.AllowedCurves, "elliptic curves that ECDSA keys may use")
+	rootCmd.PersistentFlags().StringSlice("policy.default.allowed_hashes", defaultPolicy.AllowedHashes, "hash algorithms that signatures may use")
+	defaultFetch := util.DefaultFetchConfig()
+	rootCmd.PersistentFlags().Duration("fetch.connect_timeout", defaultFetch.ConnectTimeout, "maximum time to connect to the server hosting an external entity referenced by URL")
+	rootCmd.PersistentFlags().Duration("fetch.read_timeout", defaultFetch.ReadTimeout, "maximum time to download an external entity referenced by URL")
+	rootCmd.PersistentFlags().Int64("fetch.max_body_size", defaultFetch.MaxBodySize, "maximum size in bytes of an external entity referenced by URL")
+	rootCmd.PersistentFlags().Int("fetch.max_redirects", defaultFetch.MaxRedirects, "maximum number of redirects followed when fetching an external entity")
+	rootCmd.PersistentFlags().StringSlice("fetch.allowed_schemes", defaultFetch.AllowedSchemes, "URL schemes that external entities may be fetched with")
+	rootCmd.PersistentFlags().StringSlice("fetch.allowed_hosts", defaultFetch.AllowedHosts, "hosts that external entities may be fetched from; *.example.com matches any subdomain (default any host)")
+	rootCmd.PersistentFlags().Bool("fetch.block_private_addresses", defaultFetch.BlockPrivateAddresses, "refuse to fetch external entities from loopback, private and link-local addresses")
+	defaultCache := util.DefaultFetchCacheConfig()
+	rootCmd.PersistentFlags().Int64("fetch.cache.max_memory_size", defaultCache.MaxMemorySize, "maximum size in bytes of fetched external entities cached in memory; 0 disables the cache")
+	rootCmd.PersistentFlags().Int64("fetch.cache.max_entry_size", defaultCache.MaxEntrySize, "external entities larger than this size in bytes are not cached")
+	rootCmd.PersistentFlags().Duration("fetch.cache.ttl", defaultCache.TTL, "how long an external entity without a known digest is served from the cache")
+	rootCmd.PersistentFlags().String("fetch.cache.dir", defaultCache.Dir, "directory where cached external entities evicted from memory are kept (default none)")
+	rootCmd.PersistentFlags().Int64("fetch.cache.max_disk_size", defaultCache.MaxDiskSize, "maximum size in bytes of external entities cached in fetch.cache.dir")
+	rootCmd.PersistentFlags().String("multipart.spool_dir", os.TempDir(), "directory where artifacts uploaded as multipart/form-data parts are held while the request is processed")
+	rootCmd.PersistentFlags().Int64("multipart.max_attachment_size", 128<<20, "maximum size in bytes of each part of a multipart/form-data request")
+	rootCmd.PersistentFlags().Int("multipart.max_attachments", 32, "maximum number of file parts in a multipart/form-data request")
+	rootCmd.PersistentFlags().Int("batch.max_entries", 1000, "maximum number of entries in a request to the batch endpoint")
+	rootCmd.PersistentFlags().Bool("async_create.enabled", true, "allow clients to request that the server respond once a new entry is queued, rather than once it is included in the log")
+	rootCmd.PersistentFlags().Bool("async_create.sign_promise", true, "include a signed promise of inclusion in responses for entries that have been queued")
+	rootCmd.PersistentFlags().Duration("async_create.pending_ttl", 24*time.Hour, "how long the status of an entry that has been queued but not yet included in the log is tracked")
+	rootCmd.PersistentFlags().Int("batch.concurrency", 16, "maximum number of entries in a batch that are canonicalized or queued to the log at once")
+	rootCmd.PersistentFlags().StringSlice("ssh.allowed_namespaces", []string{"file", "git"}, "namespaces that SSH signatures may be created in")
+	rootCmd.PersistentFlags().Bool("ssh.require_user_presence", true, "require SSH signatures made by FIDO security keys to assert user presence")
+	rootCmd.PersistentFlags().Bool("ssh.require_user_verification", false, "require SSH signatures made by FIDO security keys to assert user verification (e.g. PIN entry)")
+
 	if err := viper.BindPFlags(rootCmd.PersistentFlags()); err != nil {
 		log.Logger.Fatal(err)
 	}
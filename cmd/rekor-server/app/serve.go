@@ -28,6 +28,8 @@ import (
 	"github.com/sigstore/rekor/pkg/generated/restapi"
 	"github.com/sigstore/rekor/pkg/generated/restapi/operations"
 	"github.com/sigstore/rekor/pkg/log"
+	"github.com/sigstore/rekor/pkg/pki"
+	_ "github.com/sigstore/rekor/pkg/pki/formats"
 	"github.com/sigstore/rekor/pkg/types/jar"
 	jar_v001 "github.com/sigstore/rekor/pkg/types/jar/v0.0.1"
 	"github.com/sigstore/rekor/pkg/types/rekord"
@@ -73,6 +75,11 @@ var serveCmd = &cobra.Command{
 			log.Logger.Infof("Loading version '%v' for pluggable type '%v'", v, k)
 		}
 
+		// signature formats are registered by the init() methods of the packages imported above
+		for _, f := range pki.SupportedFormats() {
+			log.Logger.Infof("Loading support for signature format '%v'", f)
+		}
+
 		server.Host = viper.GetString("rekor_server.address")
 		server.Port = int(viper.GetUint("rekor_server.port"))
 		server.EnabledListeners = []string{"http"}
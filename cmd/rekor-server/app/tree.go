@@ -0,0 +1,289 @@
+//
+// Copyright 2021 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"bufio"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/trillian"
+	"github.com/google/trillian/types"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"google.golang.org/genproto/protobuf/field_mask"
+
+	"github.com/sigstore/rekor/pkg/api"
+	"github.com/sigstore/rekor/pkg/log"
+)
+
+// treeCmd groups the commands that administer the trees of the Trillian log server
+var treeCmd = &cobra.Command{
+	Use:   "tree",
+	Short: "Administer the trees of the Trillian log server",
+	Long: `Creates, inspects, freezes and deletes the trees of the Trillian log server that rekor-server
+is configured to connect to.`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		// these are bound here so that they are not overwritten by other commands
+		if err := viper.BindPFlags(cmd.Flags()); err != nil {
+			log.Logger.Fatal("Error initializing cmd line args: ", err)
+		}
+		log.ConfigureLogger(viper.GetString("log_type"))
+	},
+}
+
+var treeCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create and initialize a new log tree",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withTrillian(func(ctx context.Context, c *trillianClients) error {
+			t, err := api.CreateLogTree(ctx, c.admin, c.log, viper.GetString("display_name"), viper.GetString("description"))
+			if err != nil {
+				return errors.Wrap(err, "creating tree")
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), t.TreeId)
+			return nil
+		})
+	},
+}
+
+var treeListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the trees of the Trillian log server",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withTrillian(func(ctx context.Context, c *trillianClients) error {
+			resp, err := c.admin.ListTrees(ctx, &trillian.ListTreesRequest{ShowDeleted: viper.GetBool("show_deleted")})
+			if err != nil {
+				return errors.Wrap(err, "listing trees")
+			}
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+			fmt.Fprintln(w, "TREE ID\tTYPE\tSTATE\tCREATED\tDISPLAY NAME")
+			for _, t := range resp.Tree {
+				fmt.Fprintf(w, "%d\t%v\t%v\t%v\t%v\n", t.TreeId, t.TreeType, treeState(t), t.CreateTime.AsTime().UTC().Format(time.RFC3339), t.DisplayName)
+			}
+			return w.Flush()
+		})
+	},
+}
+
+var treeDescribeCmd = &cobra.Command{
+	Use:   "describe <tree-id>",
+	Short: "Describe a tree and its latest root",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		treeID, err := parseTreeID(args[0])
+		if err != nil {
+			return err
+		}
+		return withTrillian(func(ctx context.Context, c *trillianClients) error {
+			t, err := c.admin.GetTree(ctx, &trillian.GetTreeRequest{TreeId: treeID})
+			if err != nil {
+				return errors.Wrapf(err, "getting tree %d", treeID)
+			}
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+			fmt.Fprintf(w, "Tree ID:\t%d\n", t.TreeId)
+			fmt.Fprintf(w, "Type:\t%v\n", t.TreeType)
+			fmt.Fprintf(w, "State:\t%v\n", treeState(t))
+			fmt.Fprintf(w, "Display Name:\t%v\n", t.DisplayName)
+			fmt.Fprintf(w, "Description:\t%v\n", t.Description)
+			fmt.Fprintf(w, "Created:\t%v\n", t.CreateTime.AsTime().UTC().Format(time.RFC3339))
+			fmt.Fprintf(w, "Updated:\t%v\n", t.UpdateTime.AsTime().UTC().Format(time.RFC3339))
+			fmt.Fprintf(w, "Max Root Duration:\t%v\n", t.MaxRootDuration.AsDuration())
+
+			// the roots of deleted trees can no longer be read
+			if t.TreeType == trillian.TreeType_LOG && !t.Deleted {
+				resp, err := c.log.GetLatestSignedLogRoot(ctx, &trillian.GetLatestSignedLogRootRequest{LogId: treeID})
+				if err != nil {
+					return errors.Wrapf(err, "getting root of tree %d", treeID)
+				}
+				var root types.LogRootV1
+				if err := root.UnmarshalBinary(resp.SignedLogRoot.LogRoot); err != nil {
+					return err
+				}
+				fmt.Fprintf(w, "Tree Size:\t%d\n", root.TreeSize)
+				fmt.Fprintf(w, "Root Hash:\t%v\n", hex.EncodeToString(root.RootHash))
+				fmt.Fprintf(w, "Root Timestamp:\t%v\n", time.Unix(0, int64(root.TimestampNanos)).UTC().Format(time.RFC3339))
+			}
+			return w.Flush()
+		})
+	},
+}
+
+var treeFreezeCmd = &cobra.Command{
+	Use:   "freeze <tree-id>",
+	Short: "Freeze a tree so that no more entries can be added to it",
+	Long: `Freezes a tree so that no more entries can be added to it, as is done before the signing key of the
+log is rotated or a new shard is started. Entries already queued by the log server may not yet have
+been integrated; stop adding entries to the tree before freezing it.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setTreeState(cmd, args[0], trillian.TreeState_ACTIVE, trillian.TreeState_FROZEN)
+	},
+}
+
+var treeUnfreezeCmd = &cobra.Command{
+	Use:   "unfreeze <tree-id>",
+	Short: "Return a frozen tree to the active state",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setTreeState(cmd, args[0], trillian.TreeState_FROZEN, trillian.TreeState_ACTIVE)
+	},
+}
+
+var treeDeleteCmd = &cobra.Command{
+	Use:   "delete <tree-id>",
+	Short: "Soft delete a tree",
+	Long: `Soft deletes a tree; its entries can no longer be read or added to, and it is removed by the log
+server once its deletion grace period has passed.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		treeID, err := parseTreeID(args[0])
+		if err != nil {
+			return err
+		}
+		return withTrillian(func(ctx context.Context, c *trillianClients) error {
+			t, err := c.admin.GetTree(ctx, &trillian.GetTreeRequest{TreeId: treeID})
+			if err != nil {
+				return errors.Wrapf(err, "getting tree %d", treeID)
+			}
+			if t.Deleted {
+				return fmt.Errorf("tree %d is already deleted", treeID)
+			}
+			ok, err := confirm(cmd, fmt.Sprintf("Delete %v tree %d (%v)? Its entries will no longer be readable.", treeState(t), treeID, t.DisplayName))
+			if err != nil || !ok {
+				return err
+			}
+			if _, err := c.admin.DeleteTree(ctx, &trillian.DeleteTreeRequest{TreeId: treeID}); err != nil {
+				return errors.Wrapf(err, "deleting tree %d", treeID)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Deleted tree %d\n", treeID)
+			return nil
+		})
+	},
+}
+
+// trillianClients are the clients of the Trillian log server used by the tree commands
+type trillianClients struct {
+	admin trillian.TrillianAdminClient
+	log   trillian.TrillianLogClient
+}
+
+// withTrillian connects to the Trillian log server configured for rekor-server, and calls fn with clients for it
+func withTrillian(fn func(ctx context.Context, c *trillianClients) error) error {
+	ctx := context.Background()
+	conn, err := api.DialTrillian(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	return fn(ctx, &trillianClients{
+		admin: trillian.NewTrillianAdminClient(conn),
+		log:   trillian.NewTrillianLogClient(conn),
+	})
+}
+
+// setTreeState moves the tree from one state to another, after confirming the change
+func setTreeState(cmd *cobra.Command, arg string, from, to trillian.TreeState) error {
+	treeID, err := parseTreeID(arg)
+	if err != nil {
+		return err
+	}
+	return withTrillian(func(ctx context.Context, c *trillianClients) error {
+		return updateTreeState(ctx, cmd, c.admin, treeID, from, to)
+	})
+}
+
+// updateTreeState moves the tree from one state to another with the admin client, refusing to change a tree that
+// is deleted or not in the from state
+func updateTreeState(ctx context.Context, cmd *cobra.Command, admin trillian.TrillianAdminClient, treeID int64, from, to trillian.TreeState) error {
+	t, err := admin.GetTree(ctx, &trillian.GetTreeRequest{TreeId: treeID})
+	if err != nil {
+		return errors.Wrapf(err, "getting tree %d", treeID)
+	}
+	if t.Deleted || t.TreeState != from {
+		return fmt.Errorf("tree %d is %v; only a %v tree can be made %v", treeID, treeState(t), from, to)
+	}
+	ok, err := confirm(cmd, fmt.Sprintf("Change tree %d (%v) from %v to %v?", treeID, t.DisplayName, from, to))
+	if err != nil || !ok {
+		return err
+	}
+	t.TreeState = to
+	if _, err := admin.UpdateTree(ctx, &trillian.UpdateTreeRequest{
+		Tree:       t,
+		UpdateMask: &field_mask.FieldMask{Paths: []string{"tree_state"}},
+	}); err != nil {
+		return errors.Wrapf(err, "updating tree %d", treeID)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Tree %d is now %v\n", treeID, to)
+	return nil
+}
+
+// confirm asks the user to confirm an action, unless --yes was given; the action is declined unless the answer
+// is yes
+func confirm(cmd *cobra.Command, prompt string) (bool, error) {
+	if viper.GetBool("yes") {
+		return true, nil
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "%v [y/N]: ", prompt)
+	answer, err := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, err
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	default:
+		fmt.Fprintln(cmd.OutOrStdout(), "Aborted")
+		return false, nil
+	}
+}
+
+func parseTreeID(arg string) (int64, error) {
+	treeID, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil || treeID <= 0 {
+		return 0, fmt.Errorf("invalid tree ID '%v'", arg)
+	}
+	return treeID, nil
+}
+
+// treeState returns the state of the tree for display, including whether it has been deleted
+func treeState(t *trillian.Tree) string {
+	if t.Deleted {
+		return "DELETED"
+	}
+	return t.TreeState.String()
+}
+
+func init() {
+	treeCreateCmd.Flags().String("display_name", "", "display name of the tree")
+	treeCreateCmd.Flags().String("description", "", "description of the tree")
+	treeListCmd.Flags().Bool("show_deleted", false, "include deleted trees")
+	for _, cmd := range []*cobra.Command{treeFreezeCmd, treeUnfreezeCmd, treeDeleteCmd} {
+		cmd.Flags().BoolP("yes", "y", false, "do not ask for confirmation")
+	}
+
+	treeCmd.AddCommand(treeCreateCmd, treeListCmd, treeDescribeCmd, treeFreezeCmd, treeUnfreezeCmd, treeDeleteCmd)
+	rootCmd.AddCommand(treeCmd)
+}
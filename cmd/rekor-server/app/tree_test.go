@@ -0,0 +1,198 @@
+//
+// Copyright 2021 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/trillian"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// fakeAdminClient serves a single tree, recording the updates made to it
+type fakeAdminClient struct {
+	trillian.TrillianAdminClient
+	tree    *trillian.Tree
+	updates []*trillian.UpdateTreeRequest
+}
+
+func (f *fakeAdminClient) GetTree(ctx context.Context, req *trillian.GetTreeRequest, opts ...grpc.CallOption) (*trillian.Tree, error) {
+	if req.TreeId != f.tree.TreeId {
+		return nil, status.Errorf(codes.NotFound, "tree %d not found", req.TreeId)
+	}
+	return proto.Clone(f.tree).(*trillian.Tree), nil
+}
+
+func (f *fakeAdminClient) UpdateTree(ctx context.Context, req *trillian.UpdateTreeRequest, opts ...grpc.CallOption) (*trillian.Tree, error) {
+	f.updates = append(f.updates, req)
+	f.tree.TreeState = req.Tree.TreeState
+	return req.Tree, nil
+}
+
+// testCommand returns a command that reads the input and writes to the returned buffer
+func testCommand(input string) (*cobra.Command, *bytes.Buffer) {
+	cmd := &cobra.Command{}
+	out := &bytes.Buffer{}
+	cmd.SetIn(strings.NewReader(input))
+	cmd.SetOut(out)
+	return cmd, out
+}
+
+func setYes(t *testing.T, yes bool) {
+	t.Helper()
+	old := viper.GetBool("yes")
+	viper.Set("yes", yes)
+	t.Cleanup(func() { viper.Set("yes", old) })
+}
+
+func TestConfirm(t *testing.T) {
+	setYes(t, false)
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"y\n", true},
+		{"yes\n", true},
+		{" YES \n", true},
+		{"Y", true},
+		{"n\n", false},
+		{"no\n", false},
+		{"yess\n", false},
+		{"\n", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		cmd, out := testCommand(tt.input)
+		got, err := confirm(cmd, "Proceed?")
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.input, err)
+		}
+		if got != tt.want {
+			t.Errorf("%q: expected %v, got %v", tt.input, tt.want, got)
+		}
+		if !strings.HasPrefix(out.String(), "Proceed? [y/N]: ") {
+			t.Errorf("%q: expected the prompt, got %q", tt.input, out.String())
+		}
+		if aborted := strings.Contains(out.String(), "Aborted"); aborted == tt.want {
+			t.Errorf("%q: unexpected output %q", tt.input, out.String())
+		}
+	}
+
+	setYes(t, true)
+	cmd, out := testCommand("n\n")
+	if got, err := confirm(cmd, "Proceed?"); err != nil || !got {
+		t.Errorf("expected --yes to confirm without asking, got %v, %v", got, err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("expected no prompt with --yes, got %q", out.String())
+	}
+}
+
+func TestParseTreeID(t *testing.T) {
+	tests := []struct {
+		arg     string
+		want    int64
+		wantErr bool
+	}{
+		{"1", 1, false},
+		{"6002829577353435541", 6002829577353435541, false},
+		{"0", 0, true},
+		{"-5", 0, true},
+		{"abc", 0, true},
+		{"", 0, true},
+		{"1.5", 0, true},
+		{"9223372036854775808", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseTreeID(tt.arg)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%q: expected error %v, got %v", tt.arg, tt.wantErr, err)
+		}
+		if got != tt.want {
+			t.Errorf("%q: expected %d, got %d", tt.arg, tt.want, got)
+		}
+	}
+}
+
+func TestUpdateTreeState(t *testing.T) {
+	setYes(t, false)
+	const treeID = 42
+	tests := []struct {
+		name      string
+		state     trillian.TreeState
+		deleted   bool
+		from, to  trillian.TreeState
+		input     string
+		wantErr   string
+		wantState trillian.TreeState
+	}{
+		{"freeze", trillian.TreeState_ACTIVE, false, trillian.TreeState_ACTIVE, trillian.TreeState_FROZEN, "y\n", "", trillian.TreeState_FROZEN},
+		{"unfreeze", trillian.TreeState_FROZEN, false, trillian.TreeState_FROZEN, trillian.TreeState_ACTIVE, "y\n", "", trillian.TreeState_ACTIVE},
+		{"declined", trillian.TreeState_ACTIVE, false, trillian.TreeState_ACTIVE, trillian.TreeState_FROZEN, "n\n", "", trillian.TreeState_ACTIVE},
+		{"freeze frozen tree", trillian.TreeState_FROZEN, false, trillian.TreeState_ACTIVE, trillian.TreeState_FROZEN, "y\n", "tree 42 is FROZEN; only a ACTIVE tree can be made FROZEN", trillian.TreeState_FROZEN},
+		{"unfreeze active tree", trillian.TreeState_ACTIVE, false, trillian.TreeState_FROZEN, trillian.TreeState_ACTIVE, "y\n", "tree 42 is ACTIVE; only a FROZEN tree can be made ACTIVE", trillian.TreeState_ACTIVE},
+		{"freeze deleted tree", trillian.TreeState_ACTIVE, true, trillian.TreeState_ACTIVE, trillian.TreeState_FROZEN, "y\n", "tree 42 is DELETED; only a ACTIVE tree can be made FROZEN", trillian.TreeState_ACTIVE},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			admin := &fakeAdminClient{tree: &trillian.Tree{TreeId: treeID, TreeState: tt.state, Deleted: tt.deleted, DisplayName: "test"}}
+			cmd, out := testCommand(tt.input)
+
+			err := updateTreeState(context.Background(), cmd, admin, treeID, tt.from, tt.to)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("expected error %q, got %v", tt.wantErr, err)
+				}
+				if out.Len() != 0 {
+					t.Errorf("expected no prompt for a tree in the wrong state, got %q", out.String())
+				}
+			} else if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			if admin.tree.TreeState != tt.wantState {
+				t.Errorf("expected the tree to be %v, got %v", tt.wantState, admin.tree.TreeState)
+			}
+			if tt.wantState == tt.state {
+				if len(admin.updates) != 0 {
+					t.Errorf("expected the tree not to be updated, got %v", admin.updates)
+				}
+				return
+			}
+			if len(admin.updates) != 1 || admin.updates[0].UpdateMask.GetPaths()[0] != "tree_state" || len(admin.updates[0].UpdateMask.GetPaths()) != 1 {
+				t.Errorf("expected a single update of the tree state, got %v", admin.updates)
+			}
+			if !strings.HasSuffix(out.String(), "Tree 42 is now "+tt.to.String()+"\n") {
+				t.Errorf("unexpected output %q", out.String())
+			}
+		})
+	}
+
+	admin := &fakeAdminClient{tree: &trillian.Tree{TreeId: treeID, TreeState: trillian.TreeState_ACTIVE}}
+	cmd, _ := testCommand("y\n")
+	if err := updateTreeState(context.Background(), cmd, admin, 7, trillian.TreeState_ACTIVE, trillian.TreeState_FROZEN); err == nil || status.Code(errors.Cause(err)) != codes.NotFound {
+		t.Errorf("expected the tree not to be found, got %v", err)
+	}
+}
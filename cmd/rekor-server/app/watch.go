@@ -16,27 +16,35 @@
 package app
 
 import (
+	"bytes"
 	"context"
-	"crypto"
-	"crypto/x509"
 	"encoding/json"
-	"encoding/pem"
 	"flag"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	_ "gocloud.dev/blob/fileblob" // fileblob
 	_ "gocloud.dev/blob/gcsblob"
 
+	"github.com/go-openapi/swag"
 	"github.com/google/trillian/types"
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"gocloud.dev/blob"
 
 	"github.com/sigstore/rekor/cmd/rekor-cli/app"
 	"github.com/sigstore/rekor/pkg/generated/client"
+	"github.com/sigstore/rekor/pkg/generated/client/pubkey"
 	"github.com/sigstore/rekor/pkg/generated/models"
 	"github.com/sigstore/rekor/pkg/log"
 	"github.com/sigstore/rekor/pkg/verify"
@@ -44,6 +52,28 @@ import (
 
 const rekorSthBucketEnv = "REKOR_STH_BUCKET"
 
+var (
+	metricWatchTreeSize = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "rekor_watch_last_verified_tree_size",
+		Help: "The size of the last tree head verified by the watcher",
+	})
+
+	metricWatchLastSuccess = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "rekor_watch_last_success_timestamp_seconds",
+		Help: "The time of the last check that verified a tree head",
+	})
+
+	metricWatchFailures = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "rekor_watch_failures",
+		Help: "The total number of checks that failed or raised an alert",
+	})
+
+	metricWatchAlerts = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "rekor_watch_alerts",
+		Help: "The total number of alerts raised, by reason",
+	}, []string{"reason"})
+)
+
 // watchCmd represents the serve command
 var watchCmd = &cobra.Command{
 	Use:   "watch",
@@ -73,19 +103,22 @@ var watchCmd = &cobra.Command{
 			return err
 		}
 
-		keyResp, err := c.Pubkey.GetPublicKey(nil)
-		if err != nil {
-			return err
-		}
-		publicKey := keyResp.Payload
-		block, _ := pem.Decode([]byte(publicKey))
-		if block == nil {
-			return errors.New("failed to decode public key of server")
-		}
-
-		pub, err := x509.ParsePKIXPublicKey(block.Bytes)
-		if err != nil {
-			return err
+		// the keys are only fetched from the log if none are configured, and then only once, so that a log cannot
+		// have the watcher trust a key of its choosing later
+		var keys verify.LogKeys
+		if path := viper.GetString("public_key"); path != "" {
+			b, err := ioutil.ReadFile(filepath.Clean(path))
+			if err != nil {
+				return errors.Wrap(err, "reading public key of log")
+			}
+			if keys, err = verify.ParseLogKeys(b); err != nil {
+				return errors.Wrap(err, "parsing public key of log")
+			}
+		} else {
+			if keys, err = fetchLogKeys(c); err != nil {
+				return err
+			}
+			log.Logger.Warnf("No public_key configured; trusting the %d keys served by the log now", len(keys))
 		}
 
 		ctx := context.Background()
@@ -98,40 +131,101 @@ var watchCmd = &cobra.Command{
 			return err
 		}
 		defer bucket.Close()
-		tick := time.NewTicker(interval)
-		var last *SignedAndUnsignedLogRoot
 
+		// the last tree head verified is kept in the bucket, so that consistency is proven across restarts
+		last, err := loadLastLogRoot(ctx, bucket, keys)
+		if err != nil {
+			return err
+		}
+		if last != nil {
+			log.Logger.Infof("Loaded last verified state at %d %d", last.VerifiedLogRoot.TreeSize, last.VerifiedLogRoot.TimestampNanos)
+			metricWatchTreeSize.Set(float64(last.VerifiedLogRoot.TreeSize))
+		}
+		sinks := newAlertSinks()
+
+		if port := viper.GetUint("metrics_port"); port != 0 {
+			http.Handle("/metrics", promhttp.Handler())
+			go func() {
+				_ = http.ListenAndServe(fmt.Sprintf(":%d", port), nil)
+			}()
+		}
+
+		tick := time.NewTicker(interval)
 		for {
 			<-tick.C
 			log.Logger.Info("performing check")
-			lr, err := doCheck(c, pub)
+			lr, err := doCheck(c, keys)
+			if errors.Is(err, errUnknownKey) {
+				sendAlert(ctx, sinks, &watchAlert{
+					Reason:   alertUnknownKey,
+					Message:  err.Error(),
+					Time:     time.Now().UTC(),
+					Previous: last,
+				})
+				metricWatchFailures.Inc()
+				continue
+			}
 			if err != nil {
 				log.Logger.Warnf("error verifiying tree: %s", err)
+				metricWatchFailures.Inc()
 				continue
 			}
 			log.Logger.Infof("Found and verified state at %d %d", lr.VerifiedLogRoot.TreeSize, lr.VerifiedLogRoot.TimestampNanos)
-			if last != nil && last.VerifiedLogRoot.TreeSize == lr.VerifiedLogRoot.TreeSize {
-				log.Logger.Infof("Last tree size is the same as the current one: %d %d",
-					last.VerifiedLogRoot.TreeSize, lr.VerifiedLogRoot.TreeSize)
-				// If it's the same, it shouldn't have changed but we'll still upload anyway
-				// in case that failed.
+
+			alert, err := checkConsistency(c, keys, last, lr)
+			if err != nil {
+				log.Logger.Warnf("error proving consistency: %s", err)
+				metricWatchFailures.Inc()
+				continue
+			}
+			if alert != nil {
+				// the tree head is not kept, so that each later one is also checked against the last good one
+				sendAlert(ctx, sinks, alert)
+				metricWatchFailures.Inc()
+				continue
 			}
 
 			if err := uploadToBlobStorage(ctx, bucket, lr); err != nil {
 				log.Logger.Warnf("error uploading result: %s", err)
+				metricWatchFailures.Inc()
 				continue
 			}
 			last = lr
+			metricWatchTreeSize.Set(float64(lr.VerifiedLogRoot.TreeSize))
+			metricWatchLastSuccess.SetToCurrentTime()
 		}
 	},
 }
 
 func init() {
 	watchCmd.Flags().Duration("interval", 1*time.Minute, "Polling interval")
+	watchCmd.Flags().String("public_key", "", "PEM file holding the keys that the log signs, or has signed, tree heads with; if not set, the keys served by the log at startup are trusted")
+	watchCmd.Flags().Uint("metrics_port", 2112, "Port to serve Prometheus metrics on, or 0 to not serve them")
+	watchCmd.Flags().String("alert.webhook_url", "", "URL that alerts are posted to as JSON")
+	watchCmd.Flags().String("alert.file", "", "file that alerts are appended to as lines of JSON")
+	watchCmd.Flags().Int("alert.exit_code", 0, "status to exit with after an alert, or 0 to keep watching")
 	rootCmd.AddCommand(watchCmd)
 }
 
-func doCheck(c *client.Rekor, pub crypto.PublicKey) (*SignedAndUnsignedLogRoot, error) {
+// errUnknownKey is returned by doCheck if the tree head was signed with a key not among those given
+var errUnknownKey = errors.New("tree head signed with unknown key")
+
+// fetchLogKeys returns every key that the log signs or has signed tree heads with
+func fetchLogKeys(c *client.Rekor) (verify.LogKeys, error) {
+	params := pubkey.NewGetPublicKeyParams()
+	params.All = swag.Bool(true)
+	keyResp, err := c.Pubkey.GetPublicKey(params)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := verify.ParseLogKeys([]byte(keyResp.Payload))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode public key of server")
+	}
+	return keys, nil
+}
+
+func doCheck(c *client.Rekor, keys verify.LogKeys) (*SignedAndUnsignedLogRoot, error) {
 	li, err := c.Tlog.GetLogInfo(nil)
 	if err != nil {
 		return nil, errors.Wrap(err, "getting log info")
@@ -145,7 +239,14 @@ func doCheck(c *client.Rekor, pub crypto.PublicKey) (*SignedAndUnsignedLogRoot,
 		return nil, errors.New("signature should not be nil")
 	}
 
-	verifiedLogRoot, err := verify.SignedLogRoot(pub, logRoot, signature)
+	var keyHint []byte
+	if hint := li.Payload.SignedTreeHead.KeyHint; hint != nil {
+		keyHint = *hint
+		if keys.ByHint(keyHint) == nil {
+			return nil, errors.Wrapf(errUnknownKey, "key %x", keyHint)
+		}
+	}
+	verifiedLogRoot, err := keys.SignedLogRoot(keyHint, logRoot, signature)
 	if err != nil {
 		return nil, errors.Wrap(err, "signing log root")
 	}
@@ -153,9 +254,125 @@ func doCheck(c *client.Rekor, pub crypto.PublicKey) (*SignedAndUnsignedLogRoot,
 	return &SignedAndUnsignedLogRoot{
 		SignedLogRoot:   li.GetPayload().SignedTreeHead,
 		VerifiedLogRoot: verifiedLogRoot,
+		TreeID:          li.Payload.TreeID,
+		InactiveShards:  li.Payload.InactiveShards,
 	}, nil
 }
 
+// checkConsistency proves that the tree head is consistent with the last one verified, returning an alert if the
+// tree has shrunk, has a different root at the same size, or does not extend the last tree head; an error is
+// returned only if the proof could not be fetched. If the log has moved to a new tree since the last tree head, the
+// last tree must be one of the inactive shards of the log, and its frozen tree head is checked in the same way.
+func checkConsistency(c *client.Rekor, keys verify.LogKeys, last, lr *SignedAndUnsignedLogRoot) (*watchAlert, error) {
+	if last == nil {
+		return nil, nil
+	}
+	alert := &watchAlert{Time: time.Now().UTC(), Previous: last, Current: lr}
+	prev, cur := last.VerifiedLogRoot, lr.VerifiedLogRoot
+	if last.TreeID != "" && last.TreeID != lr.TreeID {
+		frozen, err := frozenLogRoot(keys, lr, last.TreeID)
+		if err != nil {
+			alert.Reason = alertTreeChanged
+			alert.Message = fmt.Sprintf("tree ID changed from %v to %v: %v", last.TreeID, lr.TreeID, err)
+			return alert, nil
+		}
+		log.Logger.Infof("Tree ID changed from %v to %v; checking the frozen tree head of %v", last.TreeID, lr.TreeID, last.TreeID)
+		cur = frozen
+	}
+
+	switch {
+	case cur.TreeSize < prev.TreeSize:
+		alert.Reason = alertShrink
+		alert.Message = fmt.Sprintf("tree size decreased from %d to %d", prev.TreeSize, cur.TreeSize)
+	case cur.TreeSize == prev.TreeSize:
+		if bytes.Equal(cur.RootHash, prev.RootHash) {
+			return nil, nil
+		}
+		alert.Reason = alertFork
+		alert.Message = fmt.Sprintf("root hash at tree size %d changed from %x to %x", cur.TreeSize, prev.RootHash, cur.RootHash)
+	case prev.TreeSize == 0:
+		return nil, nil
+	default:
+		err := app.ProveConsistency(c, last.TreeID, int64(prev.TreeSize), prev.RootHash, int64(cur.TreeSize), cur.RootHash)
+		if err == nil {
+			log.Logger.Infof("Proved consistency between %d and %d", prev.TreeSize, cur.TreeSize)
+			return nil, nil
+		}
+		if !errors.Is(err, app.ErrInconsistentTree) {
+			return nil, err
+		}
+		alert.Reason = alertInconsistent
+		alert.Message = fmt.Sprintf("tree at size %d is not consistent with size %d: %v", cur.TreeSize, prev.TreeSize, err)
+	}
+	return alert, nil
+}
+
+// frozenLogRoot returns the verified tree head of the inactive shard of the log with the tree ID
+func frozenLogRoot(keys verify.LogKeys, lr *SignedAndUnsignedLogRoot, treeID string) (*types.LogRootV1, error) {
+	for _, shard := range lr.InactiveShards {
+		if swag.StringValue(shard.TreeID) != treeID {
+			continue
+		}
+		sth := shard.SignedTreeHead
+		if sth == nil || sth.LogRoot == nil || sth.Signature == nil {
+			return nil, fmt.Errorf("inactive shard %v has no signed tree head", treeID)
+		}
+		var keyHint []byte
+		if sth.KeyHint != nil {
+			keyHint = *sth.KeyHint
+		}
+		frozen, err := keys.SignedLogRoot(keyHint, *sth.LogRoot, *sth.Signature)
+		if err != nil {
+			return nil, errors.Wrapf(err, "verifying signed tree head of inactive shard %v", treeID)
+		}
+		return frozen, nil
+	}
+	return nil, fmt.Errorf("tree %v is not among the inactive shards of the log", treeID)
+}
+
+// loadLastLogRoot returns the tree head most recently uploaded to the bucket, once its signature is verified, or
+// nil if none has been uploaded
+func loadLastLogRoot(ctx context.Context, bucket *blob.Bucket, keys verify.LogKeys) (*SignedAndUnsignedLogRoot, error) {
+	var latest *blob.ListObject
+	iter := bucket.List(&blob.ListOptions{Prefix: "sth-"})
+	for {
+		obj, err := iter.Next(ctx)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "listing bucket")
+		}
+		if strings.HasSuffix(obj.Key, ".json") && (latest == nil || obj.ModTime.After(latest.ModTime)) {
+			latest = obj
+		}
+	}
+	if latest == nil {
+		return nil, nil
+	}
+
+	b, err := bucket.ReadAll(ctx, latest.Key)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading %v", latest.Key)
+	}
+	var lr SignedAndUnsignedLogRoot
+	if err := json.Unmarshal(b, &lr); err != nil {
+		return nil, errors.Wrapf(err, "parsing %v", latest.Key)
+	}
+	sth := lr.SignedLogRoot
+	if sth == nil || sth.LogRoot == nil || sth.Signature == nil {
+		return nil, fmt.Errorf("%v has no signed tree head", latest.Key)
+	}
+	var keyHint []byte
+	if sth.KeyHint != nil {
+		keyHint = *sth.KeyHint
+	}
+	if lr.VerifiedLogRoot, err = keys.SignedLogRoot(keyHint, *sth.LogRoot, *sth.Signature); err != nil {
+		return nil, errors.Wrapf(err, "verifying %v", latest.Key)
+	}
+	return &lr, nil
+}
+
 func uploadToBlobStorage(ctx context.Context, bucket *blob.Bucket, lr *SignedAndUnsignedLogRoot) error {
 	b, err := json.Marshal(lr)
 	if err != nil {
@@ -167,15 +384,20 @@ func uploadToBlobStorage(ctx context.Context, bucket *blob.Bucket, lr *SignedAnd
 	if err != nil {
 		return err
 	}
-	defer w.Close()
 	if _, err := w.Write(b); err != nil {
+		_ = w.Close()
 		return err
 	}
-	return nil
+	// the object is only committed to the bucket once the writer is closed
+	return w.Close()
 }
 
 // For JSON marshalling
 type SignedAndUnsignedLogRoot struct {
 	SignedLogRoot   *models.LogInfoSignedTreeHead
 	VerifiedLogRoot *types.LogRootV1
+	// tree of the active shard that the tree head is of; empty for logs that do not report it
+	TreeID string `json:",omitempty"`
+	// frozen shards of the log when the tree head was fetched
+	InactiveShards []*models.InactiveShardLogInfo `json:",omitempty"`
 }
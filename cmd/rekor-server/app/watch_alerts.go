@@ -0,0 +1,143 @@
+//
+// Copyright 2021 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+
+	"github.com/sigstore/rekor/pkg/log"
+)
+
+// reasons that the watcher raises an alert for
+const (
+	alertShrink       = "shrink"
+	alertFork         = "fork"
+	alertInconsistent = "inconsistent"
+	alertTreeChanged  = "tree_change"
+	alertUnknownKey   = "unknown_key"
+)
+
+// watchAlert is raised when the watcher finds that the log has not only grown since the last tree head verified,
+// or that its tree head is signed with a key the watcher does not trust
+type watchAlert struct {
+	Reason   string
+	Message  string
+	Time     time.Time
+	Previous *SignedAndUnsignedLogRoot
+	// nil if the current tree head could not be verified
+	Current *SignedAndUnsignedLogRoot
+}
+
+// alertSink is sent each alert raised by the watcher
+type alertSink interface {
+	Send(ctx context.Context, alert *watchAlert) error
+}
+
+// newAlertSinks returns the sinks configured with the alert flags; the exit sink is always last, so that the other
+// sinks are sent the alert before the watcher exits
+func newAlertSinks() []alertSink {
+	var sinks []alertSink
+	if url := viper.GetString("alert.webhook_url"); url != "" {
+		sinks = append(sinks, &webhookSink{url: url, client: &http.Client{Timeout: 30 * time.Second}})
+	}
+	if path := viper.GetString("alert.file"); path != "" {
+		sinks = append(sinks, &fileSink{path: path})
+	}
+	if code := viper.GetInt("alert.exit_code"); code != 0 {
+		sinks = append(sinks, exitSink{code: code})
+	}
+	return sinks
+}
+
+// sendAlert sends the alert to each of the sinks; an alert is always logged
+func sendAlert(ctx context.Context, sinks []alertSink, alert *watchAlert) {
+	log.Logger.Errorf("ALERT (%v): %v", alert.Reason, alert.Message)
+	metricWatchAlerts.WithLabelValues(alert.Reason).Inc()
+	for _, s := range sinks {
+		if err := s.Send(ctx, alert); err != nil {
+			log.Logger.Errorf("error sending alert to %T: %v", s, err)
+		}
+	}
+}
+
+// webhookSink posts each alert as JSON to a URL
+type webhookSink struct {
+	url    string
+	client *http.Client
+}
+
+func (s *webhookSink) Send(ctx context.Context, alert *watchAlert) error {
+	b, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned status %v", resp.Status)
+	}
+	return nil
+}
+
+// fileSink appends each alert to a file as a line of JSON
+type fileSink struct {
+	path string
+}
+
+func (s *fileSink) Send(ctx context.Context, alert *watchAlert) error {
+	b, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(filepath.Clean(s.path), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return errors.Wrap(err, "opening alert file")
+	}
+	if _, err := f.Write(append(b, '\n')); err != nil {
+		f.Close()
+		return errors.Wrap(err, "writing alert")
+	}
+	return f.Close()
+}
+
+// exitSink exits the watcher with a status code, for supervisors that act on the watcher exiting
+type exitSink struct {
+	code int
+}
+
+func (s exitSink) Send(ctx context.Context, alert *watchAlert) error {
+	log.Logger.Errorf("exiting with status %d after alert", s.code)
+	os.Exit(s.code)
+	return nil
+}
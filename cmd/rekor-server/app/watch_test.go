@@ -0,0 +1,284 @@
+//
+// Copyright 2021 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"bufio"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+	rfc6962 "github.com/google/trillian/merkle/rfc6962/hasher"
+	"github.com/google/trillian/types"
+	"github.com/spf13/viper"
+
+	"github.com/sigstore/rekor/pkg/generated/client"
+	"github.com/sigstore/rekor/pkg/generated/client/tlog"
+	"github.com/sigstore/rekor/pkg/generated/models"
+	"github.com/sigstore/rekor/pkg/verify"
+)
+
+// merkleRoot computes the RFC 6962 root hash of the leaves from its definition
+func merkleRoot(leaves [][]byte) []byte {
+	h := rfc6962.DefaultHasher
+	switch len(leaves) {
+	case 0:
+		return h.EmptyRoot()
+	case 1:
+		return h.HashLeaf(leaves[0])
+	}
+	k := splitPoint(len(leaves))
+	return h.HashChildren(merkleRoot(leaves[:k]), merkleRoot(leaves[k:]))
+}
+
+// splitPoint returns the largest power of two less than n
+func splitPoint(n int) int {
+	k := 1
+	for k*2 < n {
+		k *= 2
+	}
+	return k
+}
+
+// consistencyProof computes the RFC 6962 consistency proof between the first m leaves and all of the leaves
+func consistencyProof(m int, leaves [][]byte, complete bool) [][]byte {
+	n := len(leaves)
+	if m == n {
+		if complete {
+			return nil
+		}
+		return [][]byte{merkleRoot(leaves)}
+	}
+	k := splitPoint(n)
+	if m <= k {
+		return append(consistencyProof(m, leaves[:k], complete), merkleRoot(leaves[k:]))
+	}
+	return append(consistencyProof(m-k, leaves[k:], false), merkleRoot(leaves[:k]))
+}
+
+func testLeaves(prefix string, n int) [][]byte {
+	var leaves [][]byte
+	for i := 0; i < n; i++ {
+		leaves = append(leaves, []byte(fmt.Sprintf("%v %d", prefix, i)))
+	}
+	return leaves
+}
+
+// stubTlog serves consistency proofs of the trees given, keyed by tree ID
+type stubTlog struct {
+	trees map[string][][]byte
+	err   error
+}
+
+func (s *stubTlog) AddCosignature(params *tlog.AddCosignatureParams, opts ...tlog.ClientOption) (*tlog.AddCosignatureCreated, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (s *stubTlog) GetLogInfo(params *tlog.GetLogInfoParams, opts ...tlog.ClientOption) (*tlog.GetLogInfoOK, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (s *stubTlog) GetLogProof(params *tlog.GetLogProofParams, opts ...tlog.ClientOption) (*tlog.GetLogProofOK, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	leaves, ok := s.trees[swag.StringValue(params.TreeID)]
+	if !ok || int(params.LastSize) > len(leaves) {
+		return nil, errors.New("no such tree")
+	}
+	leaves = leaves[:params.LastSize]
+	proof := &models.ConsistencyProof{RootHash: swag.String(hex.EncodeToString(merkleRoot(leaves)))}
+	for _, h := range consistencyProof(int(swag.Int64Value(params.FirstSize)), leaves, true) {
+		proof.Hashes = append(proof.Hashes, hex.EncodeToString(h))
+	}
+	return &tlog.GetLogProofOK{Payload: proof}, nil
+}
+
+func (s *stubTlog) SetTransport(transport runtime.ClientTransport) {}
+
+func TestCheckConsistency(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := verify.NewLogKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := verify.LogKeys{key}
+	_, otherPriv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	leaves := testLeaves("leaf", 20)
+	forked := append(append([][]byte{}, leaves[:10]...), testLeaves("fork", 10)...)
+	next := testLeaves("next", 5)
+
+	head := func(treeID string, l [][]byte) *SignedAndUnsignedLogRoot {
+		return &SignedAndUnsignedLogRoot{
+			VerifiedLogRoot: &types.LogRootV1{TreeSize: uint64(len(l)), RootHash: merkleRoot(l)},
+			TreeID:          treeID,
+		}
+	}
+	// frozen returns a shard of the tree signed with the private key
+	frozen := func(treeID string, l [][]byte, signer ed25519.PrivateKey) *models.InactiveShardLogInfo {
+		logRoot, err := (&types.LogRootV1{TreeSize: uint64(len(l)), RootHash: merkleRoot(l)}).MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		lr, sig, hint := strfmt.Base64(logRoot), strfmt.Base64(ed25519.Sign(signer, logRoot)), strfmt.Base64(key.ID)
+		return &models.InactiveShardLogInfo{
+			TreeID:         swag.String(treeID),
+			TreeSize:       swag.Int64(int64(len(l))),
+			SignedTreeHead: &models.InactiveShardLogInfoSignedTreeHead{KeyHint: &hint, LogRoot: &lr, Signature: &sig},
+		}
+	}
+	moved := func(shards ...*models.InactiveShardLogInfo) *SignedAndUnsignedLogRoot {
+		lr := head("2", next)
+		lr.InactiveShards = shards
+		return lr
+	}
+
+	tests := []struct {
+		name       string
+		last, cur  *SignedAndUnsignedLogRoot
+		proofErr   error
+		wantReason string
+		wantErr    bool
+	}{
+		{name: "first tree head", cur: head("1", leaves)},
+		{name: "unchanged", last: head("1", leaves), cur: head("1", leaves)},
+		{name: "grown", last: head("1", leaves[:7]), cur: head("1", leaves)},
+		{name: "grown from empty", last: head("1", nil), cur: head("1", leaves)},
+		{name: "shrunk", last: head("1", leaves), cur: head("1", leaves[:7]), wantReason: alertShrink},
+		{name: "forked", last: head("1", forked), cur: head("1", leaves), wantReason: alertFork},
+		{name: "inconsistent", last: head("1", forked[:15]), cur: head("1", leaves), wantReason: alertInconsistent},
+		{name: "proof unavailable", last: head("1", leaves[:7]), cur: head("1", leaves), proofErr: errors.New("unavailable"), wantErr: true},
+		{name: "legacy tree head without tree ID", last: head("", leaves[:7]), cur: head("1", leaves)},
+		{name: "moved to frozen shard", last: head("1", leaves[:7]), cur: moved(frozen("1", leaves, priv))},
+		{name: "moved without frozen shard", last: head("1", leaves[:7]), cur: moved(), wantReason: alertTreeChanged},
+		{name: "moved to another frozen shard", last: head("1", leaves[:7]), cur: moved(frozen("3", leaves, priv)), wantReason: alertTreeChanged},
+		{name: "frozen shard signed with unknown key", last: head("1", leaves[:7]), cur: moved(frozen("1", leaves, otherPriv)), wantReason: alertTreeChanged},
+		{name: "frozen shard shrunk", last: head("1", leaves), cur: moved(frozen("1", leaves[:7], priv)), wantReason: alertShrink},
+		{name: "frozen shard inconsistent", last: head("1", forked[:15]), cur: moved(frozen("1", leaves, priv)), wantReason: alertInconsistent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &client.Rekor{Tlog: &stubTlog{trees: map[string][][]byte{"1": leaves, "": leaves, "2": next}, err: tt.proofErr}}
+			alert, err := checkConsistency(c, keys, tt.last, tt.cur)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkConsistency() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			reason := ""
+			if alert != nil {
+				reason = alert.Reason
+			}
+			if reason != tt.wantReason {
+				t.Errorf("checkConsistency() alert reason = %q, want %q", reason, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestAlertSinks(t *testing.T) {
+	ctx := context.Background()
+	alert := &watchAlert{Reason: alertFork, Message: "forked"}
+
+	var received []watchAlert
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var a watchAlert
+		if err := json.NewDecoder(r.Body).Decode(&a); err != nil {
+			t.Errorf("decoding alert: %v", err)
+		}
+		received = append(received, a)
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	webhook := &webhookSink{url: srv.URL, client: srv.Client()}
+	if err := webhook.Send(ctx, alert); err != nil {
+		t.Fatalf("sending to webhook: %v", err)
+	}
+	if len(received) != 1 || received[0].Reason != alertFork || received[0].Message != "forked" {
+		t.Errorf("webhook received %+v", received)
+	}
+	status = http.StatusInternalServerError
+	if err := webhook.Send(ctx, alert); err == nil {
+		t.Errorf("expected error for webhook returning an error status")
+	}
+
+	path := filepath.Join(t.TempDir(), "alerts.json")
+	file := &fileSink{path: path}
+	for i := 0; i < 2; i++ {
+		if err := file.Send(ctx, alert); err != nil {
+			t.Fatalf("sending to file: %v", err)
+		}
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	lines := 0
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var a watchAlert
+		if err := json.Unmarshal(scanner.Bytes(), &a); err != nil {
+			t.Errorf("line %d: %v", lines, err)
+		}
+		lines++
+	}
+	if lines != 2 {
+		t.Errorf("alert file has %d lines, want 2", lines)
+	}
+	if err := (&fileSink{path: filepath.Join(t.TempDir(), "missing", "alerts.json")}).Send(ctx, alert); err == nil {
+		t.Errorf("expected error for alert file in missing directory")
+	}
+}
+
+func TestNewAlertSinks(t *testing.T) {
+	defer viper.Reset()
+	viper.Set("alert.exit_code", 3)
+	viper.Set("alert.webhook_url", "http://127.0.0.1/alerts")
+	viper.Set("alert.file", filepath.Join(t.TempDir(), "alerts.json"))
+
+	sinks := newAlertSinks()
+	if len(sinks) != 3 {
+		t.Fatalf("got %d sinks, want 3", len(sinks))
+	}
+	if _, ok := sinks[len(sinks)-1].(exitSink); !ok {
+		t.Errorf("exit sink is not last: %T", sinks[len(sinks)-1])
+	}
+
+	viper.Reset()
+	if sinks := newAlertSinks(); len(sinks) != 0 {
+		t.Errorf("got %d sinks with no alert settings, want 0", len(sinks))
+	}
+}
@@ -0,0 +1,283 @@
+//
+// Copyright 2021 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+	"github.com/google/trillian/types"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/sigstore/rekor/cmd/rekor-cli/app"
+	"github.com/sigstore/rekor/pkg/generated/client"
+	"github.com/sigstore/rekor/pkg/generated/client/tlog"
+	"github.com/sigstore/rekor/pkg/generated/models"
+	"github.com/sigstore/rekor/pkg/log"
+	"github.com/sigstore/rekor/pkg/signer"
+	"github.com/sigstore/rekor/pkg/verify"
+	"github.com/sigstore/sigstore/pkg/signature"
+)
+
+// witnessStateFile is the name of the file in witness.state_dir that holds the last tree head verified of each log
+const witnessStateFile = "witness_state.json"
+
+// witnessLogConfig is an entry in witness.logs in the config file
+type witnessLogConfig struct {
+	URL string `mapstructure:"url"`
+	// name of the log on the server, or empty for the default log of the server
+	Name string `mapstructure:"name"`
+	// PEM file holding the keys that the log signs, or has signed, tree heads with
+	PublicKey string `mapstructure:"public_key"`
+}
+
+// witnessedLog is a log that the witness cosigns the tree heads of
+type witnessedLog struct {
+	// identifies the log in the state of the witness
+	id     string
+	client *client.Rekor
+	keys   verify.LogKeys
+}
+
+// witnessState is the last tree head that the witness verified for a tree of a log
+type witnessState struct {
+	TreeSize uint64
+	RootHash []byte
+}
+
+// witness cosigns the tree heads of logs once it has verified that they are consistent with those it saw before
+type witness struct {
+	signer signature.Signer
+	keyID  []byte
+	logs   []*witnessedLog
+	// keyed by the ID of the log and the ID of the tree
+	state     map[string]witnessState
+	statePath string
+}
+
+// witnessCmd represents the witness command
+var witnessCmd = &cobra.Command{
+	Use:   "witness",
+	Short: "Start a process to verify and cosign the tree heads of Rekor logs",
+	Long: `Start a process that polls each log in witness.logs, verifies that its signed tree head is consistent
+with the last one the witness saw, and submits a cosignature of the tree head to the log. Clients can then
+require that tree heads are cosigned by witnesses they trust, so that a log cannot show a different view of
+itself to different clients.`,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		// these are bound here so that they are not overwritten by other commands
+		if err := viper.BindPFlags(cmd.Flags()); err != nil {
+			log.Logger.Fatal("Error initializing cmd line args: ", err)
+		}
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		log.ConfigureLogger(viper.GetString("log_type"))
+
+		// workaround for https://github.com/sigstore/rekor/issues/68
+		// from https://github.com/golang/glog/commit/fca8c8854093a154ff1eb580aae10276ad6b1b5f
+		_ = flag.CommandLine.Parse([]string{})
+
+		ctx := context.Background()
+		w, err := newWitness(ctx)
+		if err != nil {
+			return err
+		}
+		log.Logger.Infof("Cosigning tree heads with key %x", w.keyID)
+
+		tick := time.NewTicker(viper.GetDuration("witness.interval"))
+		defer tick.Stop()
+		for {
+			for _, l := range w.logs {
+				if err := w.witnessLog(ctx, l); err != nil {
+					log.Logger.Errorf("witnessing %v: %v", l.id, err)
+				}
+			}
+			<-tick.C
+		}
+	},
+}
+
+func init() {
+	witnessCmd.Flags().String("witness.signer", "", "signer of the witness's cosignatures: [gcpkms, memory, file://<path to PEM encoded private key>]")
+	witnessCmd.Flags().Duration("witness.interval", 1*time.Minute, "Polling interval")
+	witnessCmd.Flags().String("witness.state_dir", ".", "directory that the last tree head verified of each log is kept in")
+	rootCmd.AddCommand(witnessCmd)
+}
+
+func newWitness(ctx context.Context) (*witness, error) {
+	s, err := signer.New(ctx, viper.GetString("witness.signer"))
+	if err != nil {
+		return nil, errors.Wrap(err, "getting signer")
+	}
+	pub, err := s.PublicKey(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting public key")
+	}
+	key, err := verify.NewLogKey(pub)
+	if err != nil {
+		return nil, err
+	}
+	// check that logs and clients can verify cosignatures made by the signer
+	logRoot, err := (&types.LogRootV1{RootHash: make([]byte, 32)}).MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	sig, _, err := s.Sign(ctx, verify.CosignedMessage(0, logRoot))
+	if err != nil {
+		return nil, errors.Wrap(err, "signing")
+	}
+	if _, err := (verify.LogKeys{key}).Cosignature(key.ID, 0, logRoot, sig); err != nil {
+		return nil, errors.Wrap(err, "cosignatures made by the signer cannot be verified with its public key")
+	}
+
+	var configs []witnessLogConfig
+	if err := viper.UnmarshalKey("witness.logs", &configs); err != nil {
+		return nil, fmt.Errorf("parsing witness.logs: %w", err)
+	}
+	if len(configs) == 0 {
+		return nil, errors.New("no logs to witness are configured in witness.logs")
+	}
+	w := &witness{
+		signer:    s,
+		keyID:     key.ID,
+		statePath: filepath.Join(viper.GetString("witness.state_dir"), witnessStateFile),
+	}
+	for _, cfg := range configs {
+		if cfg.PublicKey == "" {
+			return nil, fmt.Errorf("public_key must be set for log %v", cfg.URL)
+		}
+		b, err := ioutil.ReadFile(filepath.Clean(cfg.PublicKey))
+		if err != nil {
+			return nil, errors.Wrap(err, "reading public key of log")
+		}
+		keys, err := verify.ParseLogKeys(b)
+		if err != nil {
+			return nil, fmt.Errorf("public key of log %v: %w", cfg.URL, err)
+		}
+		c, err := app.GetRekorClientForLog(cfg.URL, cfg.Name)
+		if err != nil {
+			return nil, err
+		}
+		id := cfg.URL
+		if cfg.Name != "" {
+			id = fmt.Sprintf("%v/logs/%v", cfg.URL, cfg.Name)
+		}
+		w.logs = append(w.logs, &witnessedLog{id: id, client: c, keys: keys})
+	}
+
+	w.state = map[string]witnessState{}
+	b, err := ioutil.ReadFile(w.statePath)
+	switch {
+	case os.IsNotExist(err):
+	case err != nil:
+		return nil, errors.Wrap(err, "reading witness state")
+	default:
+		if err := json.Unmarshal(b, &w.state); err != nil {
+			return nil, errors.Wrap(err, "parsing witness state")
+		}
+	}
+	return w, nil
+}
+
+// witnessLog verifies the current tree head of the log against the last one verified, and cosigns it if it is
+// consistent; the first tree head seen of a tree is trusted
+func (w *witness) witnessLog(ctx context.Context, l *witnessedLog) error {
+	li, err := l.client.Tlog.GetLogInfo(nil)
+	if err != nil {
+		return errors.Wrap(err, "getting log info")
+	}
+	sth := li.Payload.SignedTreeHead
+	if sth == nil || sth.LogRoot == nil || sth.Signature == nil {
+		return errors.New("log info has no signed tree head")
+	}
+	var keyHint []byte
+	if sth.KeyHint != nil {
+		keyHint = *sth.KeyHint
+	}
+	lr, err := l.keys.SignedLogRoot(keyHint, *sth.LogRoot, *sth.Signature)
+	if err != nil {
+		return errors.Wrap(err, "verifying signed tree head")
+	}
+	treeID, err := strconv.ParseInt(li.Payload.TreeID, 10, 64)
+	if err != nil {
+		return errors.New("log does not give the tree ID of its signed tree head")
+	}
+
+	stateKey := fmt.Sprintf("%v#%v", l.id, li.Payload.TreeID)
+	last, ok := w.state[stateKey]
+	switch {
+	case !ok:
+		log.Logger.Infof("First tree head seen of %v, tree %d, at size %d", l.id, treeID, lr.TreeSize)
+	case lr.TreeSize < last.TreeSize:
+		return fmt.Errorf("tree %d shrank from size %d to %d", treeID, last.TreeSize, lr.TreeSize)
+	case lr.TreeSize == last.TreeSize:
+		if !bytes.Equal(lr.RootHash, last.RootHash) {
+			return fmt.Errorf("root hash of tree %d at size %d differs from the one seen before", treeID, lr.TreeSize)
+		}
+	case last.TreeSize > 0:
+		if err := app.ProveConsistency(l.client, li.Payload.TreeID, int64(last.TreeSize), last.RootHash, int64(lr.TreeSize), lr.RootHash); err != nil {
+			return fmt.Errorf("tree %d at size %d is not consistent with size %d: %w", treeID, lr.TreeSize, last.TreeSize, err)
+		}
+	}
+	w.state[stateKey] = witnessState{TreeSize: lr.TreeSize, RootHash: lr.RootHash}
+	if err := w.saveState(); err != nil {
+		return err
+	}
+
+	// the cosignature is submitted on each poll, so that instances of the log that have lost it learn it again
+	sig, _, err := w.signer.Sign(ctx, verify.CosignedMessage(treeID, *sth.LogRoot))
+	if err != nil {
+		return errors.Wrap(err, "signing tree head")
+	}
+	keyID := strfmt.Base64(w.keyID)
+	signature := strfmt.Base64(sig)
+	params := tlog.NewAddCosignatureParams().WithCosignature(&models.Cosignature{
+		TreeID:    swag.String(li.Payload.TreeID),
+		KeyHint:   &keyID,
+		LogRoot:   sth.LogRoot,
+		Signature: &signature,
+	})
+	if _, err := l.client.Tlog.AddCosignature(params); err != nil {
+		return errors.Wrap(err, "submitting cosignature")
+	}
+	log.Logger.Infof("Cosigned tree %d of %v at size %d", treeID, l.id, lr.TreeSize)
+	return nil
+}
+
+// saveState writes the state of the witness to a temporary file and renames it, so that the state is never lost
+// to a partial write
+func (w *witness) saveState() error {
+	b, err := json.Marshal(w.state)
+	if err != nil {
+		return err
+	}
+	tmp := w.statePath + ".tmp"
+	if err := ioutil.WriteFile(tmp, b, 0600); err != nil {
+		return errors.Wrap(err, "writing witness state")
+	}
+	return os.Rename(tmp, w.statePath)
+}
@@ -17,6 +17,7 @@ package api
 
 import (
 	"context"
+	"crypto"
 	"crypto/x509"
 	"encoding/pem"
 	"fmt"
@@ -24,66 +25,98 @@ import (
 
 	"github.com/google/trillian"
 	"github.com/google/trillian/client"
+	ttypes "github.com/google/trillian/types"
 	radix "github.com/mediocregopher/radix/v4"
 	"github.com/pkg/errors"
 	"github.com/spf13/viper"
-	"google.golang.org/grpc"
 
+	"github.com/sigstore/rekor/pkg/embeddedlog"
 	"github.com/sigstore/rekor/pkg/log"
+	"github.com/sigstore/rekor/pkg/pki"
+	"github.com/sigstore/rekor/pkg/pki/ssh"
 	"github.com/sigstore/rekor/pkg/signer"
+	"github.com/sigstore/rekor/pkg/types"
+	"github.com/sigstore/rekor/pkg/util"
+	"github.com/sigstore/rekor/pkg/verify"
 	"github.com/sigstore/sigstore/pkg/signature"
 )
 
-func dial(ctx context.Context, rpcServer string) (*grpc.ClientConn, error) {
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
-	defer cancel()
-
-	// Set up and test connection to rpc server
-	conn, err := grpc.DialContext(ctx, rpcServer, grpc.WithInsecure())
-	if err != nil {
-		log.Logger.Fatalf("Failed to connect to RPC server:", err)
-	}
-	return conn, nil
-}
-
 type API struct {
-	logClient trillian.TrillianLogClient
-	logID     int64
+	// name of the log, or empty for the default log
+	name string
+	// kinds of entries that the log accepts; all kinds are accepted if empty
+	allowedTypes []string
+
+	adminClient trillian.TrillianAdminClient
+	logClient   trillian.TrillianLogClient
+	logID       int64
 	// PEM encoded public key
-	pubkey   string
+	pubkey string
+	// ID of the public key, given as the key hint of signed tree heads
+	keyID []byte
+	// the current key followed by the keys that the log has signed with before
+	keys     verify.LogKeys
 	signer   signature.Signer
 	verifier *client.LogVerifier
+	// set in place of logClient when the embedded log backend is used
+	embeddedLog *embeddedlog.Log
+	// ordered by start index; the last shard is active
+	shards []*logShard
+	// keys of the witnesses whose cosignatures of tree heads the log accepts
+	witnesses verify.LogKeys
 }
 
+// NewAPI returns the default log, connecting to the Trillian log server if it is the configured backend
 func NewAPI() (*API, error) {
-	logRPCServer := fmt.Sprintf("%s:%d",
-		viper.GetString("trillian_log_server.address"),
-		viper.GetUint("trillian_log_server.port"))
 	ctx := context.Background()
-	tConn, err := dial(ctx, logRPCServer)
+	cfg, err := defaultLogConfig()
 	if err != nil {
 		return nil, err
 	}
-	logAdminClient := trillian.NewTrillianAdminClient(tConn)
-	logClient := trillian.NewTrillianLogClient(tConn)
 
-	tLogID := viper.GetInt64("trillian_log_server.tlog_id")
-	if tLogID == 0 {
-		t, err := createAndInitTree(ctx, logAdminClient, logClient)
+	var adminClient trillian.TrillianAdminClient
+	var logClient trillian.TrillianLogClient
+	switch backend := viper.GetString("log_backend"); backend {
+	case trillianLogBackend:
+		tConn, err := DialTrillian(ctx)
 		if err != nil {
 			return nil, err
 		}
-		tLogID = t.TreeId
+		adminClient = trillian.NewTrillianAdminClient(tConn)
+		logClient = trillian.NewTrillianLogClient(tConn)
+	case embeddedLogBackend:
+	default:
+		return nil, fmt.Errorf("unknown log backend '%v'; valid options are [%v, %v]", backend, trillianLogBackend, embeddedLogBackend)
 	}
+	return newLog(ctx, cfg, adminClient, logClient)
+}
 
-	t, err := logAdminClient.GetTree(ctx, &trillian.GetTreeRequest{
-		TreeId: tLogID,
-	})
-	if err != nil {
-		return nil, err
+// newLog returns the log with the config given, using the clients given if the Trillian backend is configured
+func newLog(ctx context.Context, cfg logConfig, adminClient trillian.TrillianAdminClient, logClient trillian.TrillianLogClient) (*API, error) {
+	a := &API{
+		name:         cfg.Name,
+		allowedTypes: cfg.AllowedTypes,
+	}
+	switch viper.GetString("log_backend") {
+	case trillianLogBackend:
+		if err := a.connectTrillian(ctx, cfg, adminClient, logClient); err != nil {
+			return nil, err
+		}
+	case embeddedLogBackend:
+		if len(cfg.Shards) > 0 {
+			return nil, errors.New("shards cannot be used with the embedded log backend")
+		}
+		l, err := embeddedlog.Open(cfg.EmbeddedLogPath)
+		if err != nil {
+			return nil, err
+		}
+		a.embeddedLog = l
+		a.logID = l.ID()
+		a.shards = []*logShard{{treeID: a.logID}}
+		log.Logger.Infof("Using embedded log %v at %v", a.logID, cfg.EmbeddedLogPath)
 	}
 
-	signer, err := signer.New(ctx, viper.GetString("rekor_server.signer"))
+	signer, err := signer.New(ctx, cfg.Signer)
 	if err != nil {
 		return nil, errors.Wrap(err, "getting new signer")
 	}
@@ -91,6 +124,9 @@ func NewAPI() (*API, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "getting public key")
 	}
+	if err := checkSigningScheme(ctx, signer, pk); err != nil {
+		return nil, err
+	}
 	b, err := x509.MarshalPKIXPublicKey(pk)
 	if err != nil {
 		return nil, errors.Wrap(err, "marshalling public key")
@@ -99,19 +135,77 @@ func NewAPI() (*API, error) {
 		Type:  "PUBLIC KEY",
 		Bytes: b,
 	})
+	a.pubkey = string(pubkey)
+	a.signer = signer
+	for _, shard := range a.shards {
+		if shard.pubkey == "" {
+			shard.pubkey = a.pubkey
+		}
+	}
+	if a.keys, err = newLogKeys(pk, cfg.HistoricalKeys, a.shards); err != nil {
+		return nil, err
+	}
+	a.keyID = a.keys[0].ID
+	log.Logger.Infof("Signing tree heads with key %x", a.keyID)
+	if a.witnesses, err = verify.LoadLogKeys(cfg.WitnessKeys); err != nil {
+		return nil, fmt.Errorf("loading witness keys: %w", err)
+	}
+	for _, k := range a.witnesses {
+		log.Logger.Infof("Accepting cosignatures from witness %x", k.ID)
+	}
+
+	return a, nil
+}
 
-	verifier, err := client.NewLogVerifierFromTree(t)
+// checkSigningScheme signs a log root with the signer, and checks that clients can verify it with the public key;
+// signers that do not sign with the scheme clients expect for the key are rejected
+func checkSigningScheme(ctx context.Context, signer signature.Signer, pk crypto.PublicKey) error {
+	logRoot, err := (&ttypes.LogRootV1{RootHash: make([]byte, 32)}).MarshalBinary()
 	if err != nil {
-		return nil, err
+		return err
+	}
+	sig, _, err := signer.Sign(ctx, logRoot)
+	if err != nil {
+		return errors.Wrap(err, "signing log root")
 	}
+	if _, err := verify.SignedLogRoot(pk, logRoot, sig); err != nil {
+		return errors.Wrap(err, "log roots signed by the signer cannot be verified with its public key")
+	}
+	return nil
+}
 
-	return &API{
-		logClient: logClient,
-		logID:     tLogID,
-		pubkey:    string(pubkey),
-		signer:    signer,
-		verifier:  verifier,
-	}, nil
+// connectTrillian loads the shards of the log from the Trillian log server, creating a tree for the active shard
+// if none is configured
+func (a *API) connectTrillian(ctx context.Context, cfg logConfig, adminClient trillian.TrillianAdminClient, logClient trillian.TrillianLogClient) error {
+	// the tree of the active shard may be given in the list of shards, or by the tree ID of the log
+	tLogID := cfg.TreeID
+	if len(cfg.Shards) > 0 && cfg.Shards[len(cfg.Shards)-1].TreeID != 0 {
+		tLogID = cfg.Shards[len(cfg.Shards)-1].TreeID
+	}
+	if tLogID == 0 {
+		if viper.GetBool("trillian_log_server.require_tlog_id") {
+			return errors.New("a tree ID must be configured for each log when trillian_log_server.require_tlog_id is set; create one with 'rekor-server tree create'")
+		}
+		t, err := createAndInitTree(ctx, adminClient, logClient)
+		if err != nil {
+			return err
+		}
+		log.Logger.Warnf("No tree ID configured; using tree %d. Set trillian_log_server.tlog_id to use a specific tree", t.TreeId)
+		tLogID = t.TreeId
+	}
+
+	shards, err := newTrillianShards(ctx, adminClient, logClient, cfg.Shards, tLogID)
+	if err != nil {
+		return err
+	}
+	active := shards[len(shards)-1]
+
+	a.adminClient = adminClient
+	a.logClient = logClient
+	a.logID = active.treeID
+	a.verifier = active.verifier
+	a.shards = shards
+	return nil
 }
 
 var (
@@ -126,6 +220,38 @@ func ConfigureAPI() {
 	if err != nil {
 		log.Logger.Panic(err)
 	}
+	if err := configureLogs(); err != nil {
+		log.Logger.Panic(err)
+	}
+	if err := configureTrustRoots(); err != nil {
+		log.Logger.Panic(err)
+	}
+	configurePolicy()
+	util.SetFetchConfig(util.FetchConfig{
+		ConnectTimeout:        viper.GetDuration("fetch.connect_timeout"),
+		ReadTimeout:           viper.GetDuration("fetch.read_timeout"),
+		MaxBodySize:           viper.GetInt64("fetch.max_body_size"),
+		MaxRedirects:          viper.GetInt("fetch.max_redirects"),
+		AllowedSchemes:        viper.GetStringSlice("fetch.allowed_schemes"),
+		AllowedHosts:          viper.GetStringSlice("fetch.allowed_hosts"),
+		BlockPrivateAddresses: viper.GetBool("fetch.block_private_addresses"),
+	})
+	if err := util.SetFetchCacheConfig(util.FetchCacheConfig{
+		MaxMemorySize: viper.GetInt64("fetch.cache.max_memory_size"),
+		MaxEntrySize:  viper.GetInt64("fetch.cache.max_entry_size"),
+		TTL:           viper.GetDuration("fetch.cache.ttl"),
+		Dir:           viper.GetString("fetch.cache.dir"),
+		MaxDiskSize:   viper.GetInt64("fetch.cache.max_disk_size"),
+	}); err != nil {
+		log.Logger.Panic(err)
+	}
+	if err := ssh.SetAllowedNamespaces(viper.GetStringSlice("ssh.allowed_namespaces")); err != nil {
+		log.Logger.Panic(err)
+	}
+	ssh.SetSecurityKeyPolicy(viper.GetBool("ssh.require_user_presence"), viper.GetBool("ssh.require_user_verification"))
+	if viper.GetBool("async_create.enabled") {
+		go sweepPendingEntries(context.Background(), time.Minute)
+	}
 	if viper.GetBool("enable_retrieve_api") {
 		redisClient, err = cfg.New(context.Background(), "tcp", fmt.Sprintf("%v:%v", viper.GetString("redis_server.address"), viper.GetUint64("redis_server.port")))
 		if err != nil {
@@ -133,3 +259,64 @@ func ConfigureAPI() {
 		}
 	}
 }
+
+// configureTrustRoots loads the root certificates that certificate chains supplied in entries must terminate in;
+// roots can be configured for all entry types (trust_roots.default) and/or for a specific type (trust_roots.<kind>)
+func configureTrustRoots() error {
+	if err := pki.SetUntrustedRootPolicy(pki.UntrustedRootPolicy(viper.GetString("trust_roots.untrusted_policy"))); err != nil {
+		return err
+	}
+
+	kinds := []string{pki.DefaultTrustRoots}
+	types.TypeMap.Range(func(k, _ interface{}) bool {
+		kinds = append(kinds, k.(string))
+		return true
+	})
+	for _, kind := range kinds {
+		path := viper.GetString("trust_roots." + kind)
+		if path == "" {
+			continue
+		}
+		pool, err := pki.LoadCertPool(path)
+		if err != nil {
+			return errors.Wrapf(err, "loading trust roots for '%v'", kind)
+		}
+		pki.TrustRootsMap.Store(kind, pool)
+		log.Logger.Infof("Loaded trust roots for '%v' from %v", kind, path)
+	}
+	return nil
+}
+
+// configurePolicy loads the admission policy for key material and signatures; settings under policy.default apply
+// to all formats, and can be overridden for an individual format under policy.<format> in the config file
+func configurePolicy() {
+	if !viper.GetBool("policy.enabled") {
+		log.Logger.Warn("Key and signature admission policy is disabled")
+		return
+	}
+	def := policyFromConfig("policy."+pki.DefaultPolicyKey, pki.DefaultPolicy())
+	pki.PolicyMap.Store(pki.DefaultPolicyKey, def)
+	for _, format := range pki.SupportedFormats() {
+		if !viper.IsSet("policy." + format) {
+			continue
+		}
+		pki.PolicyMap.Store(format, policyFromConfig("policy."+format, def))
+		log.Logger.Infof("Loaded admission policy for format '%v'", format)
+	}
+}
+
+func policyFromConfig(prefix string, p pki.Policy) pki.Policy {
+	if viper.IsSet(prefix + ".min_rsa_bits") {
+		p.MinRSABits = viper.GetInt(prefix + ".min_rsa_bits")
+	}
+	if viper.IsSet(prefix + ".allowed_key_types") {
+		p.AllowedKeyTypes = viper.GetStringSlice(prefix + ".allowed_key_types")
+	}
+	if viper.IsSet(prefix + ".allowed_curves") {
+		p.AllowedCurves = viper.GetStringSlice(prefix + ".allowed_curves")
+	}
+	if viper.IsSet(prefix + ".allowed_hashes") {
+		p.AllowedHashes = viper.GetStringSlice(prefix + ".allowed_hashes")
+	}
+	return p
+}
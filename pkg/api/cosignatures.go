@@ -0,0 +1,126 @@
+//
+// Copyright 2021 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/google/trillian/merkle/logverifier"
+	rfc6962 "github.com/google/trillian/merkle/rfc6962/hasher"
+	"github.com/google/trillian/types"
+	"google.golang.org/grpc/codes"
+
+	"github.com/sigstore/rekor/pkg/generated/models"
+	"github.com/sigstore/rekor/pkg/generated/restapi/operations/tlog"
+)
+
+// cosignatures holds the latest cosignature of each witness of each log, keyed by the ID of the key of the witness
+// qualified by the name of the log. Witnesses submit their cosignature each time they poll the log, so
+// cosignatures held by another instance of the server, or lost on a restart, are soon learnt again.
+var cosignatures sync.Map
+
+// cosignaturesMu serializes the replacement of cosignatures, so that one over a smaller tree head cannot replace one
+// over a larger tree head stored concurrently
+var cosignaturesMu sync.Mutex
+
+// storedCosignature is a cosignature accepted by the log, with the size of the tree head it is over
+type storedCosignature struct {
+	treeSize    uint64
+	cosignature *models.Cosignature
+}
+
+// AddCosignatureHandler accepts the cosignature of a witness over a tree head of the active shard, once it is
+// verified with the key of the witness and the tree head is found to be consistent with the current tree. Only the
+// cosignature over the largest tree head is kept for each witness.
+func AddCosignatureHandler(params tlog.AddCosignatureParams) middleware.Responder {
+	ctx := params.HTTPRequest.Context()
+	a := logFromContext(ctx)
+	c := params.Cosignature
+
+	if a.witnesses.ByHint(*c.KeyHint) == nil {
+		return handleRekorAPIError(params, http.StatusBadRequest, nil, fmt.Sprintf(unknownWitness, []byte(*c.KeyHint)))
+	}
+	treeID, err := strconv.ParseInt(*c.TreeID, 10, 64)
+	if err != nil {
+		return handleRekorAPIError(params, http.StatusBadRequest, err, err.Error())
+	}
+	if treeID != a.logID {
+		return handleRekorAPIError(params, http.StatusBadRequest, nil, fmt.Sprintf(cosignatureNotOfActiveShard, treeID))
+	}
+	root, err := a.witnesses.Cosignature(*c.KeyHint, treeID, *c.LogRoot, *c.Signature)
+	if err != nil {
+		return handleRekorAPIError(params, http.StatusBadRequest, err, invalidCosignature)
+	}
+
+	// the cosigned tree head must be one that the log has produced
+	tc := NewShardedClient(ctx)
+	resp := tc.getLatest(treeID, 0)
+	if resp.status != codes.OK {
+		return handleRekorAPIError(params, http.StatusInternalServerError, fmt.Errorf("grpc error: %w", resp.err), trillianCommunicationError)
+	}
+	var latest types.LogRootV1
+	if err := latest.UnmarshalBinary(resp.getLatestResult.SignedLogRoot.LogRoot); err != nil {
+		return handleRekorAPIError(params, http.StatusInternalServerError, err, trillianUnexpectedResult)
+	}
+	if root.TreeSize > latest.TreeSize {
+		return handleRekorAPIError(params, http.StatusBadRequest, nil, fmt.Sprintf(cosignedSizeGreaterThanKnown, root.TreeSize, latest.TreeSize))
+	}
+	var proof [][]byte
+	if root.TreeSize > 0 && root.TreeSize < latest.TreeSize {
+		resp := tc.getConsistencyProof(treeID, int64(root.TreeSize), int64(latest.TreeSize))
+		if resp.status != codes.OK {
+			return handleRekorAPIError(params, http.StatusInternalServerError, fmt.Errorf("grpc error: %w", resp.err), trillianCommunicationError)
+		}
+		proof = resp.getConsistencyProofResult.GetProof().GetHashes()
+	}
+	v := logverifier.New(rfc6962.DefaultHasher)
+	if err := v.VerifyConsistencyProof(int64(root.TreeSize), int64(latest.TreeSize), root.RootHash, latest.RootHash, proof); err != nil {
+		return handleRekorAPIError(params, http.StatusBadRequest, err, inconsistentCosignature)
+	}
+
+	storeCosignature(a.namespaced(hex.EncodeToString(*c.KeyHint)), storedCosignature{treeSize: root.TreeSize, cosignature: c})
+	return tlog.NewAddCosignatureCreated().WithPayload(c)
+}
+
+// storeCosignature stores the cosignature under the key unless a cosignature over a larger tree head is stored
+func storeCosignature(key string, c storedCosignature) {
+	cosignaturesMu.Lock()
+	defer cosignaturesMu.Unlock()
+	if v, ok := cosignatures.Load(key); !ok || v.(storedCosignature).treeSize <= c.treeSize {
+		cosignatures.Store(key, c)
+	}
+}
+
+// logCosignatures returns the latest cosignature of each witness of the log over a tree head of the tree given
+func (a *API) logCosignatures(treeID int64) []*models.Cosignature {
+	var result []*models.Cosignature
+	for _, w := range a.witnesses {
+		v, ok := cosignatures.Load(a.namespaced(hex.EncodeToString(w.ID)))
+		if !ok {
+			continue
+		}
+		c := v.(storedCosignature).cosignature
+		if *c.TreeID == strconv.FormatInt(treeID, 10) {
+			result = append(result, c)
+		}
+	}
+	return result
+}
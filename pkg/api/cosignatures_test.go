@@ -0,0 +1,52 @@
+//
+// Copyright 2021 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+func TestStoreCosignature(t *testing.T) {
+	const key = "witness"
+	t.Cleanup(func() { cosignatures.Delete(key) })
+
+	sizes := rand.Perm(1000)
+	var wg sync.WaitGroup
+	for _, size := range sizes {
+		wg.Add(1)
+		go func(size uint64) {
+			defer wg.Done()
+			storeCosignature(key, storedCosignature{treeSize: size})
+		}(uint64(size))
+	}
+	wg.Wait()
+
+	v, ok := cosignatures.Load(key)
+	if !ok {
+		t.Fatal("expected a cosignature to be stored")
+	}
+	if got := v.(storedCosignature).treeSize; got != uint64(len(sizes)-1) {
+		t.Errorf("expected the cosignature over the largest tree head to be kept, got tree size %d", got)
+	}
+
+	storeCosignature(key, storedCosignature{treeSize: 10})
+	v, _ = cosignatures.Load(key)
+	if got := v.(storedCosignature).treeSize; got != uint64(len(sizes)-1) {
+		t.Errorf("expected a cosignature over a smaller tree head not to replace it, got tree size %d", got)
+	}
+}
@@ -0,0 +1,223 @@
+//
+// Copyright 2021 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/trillian"
+	"github.com/google/trillian/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/sigstore/rekor/pkg/embeddedlog"
+)
+
+// EmbeddedClient adds entries to and reads them from the log kept in process, rather than by a Trillian log server;
+// leaves are included in the log as soon as they are added
+type EmbeddedClient struct {
+	log     *embeddedlog.Log
+	logID   int64
+	context context.Context
+}
+
+func NewEmbeddedClient(ctx context.Context) *EmbeddedClient {
+	a := logFromContext(ctx)
+	return &EmbeddedClient{
+		log:     a.embeddedLog,
+		logID:   a.logID,
+		context: ctx,
+	}
+}
+
+// embeddedError returns the error with the gRPC status code that Trillian would have returned in its place
+func embeddedError(err error) error {
+	switch {
+	case errors.Is(err, embeddedlog.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, embeddedlog.ErrOutOfRange):
+		return status.Error(codes.OutOfRange, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+func errorResponse(err error) *Response {
+	err = embeddedError(err)
+	return &Response{
+		status: status.Code(err),
+		err:    err,
+	}
+}
+
+func embeddedLogLeaf(leaf embeddedlog.Leaf) *trillian.LogLeaf {
+	return &trillian.LogLeaf{
+		MerkleLeafHash:     leaf.Hash,
+		LeafValue:          leaf.Value,
+		LeafIndex:          leaf.Index,
+		LeafIdentityHash:   leaf.Hash,
+		QueueTimestamp:     timestamppb.New(leaf.IntegratedTime),
+		IntegrateTimestamp: timestamppb.New(leaf.IntegratedTime),
+	}
+}
+
+func embeddedSignedLogRoot(root embeddedlog.Root) (*trillian.SignedLogRoot, error) {
+	logRoot := &types.LogRootV1{
+		TreeSize: root.TreeSize,
+		RootHash: root.RootHash,
+		Revision: root.TreeSize,
+	}
+	if !root.Timestamp.IsZero() {
+		logRoot.TimestampNanos = uint64(root.Timestamp.UnixNano())
+	}
+	b, err := logRoot.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return &trillian.SignedLogRoot{LogRoot: b}, nil
+}
+
+func (e *EmbeddedClient) addLeaf(byteValue []byte) *Response {
+	return e.addLeaves([][]byte{byteValue}, 1)[0]
+}
+
+func (e *EmbeddedClient) queueLeaf(byteValue []byte) *Response {
+	return e.addLeaf(byteValue)
+}
+
+// addLeaves appends all of the leaves to the log at once; the concurrency is ignored, as leaves are included
+// without waiting on anything else
+func (e *EmbeddedClient) addLeaves(byteValues [][]byte, _ int) []*Response {
+	responses := make([]*Response, len(byteValues))
+	results, err := e.log.Append(byteValues)
+	if err != nil {
+		for i := range responses {
+			responses[i] = errorResponse(err)
+		}
+		return responses
+	}
+	for i, result := range results {
+		queuedLeaf := &trillian.QueuedLogLeaf{
+			Leaf: embeddedLogLeaf(result.Leaf),
+		}
+		if result.Existing {
+			queuedLeaf.Status = status.New(codes.AlreadyExists, "leaf already exists").Proto()
+		}
+		responses[i] = &Response{
+			status:       codes.OK,
+			getAddResult: &trillian.QueueLeafResponse{QueuedLeaf: queuedLeaf},
+		}
+	}
+	return responses
+}
+
+func (e *EmbeddedClient) getLeafAndProofByIndex(index int64) *Response {
+	root, err := e.log.Root()
+	if err != nil {
+		return errorResponse(err)
+	}
+	if index < 0 || uint64(index) >= root.TreeSize {
+		return errorResponse(embeddedlog.ErrOutOfRange)
+	}
+	leaf, err := e.log.LeafByIndex(index)
+	if err != nil {
+		return errorResponse(err)
+	}
+	hashes, err := e.log.InclusionProof(index, root.TreeSize)
+	if err != nil {
+		return errorResponse(err)
+	}
+	signedLogRoot, err := embeddedSignedLogRoot(root)
+	if err != nil {
+		return errorResponse(err)
+	}
+	return &Response{
+		status: codes.OK,
+		getLeafAndProofResult: &trillian.GetEntryAndProofResponse{
+			Proof: &trillian.Proof{
+				LeafIndex: index,
+				Hashes:    hashes,
+			},
+			Leaf:          embeddedLogLeaf(leaf),
+			SignedLogRoot: signedLogRoot,
+		},
+	}
+}
+
+func (e *EmbeddedClient) getLeafAndProofByHash(hash []byte) *Response {
+	leaf, err := e.log.LeafByHash(hash)
+	if err != nil {
+		return errorResponse(err)
+	}
+	return e.getLeafAndProofByIndex(leaf.Index)
+}
+
+func (e *EmbeddedClient) getLatest(firstSize int64) *Response {
+	root, err := e.log.Root()
+	if err != nil {
+		return errorResponse(err)
+	}
+	signedLogRoot, err := embeddedSignedLogRoot(root)
+	if err != nil {
+		return errorResponse(err)
+	}
+	result := &trillian.GetLatestSignedLogRootResponse{
+		SignedLogRoot: signedLogRoot,
+	}
+	if firstSize > 0 && uint64(firstSize) <= root.TreeSize {
+		hashes, err := e.log.ConsistencyProof(uint64(firstSize), root.TreeSize)
+		if err != nil {
+			return errorResponse(err)
+		}
+		result.Proof = &trillian.Proof{Hashes: hashes}
+	}
+	return &Response{
+		status:          codes.OK,
+		getLatestResult: result,
+	}
+}
+
+// getConsistencyProof returns the proof between the two sizes; as with Trillian, no proof is returned if the log
+// has not yet grown to lastSize
+func (e *EmbeddedClient) getConsistencyProof(firstSize, lastSize int64) *Response {
+	if firstSize < 0 || firstSize > lastSize {
+		return errorResponse(embeddedlog.ErrOutOfRange)
+	}
+	root, err := e.log.Root()
+	if err != nil {
+		return errorResponse(err)
+	}
+	signedLogRoot, err := embeddedSignedLogRoot(root)
+	if err != nil {
+		return errorResponse(err)
+	}
+	result := &trillian.GetConsistencyProofResponse{
+		SignedLogRoot: signedLogRoot,
+	}
+	if uint64(lastSize) <= root.TreeSize {
+		hashes, err := e.log.ConsistencyProof(uint64(firstSize), uint64(lastSize))
+		if err != nil {
+			return errorResponse(err)
+		}
+		result.Proof = &trillian.Proof{Hashes: hashes}
+	}
+	return &Response{
+		status:                    codes.OK,
+		getConsistencyProofResult: result,
+	}
+}
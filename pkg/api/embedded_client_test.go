@@ -0,0 +1,195 @@
+//
+// Copyright 2021 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/trillian"
+	"github.com/google/trillian/merkle/logverifier"
+	rfc6962 "github.com/google/trillian/merkle/rfc6962/hasher"
+	"github.com/google/trillian/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sigstore/rekor/pkg/embeddedlog"
+)
+
+func newTestEmbeddedClient(t *testing.T) *EmbeddedClient {
+	t.Helper()
+	l, err := embeddedlog.Open(filepath.Join(t.TempDir(), "log.db"))
+	if err != nil {
+		t.Fatalf("unexpected error opening log: %v", err)
+	}
+	t.Cleanup(func() { _ = l.Close() })
+	return &EmbeddedClient{log: l, logID: l.ID(), context: context.Background()}
+}
+
+func testLeaves(start, end int) [][]byte {
+	values := [][]byte{}
+	for i := start; i < end; i++ {
+		values = append(values, []byte(fmt.Sprintf("leaf %d", i)))
+	}
+	return values
+}
+
+func unmarshalLogRoot(t *testing.T, slr *trillian.SignedLogRoot) types.LogRootV1 {
+	t.Helper()
+	var root types.LogRootV1
+	if err := root.UnmarshalBinary(slr.LogRoot); err != nil {
+		t.Fatalf("unexpected error unmarshalling log root: %v", err)
+	}
+	return root
+}
+
+func expectResponseCode(t *testing.T, name string, resp *Response, want codes.Code) {
+	t.Helper()
+	if resp.status != want || status.Code(resp.err) != want {
+		t.Errorf("%v: expected code %v, got %v (%v)", name, want, resp.status, resp.err)
+	}
+}
+
+func TestEmbeddedClientAddLeaves(t *testing.T) {
+	e := newTestEmbeddedClient(t)
+
+	responses := e.addLeaves(testLeaves(0, 3), 1)
+	if len(responses) != 3 {
+		t.Fatalf("expected 3 responses, got %d", len(responses))
+	}
+	for i, resp := range responses {
+		expectResponseCode(t, "new leaf", resp, codes.OK)
+		queued := resp.getAddResult.QueuedLeaf
+		if queued.Status != nil {
+			t.Errorf("leaf %d: expected no status for a new leaf, got %v", i, queued.Status)
+		}
+		if queued.Leaf.LeafIndex != int64(i) || string(queued.Leaf.LeafValue) != fmt.Sprintf("leaf %d", i) {
+			t.Errorf("leaf %d: unexpected leaf %+v", i, queued.Leaf)
+		}
+		if queued.Leaf.IntegrateTimestamp == nil {
+			t.Errorf("leaf %d: expected the leaf to be integrated", i)
+		}
+	}
+
+	// a batch mixing new leaves and duplicates, including one repeated within the batch
+	batch := [][]byte{[]byte("leaf 1"), []byte("leaf 3"), []byte("leaf 3"), []byte("leaf 4")}
+	responses = e.addLeaves(batch, 4)
+	wantIndexes := []int64{1, 3, 3, 4}
+	wantExisting := []bool{true, false, true, false}
+	for i, resp := range responses {
+		expectResponseCode(t, "batch", resp, codes.OK)
+		queued := resp.getAddResult.QueuedLeaf
+		if queued.Leaf.LeafIndex != wantIndexes[i] {
+			t.Errorf("batch leaf %d: expected index %d, got %d", i, wantIndexes[i], queued.Leaf.LeafIndex)
+		}
+		existing := queued.Status != nil && codes.Code(queued.Status.Code) == codes.AlreadyExists
+		if existing != wantExisting[i] {
+			t.Errorf("batch leaf %d: expected existing %v, got status %v", i, wantExisting[i], queued.Status)
+		}
+	}
+
+	resp := e.addLeaf([]byte("leaf 0"))
+	if resp.getAddResult.QueuedLeaf.Leaf.LeafIndex != 0 || codes.Code(resp.getAddResult.QueuedLeaf.Status.GetCode()) != codes.AlreadyExists {
+		t.Errorf("expected leaf 0 to already exist, got %+v", resp.getAddResult.QueuedLeaf)
+	}
+
+	root, err := e.log.Root()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root.TreeSize != 5 {
+		t.Errorf("expected duplicates not to grow the log, got size %d", root.TreeSize)
+	}
+}
+
+func TestEmbeddedClientLeafAndProof(t *testing.T) {
+	e := newTestEmbeddedClient(t)
+	e.addLeaves(testLeaves(0, 7), 1)
+	verifier := logverifier.New(rfc6962.DefaultHasher)
+
+	for i := int64(0); i < 7; i++ {
+		byIndex := e.getLeafAndProofByIndex(i)
+		expectResponseCode(t, "by index", byIndex, codes.OK)
+		result := byIndex.getLeafAndProofResult
+		root := unmarshalLogRoot(t, result.SignedLogRoot)
+		if root.TreeSize != 7 {
+			t.Errorf("index %d: expected the proof to be against size 7, got %d", i, root.TreeSize)
+		}
+		if result.Leaf.LeafIndex != i || result.Proof.LeafIndex != i {
+			t.Errorf("index %d: unexpected leaf %+v or proof %+v", i, result.Leaf, result.Proof)
+		}
+		if err := verifier.VerifyInclusionProof(i, int64(root.TreeSize), result.Proof.Hashes, root.RootHash, result.Leaf.MerkleLeafHash); err != nil {
+			t.Errorf("index %d: inclusion proof does not verify: %v", i, err)
+		}
+
+		byHash := e.getLeafAndProofByHash(result.Leaf.MerkleLeafHash)
+		expectResponseCode(t, "by hash", byHash, codes.OK)
+		if byHash.getLeafAndProofResult.Leaf.LeafIndex != i {
+			t.Errorf("hash of leaf %d: expected the same leaf, got index %d", i, byHash.getLeafAndProofResult.Leaf.LeafIndex)
+		}
+	}
+
+	expectResponseCode(t, "negative index", e.getLeafAndProofByIndex(-1), codes.OutOfRange)
+	expectResponseCode(t, "index past end", e.getLeafAndProofByIndex(7), codes.OutOfRange)
+	expectResponseCode(t, "unknown hash", e.getLeafAndProofByHash(rfc6962.DefaultHasher.HashLeaf([]byte("leaf 7"))), codes.NotFound)
+}
+
+func TestEmbeddedClientConsistencyProof(t *testing.T) {
+	e := newTestEmbeddedClient(t)
+	verifier := logverifier.New(rfc6962.DefaultHasher)
+
+	roots := map[int64][]byte{}
+	for size := 1; size <= 9; size++ {
+		e.addLeaves(testLeaves(size-1, size), 1)
+		latest := e.getLatest(0)
+		expectResponseCode(t, "latest", latest, codes.OK)
+		roots[int64(size)] = unmarshalLogRoot(t, latest.getLatestResult.SignedLogRoot).RootHash
+	}
+
+	for first := int64(1); first <= 9; first++ {
+		for last := first; last <= 9; last++ {
+			resp := e.getConsistencyProof(first, last)
+			expectResponseCode(t, "consistency", resp, codes.OK)
+			if resp.getConsistencyProofResult.Proof == nil {
+				t.Fatalf("%d to %d: expected a proof", first, last)
+			}
+			if err := verifier.VerifyConsistencyProof(first, last, roots[first], roots[last], resp.getConsistencyProofResult.Proof.Hashes); err != nil {
+				t.Errorf("%d to %d: consistency proof does not verify: %v", first, last, err)
+			}
+		}
+	}
+
+	// as with Trillian, no proof is returned until the log reaches the last size
+	resp := e.getConsistencyProof(3, 12)
+	expectResponseCode(t, "size past end", resp, codes.OK)
+	if resp.getConsistencyProofResult.Proof != nil {
+		t.Errorf("expected no proof to a size the log has not reached, got %v", resp.getConsistencyProofResult.Proof)
+	}
+	if root := unmarshalLogRoot(t, resp.getConsistencyProofResult.SignedLogRoot); root.TreeSize != 9 {
+		t.Errorf("expected the current root, got size %d", root.TreeSize)
+	}
+
+	expectResponseCode(t, "negative first size", e.getConsistencyProof(-1, 3), codes.OutOfRange)
+	expectResponseCode(t, "first size after last", e.getConsistencyProof(5, 3), codes.OutOfRange)
+
+	latest := e.getLatest(4)
+	expectResponseCode(t, "latest with proof", latest, codes.OK)
+	if err := verifier.VerifyConsistencyProof(4, 9, roots[4], roots[9], latest.getLatestResult.Proof.GetHashes()); err != nil {
+		t.Errorf("latest: consistency proof from size 4 does not verify: %v", err)
+	}
+}
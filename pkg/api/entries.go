@@ -22,6 +22,9 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"path"
+	"strings"
+	"time"
 
 	"github.com/go-openapi/runtime/middleware"
 	"github.com/go-openapi/strfmt"
@@ -32,16 +35,22 @@ import (
 	"github.com/spf13/viper"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/genproto/googleapis/rpc/code"
+	rpcstatus "google.golang.org/genproto/googleapis/rpc/status"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/sigstore/rekor/pkg/generated/models"
 	"github.com/sigstore/rekor/pkg/generated/restapi/operations/entries"
 	"github.com/sigstore/rekor/pkg/log"
+	"github.com/sigstore/rekor/pkg/pki"
+	"github.com/sigstore/rekor/pkg/sharding"
 	"github.com/sigstore/rekor/pkg/types"
+	"github.com/sigstore/rekor/pkg/util"
 )
 
-// logEntryFromLeaf creates LogEntry struct from trillian structs
-func logEntryFromLeaf(tc TrillianClient, leaf *trillian.LogLeaf, signedLogRoot *trillian.SignedLogRoot, proof *trillian.Proof) (models.LogEntry, error) {
+// logEntryFromLeaf creates LogEntry struct from trillian structs; the inclusion proof is relative to the tree of
+// the shard holding the leaf
+func logEntryFromLeaf(treeID int64, leaf *trillian.LogLeaf, signedLogRoot *trillian.SignedLogRoot, proof *trillian.Proof) (models.LogEntry, error) {
 
 	root := &ttypes.LogRootV1{}
 	if err := root.UnmarshalBinary(signedLogRoot.LogRoot); err != nil {
@@ -60,7 +69,7 @@ func logEntryFromLeaf(tc TrillianClient, leaf *trillian.LogLeaf, signedLogRoot *
 	}
 
 	logEntry := models.LogEntry{
-		hex.EncodeToString(leaf.MerkleLeafHash): models.LogEntryAnon{
+		entryIDFromLeafHash(treeID, leaf.MerkleLeafHash): models.LogEntryAnon{
 			LogIndex:       &leaf.LeafIndex,
 			Body:           leaf.LeafValue,
 			IntegratedTime: leaf.IntegrateTimestamp.AsTime().Unix(),
@@ -73,7 +82,7 @@ func logEntryFromLeaf(tc TrillianClient, leaf *trillian.LogLeaf, signedLogRoot *
 
 // GetLogEntryAndProofByIndexHandler returns the entry and inclusion proof for a specified log index
 func GetLogEntryByIndexHandler(params entries.GetLogEntryByIndexParams) middleware.Responder {
-	tc := NewTrillianClient(params.HTTPRequest.Context())
+	tc := NewShardedClient(params.HTTPRequest.Context())
 
 	resp := tc.getLeafAndProofByIndex(params.LogIndex)
 	switch resp.status {
@@ -90,7 +99,7 @@ func GetLogEntryByIndexHandler(params entries.GetLogEntryByIndexParams) middlewa
 		return handleRekorAPIError(params, http.StatusNotFound, errors.New("grpc returned 0 leaves with success code"), "")
 	}
 
-	logEntry, err := logEntryFromLeaf(tc, leaf, result.SignedLogRoot, result.Proof)
+	logEntry, err := logEntryFromLeaf(resp.logID, leaf, result.SignedLogRoot, result.Proof)
 	if err != nil {
 		return handleRekorAPIError(params, http.StatusInternalServerError, err, err.Error())
 	}
@@ -101,6 +110,9 @@ func GetLogEntryByIndexHandler(params entries.GetLogEntryByIndexParams) middlewa
 // CreateLogEntryHandler creates new entry into log
 func CreateLogEntryHandler(params entries.CreateLogEntryParams) middleware.Responder {
 	httpReq := params.HTTPRequest
+	if err := checkKindAllowed(httpReq.Context(), params.ProposedEntry.Kind()); err != nil {
+		return handleRekorAPIError(params, http.StatusBadRequest, err, err.Error())
+	}
 	entry, err := types.NewEntry(params.ProposedEntry)
 	if err != nil {
 		return handleRekorAPIError(params, http.StatusBadRequest, err, err.Error())
@@ -108,10 +120,15 @@ func CreateLogEntryHandler(params entries.CreateLogEntryParams) middleware.Respo
 
 	leaf, err := entry.Canonicalize(httpReq.Context())
 	if err != nil {
-		return handleRekorAPIError(params, http.StatusInternalServerError, err, failedToGenerateCanonicalEntry)
+		code, message := canonicalizationError(err)
+		return handleRekorAPIError(params, code, err, message)
+	}
+
+	if swag.BoolValue(params.Async) && viper.GetBool("async_create.enabled") {
+		return queueLogEntry(params, entry, leaf)
 	}
 
-	tc := NewTrillianClient(httpReq.Context())
+	tc := NewShardedClient(httpReq.Context())
 
 	resp := tc.addLeaf(leaf)
 	// this represents overall GRPC response state (not the results of insertion into the log)
@@ -120,23 +137,15 @@ func CreateLogEntryHandler(params entries.CreateLogEntryParams) middleware.Respo
 	}
 
 	// this represents the results of inserting the proposed leaf into the log; status is nil in success path
-	insertionStatus := resp.getAddResult.QueuedLeaf.Status
-	if insertionStatus != nil {
-		switch insertionStatus.Code {
-		case int32(code.Code_OK):
-		case int32(code.Code_ALREADY_EXISTS), int32(code.Code_FAILED_PRECONDITION):
-			existingUUID := hex.EncodeToString(rfc6962.DefaultHasher.HashLeaf(leaf))
-			return handleRekorAPIError(params, http.StatusConflict, fmt.Errorf("grpc error: %v", insertionStatus.String()), fmt.Sprintf(entryAlreadyExists, existingUUID), "entryURL", getEntryURL(*httpReq.URL, existingUUID))
-		default:
-			return handleRekorAPIError(params, http.StatusInternalServerError, fmt.Errorf("grpc error: %v", insertionStatus.String()), trillianUnexpectedResult)
-		}
+	if errResp := insertionError(params, resp.logID, leaf, resp.getAddResult.QueuedLeaf.Status); errResp != nil {
+		return errResp
 	}
 
 	// We made it this far, that means the entry was successfully added.
 	metricNewEntries.Inc()
 
 	queuedLeaf := resp.getAddResult.QueuedLeaf.Leaf
-	uuid := hex.EncodeToString(queuedLeaf.GetMerkleLeafHash())
+	uuid := entryIDFromLeafHash(resp.logID, queuedLeaf.GetMerkleLeafHash())
 
 	logEntry := models.LogEntry{
 		uuid: models.LogEntryAnon{
@@ -146,36 +155,311 @@ func CreateLogEntryHandler(params entries.CreateLogEntryParams) middleware.Respo
 		},
 	}
 
-	if viper.GetBool("enable_retrieve_api") {
-		go func() {
-			for _, key := range entry.IndexKeys() {
-				if err := addToIndex(context.Background(), key, uuid); err != nil {
-					log.RequestIDLogger(params.HTTPRequest).Error(err)
-				}
+	addIndexKeys(httpReq, entry, uuid)
+
+	return entries.NewCreateLogEntryCreated().WithPayload(logEntry).WithLocation(getEntryURL(requestURL(httpReq), uuid)).WithETag(uuid)
+}
+
+// queueLogEntry queues the canonicalized entry to be included in the log, responding without waiting for it
+// to be included
+func queueLogEntry(params entries.CreateLogEntryParams, entry types.EntryImpl, leaf []byte) middleware.Responder {
+	httpReq := params.HTTPRequest
+	tc := NewShardedClient(httpReq.Context())
+
+	resp := tc.queueLeaf(leaf)
+	if resp.status != codes.OK {
+		return handleRekorAPIError(params, http.StatusInternalServerError, fmt.Errorf("grpc error: %w", resp.err), trillianUnexpectedResult)
+	}
+	if errResp := insertionError(params, resp.logID, leaf, resp.getAddResult.QueuedLeaf.Status); errResp != nil {
+		return errResp
+	}
+
+	metricNewEntries.Inc()
+
+	queuedLeaf := resp.getAddResult.QueuedLeaf.Leaf
+	uuid := entryIDFromLeafHash(resp.logID, queuedLeaf.GetMerkleLeafHash())
+	queuedTime := time.Now()
+	if queuedLeaf.QueueTimestamp != nil {
+		queuedTime = queuedLeaf.QueueTimestamp.AsTime()
+	}
+	addPendingEntry(httpReq, logFromContext(httpReq.Context()).namespaced(hex.EncodeToString(queuedLeaf.GetMerkleLeafHash())), queuedTime)
+
+	statusURL := getEntryStatusURL(requestURL(httpReq), uuid)
+	queuedEntry := &models.QueuedLogEntry{
+		UUID:       swag.String(uuid),
+		StatusURL:  &statusURL,
+		QueuedTime: swag.Int64(queuedTime.Unix()),
+	}
+	if viper.GetBool("async_create.sign_promise") {
+		promise, err := signInclusionPromise(httpReq.Context(), uuid, queuedTime)
+		if err != nil {
+			return handleRekorAPIError(params, http.StatusInternalServerError, fmt.Errorf("signing error: %w", err), signingError)
+		}
+		queuedEntry.Promise = promise
+	}
+
+	addIndexKeys(httpReq, entry, uuid)
+
+	return entries.NewCreateLogEntryAccepted().WithPayload(queuedEntry).WithLocation(statusURL).WithETag(uuid)
+}
+
+// insertionResult is the outcome of proposing a leaf to the log
+type insertionResult int
+
+const (
+	leafInserted insertionResult = iota
+	// an equivalent leaf is already in the log
+	leafExists
+	leafFailed
+)
+
+// classifyInsertion returns whether the proposed leaf was inserted into the log, with the UUID of the leaf if it
+// was already in the log; an existing entry is reported in the tree of the shard holding it
+func classifyInsertion(treeID int64, leaf []byte, insertionStatus *rpcstatus.Status) (insertionResult, string) {
+	if insertionStatus == nil {
+		return leafInserted, ""
+	}
+	switch insertionStatus.Code {
+	case int32(code.Code_OK):
+		return leafInserted, ""
+	case int32(code.Code_ALREADY_EXISTS), int32(code.Code_FAILED_PRECONDITION):
+		return leafExists, entryIDFromLeafHash(treeID, rfc6962.DefaultHasher.HashLeaf(leaf))
+	default:
+		return leafFailed, ""
+	}
+}
+
+// insertionError returns the response to send when the proposed leaf was not inserted into the log, or nil if
+// it was inserted
+func insertionError(params entries.CreateLogEntryParams, treeID int64, leaf []byte, insertionStatus *rpcstatus.Status) middleware.Responder {
+	switch result, existingUUID := classifyInsertion(treeID, leaf, insertionStatus); result {
+	case leafExists:
+		return handleRekorAPIError(params, http.StatusConflict, fmt.Errorf("grpc error: %v", insertionStatus.String()), fmt.Sprintf(entryAlreadyExists, existingUUID), "entryURL", getEntryURL(requestURL(params.HTTPRequest), existingUUID))
+	case leafFailed:
+		return handleRekorAPIError(params, http.StatusInternalServerError, fmt.Errorf("grpc error: %w", status.ErrorProto(insertionStatus)), trillianUnexpectedResult)
+	default:
+		return nil
+	}
+}
+
+// addIndexKeys adds the UUID of the new entry to the search index under each of the entry's index keys
+func addIndexKeys(r *http.Request, entry types.EntryImpl, uuid string) {
+	if !viper.GetBool("enable_retrieve_api") {
+		return
+	}
+	a := logFromContext(r.Context())
+	go func() {
+		for _, key := range entry.IndexKeys() {
+			if err := addToIndex(context.Background(), a.namespaced(key), uuid); err != nil {
+				log.RequestIDLogger(r).Error(err)
+			}
+		}
+	}()
+}
+
+// CreateLogEntriesHandler creates a new entry in the log for each of the proposed entries, waiting once for all of
+// them to be included
+func CreateLogEntriesHandler(params entries.CreateLogEntriesParams) middleware.Responder {
+	httpReq := params.HTTPRequest
+	ctx := httpReq.Context()
+	if maxEntries := viper.GetInt("batch.max_entries"); len(params.ProposedEntries) > maxEntries {
+		err := fmt.Errorf("batch of %d entries exceeds maximum of %d", len(params.ProposedEntries), maxEntries)
+		return handleRekorAPIError(params, http.StatusBadRequest, err, fmt.Sprintf(tooManyEntries, maxEntries))
+	}
+	concurrency := viper.GetInt("batch.concurrency")
+
+	results := make([]*models.BatchEntryResult, len(params.ProposedEntries))
+	batchError := func(i, code int, message string) {
+		results[i] = &models.BatchEntryResult{
+			Status: swag.String(models.BatchEntryResultStatusError),
+			Error:  errorMsg(message, code),
+		}
+	}
+	// entries that the log server was unavailable or too busy to add may be retried by the client
+	batchTrillianError := func(i int, err error) {
+		if code, message, ok := trillianErrorCode(err); ok {
+			batchError(i, code, message)
+			return
+		}
+		batchError(i, http.StatusInternalServerError, trillianUnexpectedResult)
+	}
+
+	impls := make([]types.EntryImpl, len(params.ProposedEntries))
+	leaves := make([][]byte, len(params.ProposedEntries))
+	forEachConcurrently(len(params.ProposedEntries), concurrency, func(i int) {
+		if err := checkKindAllowed(ctx, params.ProposedEntries[i].Kind()); err != nil {
+			batchError(i, http.StatusBadRequest, err.Error())
+			return
+		}
+		entry, err := types.NewEntry(params.ProposedEntries[i])
+		if err != nil {
+			batchError(i, http.StatusBadRequest, err.Error())
+			return
+		}
+		leaf, err := entry.Canonicalize(ctx)
+		if err != nil {
+			code, message := canonicalizationError(err)
+			log.RequestIDLogger(httpReq).Errorw("unable to canonicalize entry", "position", i, "error", err)
+			batchError(i, code, message)
+			return
+		}
+		impls[i], leaves[i] = entry, leaf
+	})
+
+	// only the canonicalized entries are added to the log
+	var positions []int
+	var queued [][]byte
+	for i, leaf := range leaves {
+		if leaf != nil {
+			positions = append(positions, i)
+			queued = append(queued, leaf)
+		}
+	}
+
+	tc := NewShardedClient(ctx)
+	for j, resp := range tc.addLeaves(queued, concurrency) {
+		i := positions[j]
+		if resp.status != codes.OK {
+			log.RequestIDLogger(httpReq).Errorw("unable to add entry", "position", i, "error", resp.err)
+			batchTrillianError(i, resp.err)
+			continue
+		}
+
+		insertionStatus := resp.getAddResult.QueuedLeaf.Status
+		switch result, existingUUID := classifyInsertion(resp.logID, queued[j], insertionStatus); result {
+		case leafExists:
+			results[i] = &models.BatchEntryResult{
+				Status:   swag.String(models.BatchEntryResultStatusConflict),
+				UUID:     existingUUID,
+				Location: getBatchEntryURL(requestURL(httpReq), existingUUID),
 			}
-		}()
+			continue
+		case leafFailed:
+			log.RequestIDLogger(httpReq).Errorw("unable to add entry", "position", i, "error", insertionStatus.String())
+			batchTrillianError(i, status.ErrorProto(insertionStatus))
+			continue
+		}
+
+		metricNewEntries.Inc()
+
+		queuedLeaf := resp.getAddResult.QueuedLeaf.Leaf
+		uuid := entryIDFromLeafHash(resp.logID, queuedLeaf.GetMerkleLeafHash())
+		results[i] = &models.BatchEntryResult{
+			Status:   swag.String(models.BatchEntryResultStatusCreated),
+			UUID:     uuid,
+			Location: getBatchEntryURL(requestURL(httpReq), uuid),
+			Entry: models.LogEntry{
+				uuid: models.LogEntryAnon{
+					LogIndex:       swag.Int64(queuedLeaf.LeafIndex),
+					Body:           queuedLeaf.GetLeafValue(),
+					IntegratedTime: queuedLeaf.IntegrateTimestamp.AsTime().Unix(),
+				},
+			},
+		}
+
+		addIndexKeys(httpReq, impls[i], uuid)
+	}
+
+	return entries.NewCreateLogEntriesOK().WithPayload(results)
+}
+
+// canonicalizationError returns the status code and client message for an error returned while canonicalizing
+// a proposed entry
+func canonicalizationError(err error) (int, string) {
+	var pe *pki.PolicyError
+	if errors.As(err, &pe) {
+		return http.StatusBadRequest, pe.Error()
+	}
+	if errors.Is(err, util.ErrFetchNotPermitted) || errors.Is(err, util.ErrEntityTooLarge) || errors.Is(err, util.ErrAttachmentNotFound) {
+		return http.StatusBadRequest, err.Error()
+	}
+	return http.StatusInternalServerError, failedToGenerateCanonicalEntry
+}
+
+// ValidateLogEntryHandler performs every check made when an entry is created, without adding the entry to the log
+func ValidateLogEntryHandler(params entries.ValidateLogEntryParams) middleware.Responder {
+	ctx := params.HTTPRequest.Context()
+	result := &models.ValidationResult{Valid: swag.Bool(false)}
+	reject := func(stage string, err error) middleware.Responder {
+		ve := &models.ValidationError{Stage: swag.String(stage), Message: swag.String(err.Error())}
+		var pe *pki.PolicyError
+		if errors.As(err, &pe) {
+			ve.Stage = swag.String(models.ValidationErrorStagePolicy)
+			ve.Format = pe.Format
+		}
+		result.Errors = append(result.Errors, ve)
+		return entries.NewValidateLogEntryOK().WithPayload(result)
+	}
+
+	if err := checkKindAllowed(ctx, params.ProposedEntry.Kind()); err != nil {
+		return reject(models.ValidationErrorStageValidate, err)
+	}
+	entry, err := types.NewEntry(params.ProposedEntry)
+	if err != nil {
+		return reject(models.ValidationErrorStageDecode, err)
+	}
+	if err := entry.Validate(); err != nil {
+		return reject(models.ValidationErrorStageValidate, err)
+	}
+	// this also verifies the signature over content supplied in the entry itself
+	if err := entry.FetchExternalEntities(ctx); err != nil {
+		if errors.Is(err, util.ErrFetchNotPermitted) || errors.Is(err, util.ErrEntityTooLarge) || errors.Is(err, util.ErrAttachmentNotFound) {
+			return reject(models.ValidationErrorStageFetch, err)
+		}
+		return reject(models.ValidationErrorStageVerify, err)
+	}
+
+	leaf, err := entry.Canonicalize(ctx)
+	if err != nil {
+		var pe *pki.PolicyError
+		if errors.As(err, &pe) {
+			return reject(models.ValidationErrorStagePolicy, err)
+		}
+		return handleRekorAPIError(params, http.StatusInternalServerError, err, failedToGenerateCanonicalEntry)
 	}
 
-	return entries.NewCreateLogEntryCreated().WithPayload(logEntry).WithLocation(getEntryURL(*httpReq.URL, uuid)).WithETag(uuid)
+	result.Valid = swag.Bool(true)
+	// the entry would be added to the active shard
+	result.UUID = entryIDFromLeafHash(logFromContext(ctx).logID, rfc6962.DefaultHasher.HashLeaf(leaf))
+	result.Body = leaf
+	result.IndexKeys = entry.IndexKeys()
+	return entries.NewValidateLogEntryOK().WithPayload(result)
 }
 
 // getEntryURL returns the absolute path to the log entry in a RESTful style
 func getEntryURL(locationURL url.URL, uuid string) strfmt.URI {
-	// remove API key from output
+	// remove API key and async request from output
 	query := locationURL.Query()
 	query.Del("apiKey")
+	query.Del("async")
 	locationURL.RawQuery = query.Encode()
 	locationURL.Path = fmt.Sprintf("%v/%v", locationURL.Path, uuid)
 	return strfmt.URI(locationURL.String())
 
 }
 
-// GetLogEntryByUUIDHandler gets log entry and inclusion proof for specified UUID aka merkle leaf hash
+// getEntryStatusURL returns the absolute path to the status of the log entry
+func getEntryStatusURL(locationURL url.URL, uuid string) strfmt.URI {
+	locationURL.RawQuery = ""
+	locationURL.Path = fmt.Sprintf("%v/%v/status", locationURL.Path, uuid)
+	return strfmt.URI(locationURL.String())
+}
+
+// getBatchEntryURL returns the absolute path to a log entry created by a request to the batch endpoint
+func getBatchEntryURL(locationURL url.URL, uuid string) strfmt.URI {
+	locationURL.Path = path.Dir(locationURL.Path)
+	return getEntryURL(locationURL, uuid)
+}
+
+// GetLogEntryByUUIDHandler gets log entry and inclusion proof for specified UUID aka merkle leaf hash; if the
+// UUID is prefixed with a tree ID, only the shard in that tree is searched
 func GetLogEntryByUUIDHandler(params entries.GetLogEntryByUUIDParams) middleware.Responder {
-	hashValue, _ := hex.DecodeString(params.EntryUUID)
-	tc := NewTrillianClient(params.HTTPRequest.Context())
+	tc := NewShardedClient(params.HTTPRequest.Context())
 
-	resp := tc.getLeafAndProofByHash(hashValue)
+	resp, err := tc.getLeafAndProofByEntryID(params.EntryUUID)
+	if err != nil {
+		return handleRekorAPIError(params, http.StatusBadRequest, err, malformedUUID)
+	}
 	switch resp.status {
 	case codes.OK:
 	case codes.NotFound:
@@ -190,7 +474,7 @@ func GetLogEntryByUUIDHandler(params entries.GetLogEntryByUUIDParams) middleware
 		return handleRekorAPIError(params, http.StatusNotFound, errors.New("grpc returned 0 leaves with success code"), "")
 	}
 
-	logEntry, err := logEntryFromLeaf(tc, leaf, result.SignedLogRoot, result.Proof)
+	logEntry, err := logEntryFromLeaf(resp.logID, leaf, result.SignedLogRoot, result.Proof)
 	if err != nil {
 		return handleRekorAPIError(params, http.StatusInternalServerError, err, "")
 	}
@@ -198,22 +482,92 @@ func GetLogEntryByUUIDHandler(params entries.GetLogEntryByUUIDParams) middleware
 	return entries.NewGetLogEntryByUUIDOK().WithPayload(logEntry)
 }
 
+// GetLogEntryStatusHandler returns whether the entry with the specified UUID is queued or has been included in the log;
+// an entry that is not in the log is only known to be queued if it is tracked as pending by this server or in Redis
+func GetLogEntryStatusHandler(params entries.GetLogEntryStatusParams) middleware.Responder {
+	uuid := strings.ToLower(params.EntryUUID)
+	tc := NewShardedClient(params.HTTPRequest.Context())
+
+	// pending entries are tracked by leaf hash, whichever form of the UUID they are looked up by
+	leafHash, err := sharding.UUIDFromEntryID(uuid)
+	if err != nil {
+		return handleRekorAPIError(params, http.StatusBadRequest, err, malformedUUID)
+	}
+	pendingKey := logFromContext(params.HTTPRequest.Context()).namespaced(leafHash)
+	resp, err := tc.getLeafAndProofByEntryID(uuid)
+	if err != nil {
+		return handleRekorAPIError(params, http.StatusBadRequest, err, malformedUUID)
+	}
+
+	queued := func() middleware.Responder {
+		queuedTime, ok := pendingEntryQueuedTime(params.HTTPRequest, pendingKey)
+		if !ok {
+			return handleRekorAPIError(params, http.StatusNotFound, errors.New("entry is not in the log, and is not known to be queued"), "")
+		}
+		return entries.NewGetLogEntryStatusOK().WithPayload(&models.LogEntryStatus{
+			UUID:       swag.String(uuid),
+			Status:     swag.String(models.LogEntryStatusStatusQueued),
+			QueuedTime: queuedTime.Unix(),
+		})
+	}
+
+	switch resp.status {
+	case codes.OK:
+	case codes.NotFound:
+		return queued()
+	default:
+		return handleRekorAPIError(params, http.StatusInternalServerError, fmt.Errorf("grpc error: %w", resp.err), trillianUnexpectedResult)
+	}
+
+	result := resp.getLeafAndProofResult
+	leaf := result.Leaf
+	if leaf == nil {
+		return queued()
+	}
+	removePendingEntry(params.HTTPRequest, pendingKey)
+	uuid = entryIDFromLeafHash(resp.logID, leaf.MerkleLeafHash)
+
+	logEntry, err := logEntryFromLeaf(resp.logID, leaf, result.SignedLogRoot, result.Proof)
+	if err != nil {
+		return handleRekorAPIError(params, http.StatusInternalServerError, err, "")
+	}
+	entryStatus := &models.LogEntryStatus{
+		UUID:   swag.String(uuid),
+		Status: swag.String(models.LogEntryStatusStatusIntegrated),
+	}
+	if leaf.QueueTimestamp != nil {
+		entryStatus.QueuedTime = leaf.QueueTimestamp.AsTime().Unix()
+	}
+	for _, e := range logEntry {
+		entryStatus.LogIndex = e.LogIndex
+		entryStatus.IntegratedTime = e.IntegratedTime
+		entryStatus.InclusionProof = e.InclusionProof
+	}
+	return entries.NewGetLogEntryStatusOK().WithPayload(entryStatus)
+}
+
 // SearchLogQueryHandler searches log by index, UUID, or proposed entry and returns array of entries found with inclusion proofs
 func SearchLogQueryHandler(params entries.SearchLogQueryParams) middleware.Responder {
 	httpReqCtx := params.HTTPRequest.Context()
 	resultPayload := []models.LogEntry{}
-	tc := NewTrillianClient(httpReqCtx)
+	tc := NewShardedClient(httpReqCtx)
 
 	if len(params.Entry.EntryUUIDs) > 0 || len(params.Entry.Entries()) > 0 {
 		g, _ := errgroup.WithContext(httpReqCtx)
 
+		// entries given by UUID are only searched for in the shard that the UUID is prefixed with, if any
 		searchHashes := make([][]byte, len(params.Entry.EntryUUIDs)+len(params.Entry.Entries()))
+		searchTreeIDs := make([]int64, len(searchHashes))
 		for i, uuid := range params.Entry.EntryUUIDs {
-			hash, err := hex.DecodeString(uuid)
+			treeID, leafHash, err := sharding.ParseEntryID(uuid)
 			if err != nil {
 				return handleRekorAPIError(params, http.StatusBadRequest, err, malformedUUID)
 			}
-			searchHashes[i] = hash
+			hash, err := hex.DecodeString(leafHash)
+			if err != nil {
+				return handleRekorAPIError(params, http.StatusBadRequest, err, malformedUUID)
+			}
+			searchHashes[i], searchTreeIDs[i] = hash, treeID
 		}
 
 		code := http.StatusBadRequest
@@ -250,12 +604,12 @@ func SearchLogQueryHandler(params entries.SearchLogQueryParams) middleware.Respo
 			return handleRekorAPIError(params, code, err, err.Error())
 		}
 
-		searchByHashResults := make([]*trillian.GetEntryAndProofResponse, len(searchHashes))
+		searchByHashResults := make([]*Response, len(searchHashes))
 		g, _ = errgroup.WithContext(httpReqCtx)
 		for i, hash := range searchHashes {
 			i, hash := i, hash // https://golang.org/doc/faq#closures_and_goroutines
 			g.Go(func() error {
-				resp := tc.getLeafAndProofByHash(hash)
+				resp := tc.getLeafAndProofByHash(searchTreeIDs[i], hash)
 				switch resp.status {
 				case codes.OK, codes.NotFound:
 				default:
@@ -263,7 +617,7 @@ func SearchLogQueryHandler(params entries.SearchLogQueryParams) middleware.Respo
 				}
 				leafResult := resp.getLeafAndProofResult
 				if leafResult != nil && leafResult.Leaf != nil {
-					searchByHashResults[i] = leafResult
+					searchByHashResults[i] = resp
 				}
 				return nil
 			})
@@ -273,8 +627,12 @@ func SearchLogQueryHandler(params entries.SearchLogQueryParams) middleware.Respo
 			return handleRekorAPIError(params, code, err, err.Error())
 		}
 
-		for _, leafResp := range searchByHashResults {
-			logEntry, err := logEntryFromLeaf(tc, leafResp.Leaf, leafResp.SignedLogRoot, leafResp.Proof)
+		for _, resp := range searchByHashResults {
+			if resp == nil {
+				continue
+			}
+			leafResp := resp.getLeafAndProofResult
+			logEntry, err := logEntryFromLeaf(resp.logID, leafResp.Leaf, leafResp.SignedLogRoot, leafResp.Proof)
 			if err != nil {
 				return handleRekorAPIError(params, code, err, err.Error())
 			}
@@ -286,7 +644,7 @@ func SearchLogQueryHandler(params entries.SearchLogQueryParams) middleware.Respo
 	if len(params.Entry.LogIndexes) > 0 {
 		g, _ := errgroup.WithContext(httpReqCtx)
 
-		leafResults := make([]*trillian.GetEntryAndProofResponse, len(params.Entry.LogIndexes))
+		leafResults := make([]*Response, len(params.Entry.LogIndexes))
 		for i, logIndex := range params.Entry.LogIndexes {
 			i, logIndex := i, logIndex // https://golang.org/doc/faq#closures_and_goroutines
 			g.Go(func() error {
@@ -298,7 +656,7 @@ func SearchLogQueryHandler(params entries.SearchLogQueryParams) middleware.Respo
 				}
 				leafResult := resp.getLeafAndProofResult
 				if leafResult != nil && leafResult.Leaf != nil {
-					leafResults[i] = leafResult
+					leafResults[i] = resp
 				}
 				return nil
 			})
@@ -308,9 +666,10 @@ func SearchLogQueryHandler(params entries.SearchLogQueryParams) middleware.Respo
 			return handleRekorAPIError(params, http.StatusInternalServerError, fmt.Errorf("grpc error: %w", err), trillianUnexpectedResult)
 		}
 
-		for _, result := range leafResults {
-			if result != nil {
-				logEntry, err := logEntryFromLeaf(tc, result.Leaf, result.SignedLogRoot, result.Proof)
+		for _, resp := range leafResults {
+			if resp != nil {
+				result := resp.getLeafAndProofResult
+				logEntry, err := logEntryFromLeaf(resp.logID, result.Leaf, result.SignedLogRoot, result.Proof)
 				if err != nil {
 					return handleRekorAPIError(params, http.StatusInternalServerError, err, trillianUnexpectedResult)
 				}
@@ -0,0 +1,185 @@
+//
+// Copyright 2021 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+	"github.com/spf13/viper"
+
+	"github.com/sigstore/rekor/pkg/generated/models"
+	"github.com/sigstore/rekor/pkg/generated/restapi/operations/entries"
+	_ "github.com/sigstore/rekor/pkg/pki/formats"
+	_ "github.com/sigstore/rekor/pkg/types/rekord/v0.0.1"
+)
+
+// setTestConfig sets the config value for the duration of the test
+func setTestConfig(t *testing.T, key string, value interface{}) {
+	t.Helper()
+	old := viper.Get(key)
+	viper.Set(key, value)
+	t.Cleanup(func() { viper.Set(key, old) })
+}
+
+// newTestLog creates a log kept by the embedded backend, and serves it as the default log if the name is empty,
+// or as the named log otherwise
+func newTestLog(t *testing.T, name string, allowedTypes ...string) *API {
+	t.Helper()
+	setTestConfig(t, "log_backend", embeddedLogBackend)
+	a, err := newLog(context.Background(), logConfig{
+		Name:            name,
+		Signer:          "memory",
+		AllowedTypes:    allowedTypes,
+		EmbeddedLogPath: filepath.Join(t.TempDir(), "log.db"),
+	}, nil, nil)
+	if err != nil {
+		t.Fatalf("creating log: %v", err)
+	}
+	t.Cleanup(func() { _ = a.embeddedLog.Close() })
+
+	if name == "" {
+		old := api
+		api = a
+		t.Cleanup(func() { api = old })
+	} else {
+		logs.Store(name, a)
+		t.Cleanup(func() { logs.Delete(name) })
+	}
+	return a
+}
+
+// testSigner signs artifacts for rekord entries
+type testSigner struct {
+	priv      ed25519.PrivateKey
+	publicKey []byte
+}
+
+func newTestSigner(t *testing.T) *testSigner {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testSigner{priv: priv, publicKey: pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})}
+}
+
+// rekordEntry returns a rekord entry for the artifact, with the signature given
+func (s *testSigner) rekordEntry(artifact string, signature []byte) models.ProposedEntry {
+	return &models.Rekord{
+		APIVersion: swag.String("0.0.1"),
+		Spec: &models.RekordV001Schema{
+			Data: &models.RekordV001SchemaData{Content: strfmt.Base64(artifact)},
+			Signature: &models.RekordV001SchemaSignature{
+				Format:    "x509",
+				Content:   strfmt.Base64(signature),
+				PublicKey: &models.RekordV001SchemaSignaturePublicKey{Content: strfmt.Base64(s.publicKey)},
+			},
+		},
+	}
+}
+
+// signedEntry returns a rekord entry for the artifact with a valid signature
+func (s *testSigner) signedEntry(artifact string) models.ProposedEntry {
+	return s.rekordEntry(artifact, ed25519.Sign(s.priv, []byte(artifact)))
+}
+
+func TestCreateLogEntries(t *testing.T) {
+	newTestLog(t, "")
+	setTestConfig(t, "batch.max_entries", 10)
+	setTestConfig(t, "batch.concurrency", 2)
+	setTestConfig(t, "enable_retrieve_api", false)
+	s := newTestSigner(t)
+
+	createBatch := func(proposed ...models.ProposedEntry) []*models.BatchEntryResult {
+		t.Helper()
+		resp := CreateLogEntriesHandler(entries.CreateLogEntriesParams{
+			HTTPRequest:     httptest.NewRequest(http.MethodPost, "/api/v1/log/entries/batch", nil),
+			ProposedEntries: proposed,
+		})
+		ok, isOK := resp.(*entries.CreateLogEntriesOK)
+		if !isOK {
+			t.Fatalf("unexpected response %#v", resp)
+		}
+		if len(ok.Payload) != len(proposed) {
+			t.Fatalf("expected %d results, got %d", len(proposed), len(ok.Payload))
+		}
+		return ok.Payload
+	}
+
+	first := createBatch(s.signedEntry("existing artifact"))
+	if swag.StringValue(first[0].Status) != models.BatchEntryResultStatusCreated {
+		t.Fatalf("expected the first entry to be created, got %+v", first[0])
+	}
+	existingUUID := first[0].UUID
+
+	results := createBatch(
+		s.signedEntry("new artifact"),
+		s.signedEntry("existing artifact"),
+		s.rekordEntry("unsigned artifact", []byte("not a signature")),
+		s.signedEntry("another new artifact"),
+		s.signedEntry("new artifact"),
+	)
+	wantStatus := []string{
+		models.BatchEntryResultStatusCreated,
+		models.BatchEntryResultStatusConflict,
+		models.BatchEntryResultStatusError,
+		models.BatchEntryResultStatusCreated,
+		models.BatchEntryResultStatusConflict,
+	}
+	for i, r := range results {
+		if got := swag.StringValue(r.Status); got != wantStatus[i] {
+			t.Errorf("entry %d: expected status %v, got %v (%+v)", i, wantStatus[i], got, r.Error)
+		}
+	}
+
+	if results[0].UUID == "" || results[0].Entry[results[0].UUID].LogIndex == nil {
+		t.Errorf("expected the created entry to be returned, got %+v", results[0])
+	}
+	if results[1].UUID != existingUUID {
+		t.Errorf("expected the conflict to name the existing entry %v, got %v", existingUUID, results[1].UUID)
+	}
+	if want := "/api/v1/log/entries/" + existingUUID; string(results[1].Location) != want {
+		t.Errorf("expected the location of the existing entry %v, got %v", want, results[1].Location)
+	}
+	if results[4].UUID != results[0].UUID {
+		t.Errorf("expected the repeated entry to conflict with the entry created in the same batch, got %v", results[4].UUID)
+	}
+	if results[2].Error == nil || results[2].Error.Code != http.StatusInternalServerError || results[2].UUID != "" {
+		t.Errorf("expected the entry with a bad signature not to be added, got %+v", results[2])
+	}
+	for _, i := range []int{1, 2, 4} {
+		if results[i].Entry != nil {
+			t.Errorf("entry %d: expected no entry to be returned, got %+v", i, results[i].Entry)
+		}
+	}
+	if results[3].UUID == "" || results[3].UUID == results[0].UUID {
+		t.Errorf("expected the created entries to have different UUIDs, got %v and %v", results[0].UUID, results[3].UUID)
+	}
+}
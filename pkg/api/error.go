@@ -16,17 +16,26 @@
 package api
 
 import (
+	"errors"
 	"fmt"
+	"math"
 	"net/http"
 	"regexp"
+	"strconv"
+	"time"
 
+	"github.com/go-openapi/runtime"
 	"github.com/go-openapi/runtime/middleware"
 	"github.com/go-openapi/strfmt"
 	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/viper"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/sigstore/rekor/pkg/generated/models"
 	"github.com/sigstore/rekor/pkg/generated/restapi/operations/entries"
 	"github.com/sigstore/rekor/pkg/generated/restapi/operations/index"
+	"github.com/sigstore/rekor/pkg/generated/restapi/operations/pubkey"
 	"github.com/sigstore/rekor/pkg/generated/restapi/operations/tlog"
 	"github.com/sigstore/rekor/pkg/log"
 )
@@ -37,12 +46,24 @@ const (
 	failedToGenerateCanonicalEntry = "Error generating canonicalized entry"
 	entryAlreadyExists             = "An equivalent entry already exists in the transparency log with UUID %v"
 	firstSizeLessThanLastSize      = "firstSize(%d) must be less than lastSize(%d)"
-	malformedUUID                  = "UUID must be a 64-character hexadecimal string"
+	malformedUUID                  = "UUID must be a 64-character hexadecimal string, optionally prefixed with a 16-character hexadecimal tree ID"
 	malformedHash                  = "Hash must be a 64-character hexadecimal string created from SHA256 algorithm"
 	malformedPublicKey             = "Public key provided could not be parsed"
+	unsupportedPKIFormat           = "Unsupported PKI format '%v'; supported formats are %v"
 	failedToGenerateCanonicalKey   = "Error generating canonicalized public key"
 	redisUnexpectedResult          = "Unexpected result from searching index"
 	lastSizeGreaterThanKnown       = "The tree size requested(%d) was greater than what is currently observable(%d)"
+	tooManyEntries                 = "A batch may contain at most %d entries"
+	signingError                   = "Error signing promise of inclusion"
+	trillianUnavailable            = "The transparency log is temporarily unavailable; retry later"
+	trillianResourceExhausted      = "The transparency log is overloaded; retry later"
+	unknownTreeID                  = "No shard of the transparency log is in tree %d"
+	kindNotAllowed                 = "Entries of kind '%v' are not accepted by this log; accepted kinds are %v"
+	unknownWitness                 = "No witness with key %x cosigns tree heads of this log"
+	cosignatureNotOfActiveShard    = "Cosignatures are only accepted for tree heads of the active shard, tree %d"
+	invalidCosignature             = "Cosignature could not be verified with the key of the witness"
+	cosignedSizeGreaterThanKnown   = "The cosigned tree size (%d) is greater than what is currently observable (%d)"
+	inconsistentCosignature        = "The cosigned log root is not consistent with the log"
 )
 
 func errorMsg(message string, code int) *models.Error {
@@ -52,7 +73,44 @@ func errorMsg(message string, code int) *models.Error {
 	}
 }
 
+// trillianErrorCode returns the status code and client message for an error from the log server that a client may
+// retry after waiting, rather than a generic server error
+func trillianErrorCode(err error) (int, string, bool) {
+	var grpcErr interface{ GRPCStatus() *status.Status }
+	if !errors.As(err, &grpcErr) {
+		return 0, "", false
+	}
+	switch grpcErr.GRPCStatus().Code() {
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable, trillianUnavailable, true
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests, trillianResourceExhausted, true
+	default:
+		return 0, "", false
+	}
+}
+
+// retryAfterResponder sets the Retry-After header on the response written by the wrapped responder
+type retryAfterResponder struct {
+	middleware.Responder
+	retryAfter time.Duration
+}
+
+func (r retryAfterResponder) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+	rw.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(r.retryAfter.Seconds()))))
+	r.Responder.WriteResponse(rw, producer)
+}
+
 func handleRekorAPIError(params interface{}, code int, err error, message string, fields ...interface{}) middleware.Responder {
+	if code == http.StatusInternalServerError {
+		if retryCode, retryMessage, ok := trillianErrorCode(err); ok {
+			return retryAfterResponder{
+				Responder:  handleRekorAPIError(params, retryCode, err, retryMessage, fields...),
+				retryAfter: viper.GetDuration("trillian_log_server.retry_after"),
+			}
+		}
+	}
+
 	if message == "" {
 		message = http.StatusText(code)
 	}
@@ -86,6 +144,14 @@ func handleRekorAPIError(params interface{}, code int, err error, message string
 		default:
 			return entries.NewGetLogEntryByUUIDDefault(code).WithPayload(errorMsg(message, code))
 		}
+	case entries.CreateLogEntriesParams:
+		logMsg(params.HTTPRequest)
+		switch code {
+		case http.StatusBadRequest:
+			return entries.NewCreateLogEntriesBadRequest().WithPayload(errorMsg(message, code))
+		default:
+			return entries.NewCreateLogEntriesDefault(code).WithPayload(errorMsg(message, code))
+		}
 	case entries.CreateLogEntryParams:
 		logMsg(params.HTTPRequest)
 		switch code {
@@ -108,6 +174,14 @@ func handleRekorAPIError(params interface{}, code int, err error, message string
 		default:
 			return entries.NewCreateLogEntryDefault(code).WithPayload(errorMsg(message, code))
 		}
+	case entries.GetLogEntryStatusParams:
+		logMsg(params.HTTPRequest)
+		switch code {
+		case http.StatusNotFound:
+			return entries.NewGetLogEntryStatusNotFound()
+		default:
+			return entries.NewGetLogEntryStatusDefault(code).WithPayload(errorMsg(message, code))
+		}
 	case entries.SearchLogQueryParams:
 		logMsg(params.HTTPRequest)
 		switch code {
@@ -116,9 +190,25 @@ func handleRekorAPIError(params interface{}, code int, err error, message string
 		default:
 			return entries.NewSearchLogQueryDefault(code).WithPayload(errorMsg(message, code))
 		}
+	case entries.ValidateLogEntryParams:
+		logMsg(params.HTTPRequest)
+		switch code {
+		case http.StatusBadRequest:
+			return entries.NewValidateLogEntryBadRequest().WithPayload(errorMsg(message, code))
+		default:
+			return entries.NewValidateLogEntryDefault(code).WithPayload(errorMsg(message, code))
+		}
 	case tlog.GetLogInfoParams:
 		logMsg(params.HTTPRequest)
 		return tlog.NewGetLogInfoDefault(code).WithPayload(errorMsg(message, code))
+	case tlog.AddCosignatureParams:
+		logMsg(params.HTTPRequest)
+		switch code {
+		case http.StatusBadRequest:
+			return tlog.NewAddCosignatureBadRequest().WithPayload(errorMsg(message, code))
+		default:
+			return tlog.NewAddCosignatureDefault(code).WithPayload(errorMsg(message, code))
+		}
 	case tlog.GetLogProofParams:
 		logMsg(params.HTTPRequest)
 		switch code {
@@ -130,6 +220,9 @@ func handleRekorAPIError(params interface{}, code int, err error, message string
 	case tlog.GetPublicKeyParams:
 		logMsg(params.HTTPRequest)
 		return tlog.NewGetPublicKeyDefault(code).WithPayload(errorMsg(message, code))
+	case pubkey.GetPublicKeyParams:
+		logMsg(params.HTTPRequest)
+		return pubkey.NewGetPublicKeyDefault(code).WithPayload(errorMsg(message, code))
 	case index.SearchIndexParams:
 		logMsg(params.HTTPRequest)
 		switch code {
@@ -0,0 +1,84 @@
+//
+// Copyright 2021 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/runtime"
+	"github.com/spf13/viper"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sigstore/rekor/pkg/generated/restapi/operations/tlog"
+)
+
+func TestTrillianErrorCode(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantCode    int
+		wantMessage string
+		wantOK      bool
+	}{
+		{"unavailable", status.Error(codes.Unavailable, "unavailable"), http.StatusServiceUnavailable, trillianUnavailable, true},
+		{"resource exhausted", status.Error(codes.ResourceExhausted, "overloaded"), http.StatusTooManyRequests, trillianResourceExhausted, true},
+		{"wrapped", fmt.Errorf("queueing leaf: %w", status.Error(codes.Unavailable, "unavailable")), http.StatusServiceUnavailable, trillianUnavailable, true},
+		{"other code", status.Error(codes.Internal, "internal"), 0, "", false},
+		{"not a grpc error", errors.New("broken"), 0, "", false},
+		{"nil", nil, 0, "", false},
+	}
+	for _, tt := range tests {
+		code, message, ok := trillianErrorCode(tt.err)
+		if code != tt.wantCode || message != tt.wantMessage || ok != tt.wantOK {
+			t.Errorf("%v: expected (%d, %q, %v), got (%d, %q, %v)", tt.name, tt.wantCode, tt.wantMessage, tt.wantOK, code, message, ok)
+		}
+	}
+}
+
+func TestHandleRekorAPIErrorRetryAfter(t *testing.T) {
+	defer viper.Set("trillian_log_server.retry_after", viper.Get("trillian_log_server.retry_after"))
+	viper.Set("trillian_log_server.retry_after", 1500*time.Millisecond)
+
+	tests := []struct {
+		name           string
+		code           int
+		err            error
+		wantStatus     int
+		wantRetryAfter string
+	}{
+		{"unavailable", http.StatusInternalServerError, status.Error(codes.Unavailable, "unavailable"), http.StatusServiceUnavailable, "2"},
+		{"resource exhausted", http.StatusInternalServerError, status.Error(codes.ResourceExhausted, "overloaded"), http.StatusTooManyRequests, "2"},
+		{"other grpc error", http.StatusInternalServerError, status.Error(codes.Internal, "internal"), http.StatusInternalServerError, ""},
+		{"not a server error", http.StatusBadRequest, status.Error(codes.Unavailable, "unavailable"), http.StatusBadRequest, ""},
+	}
+	for _, tt := range tests {
+		params := tlog.GetLogInfoParams{HTTPRequest: httptest.NewRequest(http.MethodGet, "/api/v1/log", nil)}
+		rw := httptest.NewRecorder()
+		handleRekorAPIError(params, tt.code, tt.err, "").WriteResponse(rw, runtime.JSONProducer())
+		if rw.Code != tt.wantStatus {
+			t.Errorf("%v: expected status %d, got %d", tt.name, tt.wantStatus, rw.Code)
+		}
+		if got := rw.Header().Get("Retry-After"); got != tt.wantRetryAfter {
+			t.Errorf("%v: expected Retry-After %q, got %q", tt.name, tt.wantRetryAfter, got)
+		}
+	}
+}
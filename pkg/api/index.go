@@ -20,6 +20,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"errors"
+	"fmt"
 	"net/http"
 	"strings"
 
@@ -36,6 +37,7 @@ import (
 
 func SearchIndexHandler(params index.SearchIndexParams) middleware.Responder {
 	httpReqCtx := params.HTTPRequest.Context()
+	a := logFromContext(httpReqCtx)
 
 	var result []string
 	if params.Query.Hash != "" {
@@ -44,20 +46,25 @@ func SearchIndexHandler(params index.SearchIndexParams) middleware.Responder {
 			return handleRekorAPIError(params, http.StatusBadRequest, errors.New("invalid hash value specified"), malformedHash)
 		}
 		var resultUUIDs []string
-		if err := redisClient.Do(httpReqCtx, radix.Cmd(&resultUUIDs, "LRANGE", strings.ToLower(params.Query.Hash), "0", "-1")); err != nil {
+		if err := redisClient.Do(httpReqCtx, radix.Cmd(&resultUUIDs, "LRANGE", a.namespaced(strings.ToLower(params.Query.Hash)), "0", "-1")); err != nil {
 			return handleRekorAPIError(params, http.StatusInternalServerError, err, redisUnexpectedResult)
 		}
 		result = append(result, resultUUIDs...)
 	}
 	if params.Query.PublicKey != nil {
-		af := pki.NewArtifactFactory(swag.StringValue(params.Query.PublicKey.Format))
+		format := swag.StringValue(params.Query.PublicKey.Format)
+		if _, found := pki.GetFormat(format); !found {
+			return handleRekorAPIError(params, http.StatusBadRequest, fmt.Errorf("unknown pki format '%v'", format), fmt.Sprintf(unsupportedPKIFormat, format, pki.SupportedFormats()))
+		}
+		af := pki.NewArtifactFactory(format)
 		keyReader, err := util.FileOrURLReadCloser(httpReqCtx, params.Query.PublicKey.URL.String(), params.Query.PublicKey.Content)
 		if err != nil {
 			return handleRekorAPIError(params, http.StatusBadRequest, err, malformedPublicKey)
 		}
 		defer keyReader.Close()
 
-		key, err := af.NewPublicKey(keyReader)
+		// the admission policy is not applied, so that keys admitted under an earlier policy can still be found
+		key, err := af.ParsePublicKey(keyReader)
 		if err != nil {
 			return handleRekorAPIError(params, http.StatusBadRequest, err, malformedPublicKey)
 		}
@@ -72,7 +79,7 @@ func SearchIndexHandler(params index.SearchIndexParams) middleware.Responder {
 		}
 		keyHash := hasher.Sum(nil)
 		var resultUUIDs []string
-		if err := redisClient.Do(httpReqCtx, radix.Cmd(&resultUUIDs, "LRANGE", strings.ToLower(hex.EncodeToString(keyHash)), "0", "-1")); err != nil {
+		if err := redisClient.Do(httpReqCtx, radix.Cmd(&resultUUIDs, "LRANGE", a.namespaced(strings.ToLower(hex.EncodeToString(keyHash))), "0", "-1")); err != nil {
 			return handleRekorAPIError(params, http.StatusInternalServerError, err, redisUnexpectedResult)
 		}
 		result = append(result, resultUUIDs...)
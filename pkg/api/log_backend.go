@@ -0,0 +1,36 @@
+//
+// Copyright 2021 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+const (
+	trillianLogBackend = "trillian"
+	embeddedLogBackend = "embedded"
+)
+
+// LogBackend is the Merkle tree log that entries are added to and proven against; results are returned in the
+// form used by the Trillian log server, whichever backend is in use
+type LogBackend interface {
+	// addLeaf adds the leaf to the log and waits for it to be included
+	addLeaf(byteValue []byte) *Response
+	// addLeaves adds each of the leaves to the log and waits for them to be included
+	addLeaves(byteValues [][]byte, concurrency int) []*Response
+	// queueLeaf adds the leaf to the log without waiting for it to be included
+	queueLeaf(byteValue []byte) *Response
+	getLeafAndProofByIndex(index int64) *Response
+	getLeafAndProofByHash(hash []byte) *Response
+	getLatest(firstSize int64) *Response
+	getConsistencyProof(firstSize, lastSize int64) *Response
+}
@@ -0,0 +1,221 @@
+//
+// Copyright 2021 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/go-openapi/errors"
+	"github.com/spf13/viper"
+
+	"github.com/sigstore/rekor/pkg/log"
+)
+
+const (
+	// apiPrefix is the prefix of the paths of the default log
+	apiPrefix = "/api/v1"
+	// namedLogsPrefix is the prefix of the paths of a named log, which are followed by the name of the log and then
+	// the path of the same operation on the default log without apiPrefix
+	namedLogsPrefix = apiPrefix + "/logs/"
+)
+
+var logNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_-]*$`)
+
+// logs holds each named log, keyed by name; the default log is not included
+var logs sync.Map
+
+// logConfig is the config of a log; the default log is configured by the top level settings, and named logs by
+// an entry in logs in the config file
+type logConfig struct {
+	Name            string        `mapstructure:"name"`
+	TreeID          int64         `mapstructure:"tree_id"`
+	Signer          string        `mapstructure:"signer"`
+	AllowedTypes    []string      `mapstructure:"allowed_types"`
+	EmbeddedLogPath string        `mapstructure:"embedded_log_path"`
+	Shards          []shardConfig `mapstructure:"shards"`
+	HistoricalKeys  []keyConfig   `mapstructure:"historical_keys"`
+	WitnessKeys     []string      `mapstructure:"witness_keys"`
+}
+
+func defaultLogConfig() (logConfig, error) {
+	cfg := logConfig{
+		TreeID:          viper.GetInt64("trillian_log_server.tlog_id"),
+		Signer:          viper.GetString("rekor_server.signer"),
+		AllowedTypes:    viper.GetStringSlice("rekor_server.allowed_types"),
+		EmbeddedLogPath: viper.GetString("embedded_log.path"),
+		WitnessKeys:     viper.GetStringSlice("rekor_server.witness_keys"),
+	}
+	if err := viper.UnmarshalKey("trillian_log_server.shards", &cfg.Shards); err != nil {
+		return cfg, fmt.Errorf("parsing trillian_log_server.shards: %w", err)
+	}
+	if err := validateShardConfig(cfg.Shards); err != nil {
+		return cfg, fmt.Errorf("trillian_log_server.shards: %w", err)
+	}
+	if err := viper.UnmarshalKey("rekor_server.historical_keys", &cfg.HistoricalKeys); err != nil {
+		return cfg, fmt.Errorf("parsing rekor_server.historical_keys: %w", err)
+	}
+	return cfg, nil
+}
+
+// configureLogs creates each of the named logs in the config file; they share the connection to the Trillian log
+// server with the default log, but each must have trees of its own
+func configureLogs() error {
+	var configs []logConfig
+	if err := viper.UnmarshalKey("logs", &configs); err != nil {
+		return fmt.Errorf("parsing logs: %w", err)
+	}
+	embedded := viper.GetString("log_backend") == embeddedLogBackend
+	embeddedPaths := map[string]string{viper.GetString("embedded_log.path"): "the default log"}
+	treeIDs := map[int64]string{}
+	if !embedded {
+		for _, s := range api.shards {
+			treeIDs[s.treeID] = "the default log"
+		}
+	}
+	for _, cfg := range configs {
+		if !logNameRegexp.MatchString(cfg.Name) {
+			return fmt.Errorf("invalid log name '%v'; names must be letters, digits, '-' and '_'", cfg.Name)
+		}
+		if _, ok := logs.Load(cfg.Name); ok {
+			return fmt.Errorf("log '%v' is configured more than once", cfg.Name)
+		}
+		if err := validateShardConfig(cfg.Shards); err != nil {
+			return fmt.Errorf("shards of log '%v': %w", cfg.Name, err)
+		}
+		if cfg.Signer == "" {
+			cfg.Signer = viper.GetString("rekor_server.signer")
+		}
+		if embedded {
+			if cfg.EmbeddedLogPath == "" {
+				return fmt.Errorf("embedded_log_path must be set for log '%v'", cfg.Name)
+			}
+			if other, ok := embeddedPaths[cfg.EmbeddedLogPath]; ok {
+				return fmt.Errorf("log '%v' has the same embedded_log_path as %v", cfg.Name, other)
+			}
+			embeddedPaths[cfg.EmbeddedLogPath] = fmt.Sprintf("log '%v'", cfg.Name)
+		} else if cfg.TreeID == 0 && (len(cfg.Shards) == 0 || cfg.Shards[len(cfg.Shards)-1].TreeID == 0) {
+			// a tree would otherwise be chosen from those on the Trillian log server, which may be the tree of
+			// another log
+			return fmt.Errorf("tree_id must be set for log '%v'", cfg.Name)
+		}
+
+		a, err := newLog(context.Background(), cfg, api.adminClient, api.logClient)
+		if err != nil {
+			return fmt.Errorf("creating log '%v': %w", cfg.Name, err)
+		}
+		if !embedded {
+			for _, s := range a.shards {
+				if other, ok := treeIDs[s.treeID]; ok {
+					return fmt.Errorf("log '%v' uses tree %d, which is also used by %v", cfg.Name, s.treeID, other)
+				}
+				treeIDs[s.treeID] = fmt.Sprintf("log '%v'", cfg.Name)
+			}
+		}
+		logs.Store(cfg.Name, a)
+		log.Logger.Infof("Serving log '%v' from tree %d under %v%v", cfg.Name, a.logID, namedLogsPrefix, cfg.Name)
+	}
+	return nil
+}
+
+type logNameKey struct{}
+
+// logFromContext returns the log that the request with the context is for
+func logFromContext(ctx context.Context) *API {
+	if name, ok := ctx.Value(logNameKey{}).(string); ok {
+		if a, ok := logs.Load(name); ok {
+			return a.(*API)
+		}
+	}
+	return api
+}
+
+// NamedLogs returns middleware that serves requests to the paths of a named log with the handlers of the default
+// log; the request is rewritten to the path of the default log, and its context records the log it is for
+func NamedLogs(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, namedLogsPrefix) {
+			handler.ServeHTTP(w, r)
+			return
+		}
+		name := strings.TrimPrefix(r.URL.Path, namedLogsPrefix)
+		rest := ""
+		if i := strings.Index(name, "/"); i >= 0 {
+			name, rest = name[:i], name[i:]
+		}
+		if _, ok := logs.Load(name); !ok {
+			errors.ServeError(w, r, errors.NotFound("log '%v' is not served by this instance", name))
+			return
+		}
+
+		escaped := r.URL.EscapedPath()
+		r = r.Clone(context.WithValue(r.Context(), logNameKey{}, name))
+		r.URL.Path = apiPrefix + rest
+		// keep any escaping by the client, such as of a slash, in the rest of the path
+		r.URL.RawPath = apiPrefix + escapedRest(escaped)
+		r.RequestURI = r.URL.RequestURI()
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// escapedRest returns the escaped path that follows the name of the log in the escaped path of a named log
+func escapedRest(escaped string) string {
+	rest := strings.TrimPrefix(escaped, namedLogsPrefix)
+	if i := strings.Index(rest, "/"); i >= 0 {
+		return rest[i:]
+	}
+	return ""
+}
+
+// requestURL returns the URL of the request as it was made by the client, before any rewriting by NamedLogs
+func requestURL(r *http.Request) url.URL {
+	u := *r.URL
+	if a := logFromContext(r.Context()); a.name != "" {
+		u.Path = namedLogsPrefix + a.name + strings.TrimPrefix(u.Path, apiPrefix)
+		u.RawPath = namedLogsPrefix + a.name + strings.TrimPrefix(r.URL.EscapedPath(), apiPrefix)
+	}
+	return u
+}
+
+// namespaced returns the key, qualified by the name of the log if it is not the default log, so that each log
+// has its own entries in the search index and other shared state; keys are hex digests, so those of the default log
+// cannot collide with the qualified keys of a named log
+func (a *API) namespaced(key string) string {
+	if a.name == "" {
+		return key
+	}
+	return a.name + "/" + key
+}
+
+// checkKindAllowed returns an error if the log that the request with the context is for does not accept entries
+// of the kind given
+func checkKindAllowed(ctx context.Context, kind string) error {
+	a := logFromContext(ctx)
+	if len(a.allowedTypes) == 0 {
+		return nil
+	}
+	for _, allowed := range a.allowedTypes {
+		if allowed == kind {
+			return nil
+		}
+	}
+	return fmt.Errorf(kindNotAllowed, kind, a.allowedTypes)
+}
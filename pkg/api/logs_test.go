@@ -0,0 +1,138 @@
+//
+// Copyright 2021 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNamedLogs(t *testing.T) {
+	def := newTestLog(t, "")
+	releases := newTestLog(t, "releases", "rekord")
+	newTestLog(t, "other")
+
+	// the handler records the request as it is seen by the handlers of the default log
+	type seen struct {
+		path, requestURI, requestURL string
+		log                          *API
+	}
+	var got *seen
+	handler := NamedLogs(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		u := requestURL(r)
+		got = &seen{path: r.URL.Path, requestURI: r.RequestURI, requestURL: u.String(), log: logFromContext(r.Context())}
+	}))
+
+	tests := []struct {
+		name       string
+		target     string
+		wantStatus int
+		want       *seen
+	}{
+		{"default log", "/api/v1/log?stable=true", http.StatusOK, &seen{"/api/v1/log", "/api/v1/log?stable=true", "/api/v1/log?stable=true", def}},
+		{"default log entries", "/api/v1/log/entries/abc", http.StatusOK, &seen{"/api/v1/log/entries/abc", "/api/v1/log/entries/abc", "/api/v1/log/entries/abc", def}},
+		{"named log", "/api/v1/logs/releases/log", http.StatusOK, &seen{"/api/v1/log", "/api/v1/log", "/api/v1/logs/releases/log", releases}},
+		{"named log entries", "/api/v1/logs/releases/log/entries/abc?async=true", http.StatusOK, &seen{"/api/v1/log/entries/abc", "/api/v1/log/entries/abc?async=true", "/api/v1/logs/releases/log/entries/abc?async=true", releases}},
+		{"named log root", "/api/v1/logs/releases", http.StatusOK, &seen{"/api/v1", "/api/v1", "/api/v1/logs/releases", releases}},
+		{"escaped path", "/api/v1/logs/releases/log/entries/a%2Fb", http.StatusOK, &seen{"/api/v1/log/entries/a/b", "/api/v1/log/entries/a%2Fb", "/api/v1/logs/releases/log/entries/a%2Fb", releases}},
+		{"unknown log", "/api/v1/logs/unknown/log", http.StatusNotFound, nil},
+		{"name prefix of a log", "/api/v1/logs/release/log", http.StatusNotFound, nil},
+		{"empty name", "/api/v1/logs//log", http.StatusNotFound, nil},
+		{"path traversal", "/api/v1/logs/../log", http.StatusNotFound, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = nil
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, tt.target, nil))
+			if rw.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, rw.Code)
+			}
+			if tt.want == nil {
+				if got != nil {
+					t.Errorf("expected the request not to be handled, got %+v", got)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatal("expected the request to be handled")
+			}
+			if got.log != tt.want.log {
+				t.Errorf("expected log '%v', got '%v'", tt.want.log.name, got.log.name)
+			}
+			if got.path != tt.want.path || got.requestURI != tt.want.requestURI || got.requestURL != tt.want.requestURL {
+				t.Errorf("expected path %v, request URI %v and request URL %v, got %v, %v and %v",
+					tt.want.path, tt.want.requestURI, tt.want.requestURL, got.path, got.requestURI, got.requestURL)
+			}
+		})
+	}
+}
+
+func TestNamespaced(t *testing.T) {
+	def := &API{}
+	releases := &API{name: "releases"}
+	other := &API{name: "other"}
+
+	keys := map[string]string{}
+	for _, a := range []*API{def, releases, other} {
+		// index keys, pending entries and cosignatures are all keyed by hex digests
+		for _, key := range []string{hex.EncodeToString(sha256.New().Sum(nil)), "0123456789abcdef", "ab"} {
+			namespaced := a.namespaced(key)
+			if owner, ok := keys[namespaced]; ok {
+				t.Errorf("key %v of log '%v' collides with a key of log '%v'", namespaced, a.name, owner)
+			}
+			keys[namespaced] = a.name
+		}
+	}
+	if got := def.namespaced("abc"); got != "abc" {
+		t.Errorf("expected keys of the default log to be unchanged, got %v", got)
+	}
+	if got := releases.namespaced("abc"); got != "releases/abc" {
+		t.Errorf("expected keys of a named log to be prefixed with its name, got %v", got)
+	}
+}
+
+func TestCheckKindAllowed(t *testing.T) {
+	newTestLog(t, "")
+	newTestLog(t, "releases", "rekord", "jar")
+
+	tests := []struct {
+		log     string
+		kind    string
+		wantErr bool
+	}{
+		{"", "rekord", false},
+		{"", "rpm", false},
+		{"releases", "rekord", false},
+		{"releases", "jar", false},
+		{"releases", "rpm", true},
+		{"releases", "Rekord", true},
+		{"unknown", "rpm", false},
+	}
+	for _, tt := range tests {
+		ctx := context.Background()
+		if tt.log != "" {
+			ctx = context.WithValue(ctx, logNameKey{}, tt.log)
+		}
+		if err := checkKindAllowed(ctx, tt.kind); (err != nil) != tt.wantErr {
+			t.Errorf("log '%v', kind %v: expected error %v, got %v", tt.log, tt.kind, tt.wantErr, err)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+//
+// Copyright 2021 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"os"
+
+	"github.com/go-openapi/errors"
+	"github.com/spf13/viper"
+
+	"github.com/sigstore/rekor/pkg/log"
+	"github.com/sigstore/rekor/pkg/util"
+)
+
+// SpoolMultipart returns middleware that accepts a multipart/form-data request in place of a JSON body.
+// The part named bodyPart holds the JSON body; every file part is written to a temporary file, and
+// may be referred to from the body as util.AttachmentURL(<name of part>). Requests of any other
+// content type are passed through unmodified.
+func SpoolMultipart(bodyPart string) func(http.Handler) http.Handler {
+	return func(handler http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+			if err != nil || mediaType != "multipart/form-data" {
+				handler.ServeHTTP(w, r)
+				return
+			}
+
+			body, attachments, err := spoolParts(r, bodyPart)
+			defer func() {
+				for _, path := range attachments {
+					if err := os.Remove(path); err != nil {
+						log.RequestIDLogger(r).Warnf("unable to remove spooled attachment: %v", err)
+					}
+				}
+			}()
+			if err != nil {
+				log.RequestIDLogger(r).Error(err)
+				errors.ServeError(w, r, err)
+				return
+			}
+
+			r = r.WithContext(util.WithAttachments(r.Context(), attachments))
+			r.Header.Set("Content-Type", "application/json")
+			r.Body = ioutil.NopCloser(bytes.NewReader(body))
+			r.ContentLength = int64(len(body))
+			handler.ServeHTTP(w, r)
+		})
+	}
+}
+
+// spoolParts reads the body part into memory and writes each file part to a temporary file; the
+// attachments returned must be removed by the caller, even if an error is returned
+func spoolParts(r *http.Request, bodyPart string) ([]byte, util.Attachments, error) {
+	attachments := util.Attachments{}
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, attachments, errors.New(http.StatusBadRequest, "invalid multipart request: %v", err)
+	}
+
+	maxSize := viper.GetInt64("multipart.max_attachment_size")
+	var body []byte
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, attachments, errors.New(http.StatusBadRequest, "invalid multipart request: %v", err)
+		}
+
+		name := part.FormName()
+		switch {
+		case part.FileName() == "" && name == bodyPart:
+			if body != nil {
+				return nil, attachments, errors.New(http.StatusBadRequest, "duplicate part '%v'", name)
+			}
+			if body, err = readLimited(part, maxSize); err != nil {
+				return nil, attachments, err
+			}
+		case part.FileName() != "":
+			if _, exists := attachments[name]; exists {
+				return nil, attachments, errors.New(http.StatusBadRequest, "duplicate part '%v'", name)
+			}
+			if len(attachments) >= viper.GetInt("multipart.max_attachments") {
+				return nil, attachments, errors.New(http.StatusBadRequest, "too many attachments in request")
+			}
+			path, err := spoolPart(part, maxSize)
+			if path != "" {
+				attachments[name] = path
+			}
+			if err != nil {
+				return nil, attachments, err
+			}
+		default:
+			return nil, attachments, errors.New(http.StatusBadRequest, "unexpected part '%v'", name)
+		}
+	}
+	if body == nil {
+		return nil, attachments, errors.New(http.StatusBadRequest, "missing part '%v'", bodyPart)
+	}
+	return body, attachments, nil
+}
+
+func readLimited(r io.Reader, maxSize int64) ([]byte, error) {
+	b, err := ioutil.ReadAll(io.LimitReader(r, maxSize+1))
+	if err != nil {
+		return nil, errors.New(http.StatusBadRequest, "error reading multipart request: %v", err)
+	}
+	if int64(len(b)) > maxSize {
+		return nil, errors.New(http.StatusRequestEntityTooLarge, "part exceeds maximum size of %d bytes", maxSize)
+	}
+	return b, nil
+}
+
+// spoolPart writes the part to a temporary file, returning its path if the file was created
+func spoolPart(r io.Reader, maxSize int64) (string, error) {
+	f, err := ioutil.TempFile(viper.GetString("multipart.spool_dir"), "rekor-attachment-")
+	if err != nil {
+		return "", fmt.Errorf("unable to spool attachment: %w", err)
+	}
+	defer f.Close()
+
+	n, err := io.Copy(f, io.LimitReader(r, maxSize+1))
+	if err != nil {
+		return f.Name(), errors.New(http.StatusBadRequest, "error reading multipart request: %v", err)
+	}
+	if n > maxSize {
+		return f.Name(), errors.New(http.StatusRequestEntityTooLarge, "attachment exceeds maximum size of %d bytes", maxSize)
+	}
+	return f.Name(), nil
+}
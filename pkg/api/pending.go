@@ -0,0 +1,146 @@
+//
+// Copyright 2021 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/mediocregopher/radix/v4"
+	"github.com/spf13/viper"
+
+	"github.com/sigstore/rekor/pkg/generated/models"
+	"github.com/sigstore/rekor/pkg/log"
+)
+
+// pendingEntries holds the time at which each entry created asynchronously was queued, keyed by leaf hash, until
+// the entry is found to have been included in the log or async_create.pending_ttl elapses. Trillian does not
+// report whether a leaf is queued, so this is how the status of an entry that has not yet been included is
+// known. When the search index is enabled, pending entries are also recorded in Redis, so that entries queued
+// by another instance of the server, or before a restart, are found; otherwise the status of such an entry is
+// not known until it has been included, and so is only ever reported on a best-effort basis.
+var pendingEntries sync.Map
+
+// pendingRedisKey returns the key under which the pending entry is recorded in Redis, which must not collide
+// with the keys of the search index
+func pendingRedisKey(key string) string {
+	return "pending/" + key
+}
+
+// addPendingEntry records that the entry with the leaf hash was queued at the time given
+func addPendingEntry(r *http.Request, key string, queuedTime time.Time) {
+	pendingEntries.Store(key, queuedTime)
+	if redisClient == nil {
+		return
+	}
+	ttl := viper.GetDuration("async_create.pending_ttl") - time.Since(queuedTime)
+	if ttl < time.Second {
+		return
+	}
+	cmd := radix.FlatCmd(nil, "SET", pendingRedisKey(key), queuedTime.Unix(), "EX", int64(ttl.Seconds()))
+	if err := redisClient.Do(r.Context(), cmd); err != nil {
+		log.RequestIDLogger(r).Warnf("unable to record pending entry %v: %v", key, err)
+	}
+}
+
+// pendingEntryQueuedTime returns the time at which the entry with the leaf hash was queued, if it is still pending
+func pendingEntryQueuedTime(r *http.Request, key string) (time.Time, bool) {
+	if v, ok := pendingEntries.Load(key); ok {
+		queuedTime := v.(time.Time)
+		if time.Since(queuedTime) <= viper.GetDuration("async_create.pending_ttl") {
+			return queuedTime, true
+		}
+		pendingEntries.Delete(key)
+	}
+	if redisClient == nil {
+		return time.Time{}, false
+	}
+	var queuedTime int64
+	mb := radix.Maybe{Rcv: &queuedTime}
+	if err := redisClient.Do(r.Context(), radix.Cmd(&mb, "GET", pendingRedisKey(key))); err != nil {
+		log.RequestIDLogger(r).Warnf("unable to look up pending entry %v: %v", key, err)
+		return time.Time{}, false
+	}
+	if mb.Null {
+		return time.Time{}, false
+	}
+	return time.Unix(queuedTime, 0), true
+}
+
+// removePendingEntry stops tracking the entry with the leaf hash, once it has been included in the log
+func removePendingEntry(r *http.Request, key string) {
+	pendingEntries.Delete(key)
+	if redisClient == nil {
+		return
+	}
+	if err := redisClient.Do(r.Context(), radix.Cmd(nil, "DEL", pendingRedisKey(key))); err != nil {
+		log.RequestIDLogger(r).Warnf("unable to remove pending entry %v: %v", key, err)
+	}
+}
+
+// sweepPendingEntries periodically stops tracking entries that were queued longer ago than the TTL
+func sweepPendingEntries(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ttl := viper.GetDuration("async_create.pending_ttl")
+			pendingEntries.Range(func(k, v interface{}) bool {
+				if time.Since(v.(time.Time)) > ttl {
+					pendingEntries.Delete(k)
+				}
+				return true
+			})
+		}
+	}
+}
+
+// inclusionPromise is the payload of the promise the log signs when an entry is queued
+type inclusionPromise struct {
+	LogID      int64  `json:"logID"`
+	QueuedTime int64  `json:"queuedTime"`
+	UUID       string `json:"uuid"`
+}
+
+// signInclusionPromise returns a promise, signed by the log, to include the queued entry with the UUID
+func signInclusionPromise(ctx context.Context, uuid string, queuedTime time.Time) (*models.InclusionPromise, error) {
+	a := logFromContext(ctx)
+	payload, err := json.Marshal(inclusionPromise{
+		LogID:      a.logID,
+		QueuedTime: queuedTime.Unix(),
+		UUID:       uuid,
+	})
+	if err != nil {
+		return nil, err
+	}
+	sig, _, err := a.signer.Sign(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+	payloadB64 := strfmt.Base64(payload)
+	sigB64 := strfmt.Base64(sig)
+	return &models.InclusionPromise{
+		Payload:   &payloadB64,
+		Signature: &sigB64,
+	}, nil
+}
@@ -0,0 +1,322 @@
+//
+// Copyright 2021 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"bufio"
+	"context"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/swag"
+	"github.com/google/trillian/merkle/rfc6962/hasher"
+	"github.com/mediocregopher/radix/v4"
+
+	"github.com/sigstore/rekor/pkg/generated/models"
+	"github.com/sigstore/rekor/pkg/generated/restapi/operations/entries"
+	"github.com/sigstore/rekor/pkg/sharding"
+	"github.com/sigstore/rekor/pkg/types"
+)
+
+// fakeRedis serves the few Redis commands used for pending entries, keeping string values in memory
+type fakeRedis struct {
+	mu     sync.Mutex
+	values map[string]string
+}
+
+// startFakeRedis starts a fake Redis server and points redisClient at it for the duration of the test
+func startFakeRedis(t *testing.T) *fakeRedis {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &fakeRedis{values: map[string]string{}}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go f.serve(conn)
+		}
+	}()
+
+	client, err := (radix.PoolConfig{Size: 1}).New(context.Background(), "tcp", l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := redisClient
+	redisClient = client
+	t.Cleanup(func() {
+		redisClient = old
+		_ = client.Close()
+		_ = l.Close()
+	})
+	return f
+}
+
+func (f *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		args, err := readRESPArray(r)
+		if err != nil {
+			return
+		}
+		if _, err := io.WriteString(conn, f.do(args)); err != nil {
+			return
+		}
+	}
+}
+
+// readRESPArray reads a command, which clients send as an array of bulk strings
+func readRESPArray(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "*")))
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, n)
+	for i := range args {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "$")))
+		if err != nil {
+			return nil, err
+		}
+		b := make([]byte, size+2)
+		if _, err := io.ReadFull(r, b); err != nil {
+			return nil, err
+		}
+		args[i] = string(b[:size])
+	}
+	return args, nil
+}
+
+func (f *fakeRedis) do(args []string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch strings.ToUpper(args[0]) {
+	case "PING":
+		return "+PONG\r\n"
+	case "SET":
+		f.values[args[1]] = args[2]
+		return "+OK\r\n"
+	case "GET":
+		v, ok := f.values[args[1]]
+		if !ok {
+			return "$-1\r\n"
+		}
+		return fmt.Sprintf("$%d\r\n%s\r\n", len(v), v)
+	case "DEL":
+		_, ok := f.values[args[1]]
+		delete(f.values, args[1])
+		if ok {
+			return ":1\r\n"
+		}
+		return ":0\r\n"
+	default:
+		return fmt.Sprintf("-ERR unknown command '%v'\r\n", args[0])
+	}
+}
+
+func (f *fakeRedis) has(key string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_, ok := f.values[key]
+	return ok
+}
+
+// clearLocalPendingEntries forgets the pending entries known to this instance, as another instance would not
+// know of them
+func clearLocalPendingEntries() {
+	pendingEntries.Range(func(k, _ interface{}) bool {
+		pendingEntries.Delete(k)
+		return true
+	})
+}
+
+func getEntryStatus(t *testing.T, uuid string) *models.LogEntryStatus {
+	t.Helper()
+	resp := GetLogEntryStatusHandler(entries.GetLogEntryStatusParams{
+		HTTPRequest: httptest.NewRequest(http.MethodGet, "/api/v1/log/entries/"+uuid+"/status", nil),
+		EntryUUID:   uuid,
+	})
+	ok, isOK := resp.(*entries.GetLogEntryStatusOK)
+	if !isOK {
+		t.Fatalf("unexpected response for status of %v: %#v", uuid, resp)
+	}
+	return ok.Payload
+}
+
+func TestQueueLogEntry(t *testing.T) {
+	a := newTestLog(t, "")
+	setTestConfig(t, "async_create.enabled", true)
+	setTestConfig(t, "async_create.sign_promise", true)
+	setTestConfig(t, "async_create.pending_ttl", time.Hour)
+	setTestConfig(t, "enable_retrieve_api", false)
+	s := newTestSigner(t)
+
+	resp := CreateLogEntryHandler(entries.CreateLogEntryParams{
+		HTTPRequest:   httptest.NewRequest(http.MethodPost, "/api/v1/log/entries?async=true", nil),
+		Async:         swag.Bool(true),
+		ProposedEntry: s.signedEntry("queued artifact"),
+	})
+	accepted, ok := resp.(*entries.CreateLogEntryAccepted)
+	if !ok {
+		t.Fatalf("expected the entry to be accepted, got %#v", resp)
+	}
+	queued := accepted.Payload
+	uuid := swag.StringValue(queued.UUID)
+	if want := "/api/v1/log/entries/" + uuid + "/status"; string(*queued.StatusURL) != want || string(accepted.Location) != want {
+		t.Errorf("expected status URL %v, got %v and location %v", want, string(*queued.StatusURL), accepted.Location)
+	}
+
+	// the promise is signed with the key of the log
+	if queued.Promise == nil {
+		t.Fatal("expected a signed promise")
+	}
+	pub, ok := a.keys[0].PublicKey.(*ecdsa.PublicKey)
+	if !ok {
+		t.Fatalf("unexpected type of log key %T", a.keys[0].PublicKey)
+	}
+	digest := sha256.Sum256(*queued.Promise.Payload)
+	if !ecdsa.VerifyASN1(pub, digest[:], *queued.Promise.Signature) {
+		t.Error("promise signature does not verify with the log key")
+	}
+	var promise inclusionPromise
+	if err := json.Unmarshal(*queued.Promise.Payload, &promise); err != nil {
+		t.Fatal(err)
+	}
+	if promise.UUID != uuid || promise.LogID != a.logID || promise.QueuedTime != swag.Int64Value(queued.QueuedTime) {
+		t.Errorf("unexpected promise %+v for entry %v of log %d queued at %d", promise, uuid, a.logID, swag.Int64Value(queued.QueuedTime))
+	}
+	tampered := append([]byte{}, *queued.Promise.Payload...)
+	tampered[len(tampered)-2] ^= 1
+	digest = sha256.Sum256(tampered)
+	if ecdsa.VerifyASN1(pub, digest[:], *queued.Promise.Signature) {
+		t.Error("expected promise signature not to verify over a different payload")
+	}
+
+	// entries are included by the embedded log as soon as they are queued
+	status := getEntryStatus(t, uuid)
+	if swag.StringValue(status.Status) != models.LogEntryStatusStatusIntegrated || status.LogIndex == nil || status.InclusionProof == nil {
+		t.Errorf("expected the entry to be reported as included, got %+v", status)
+	}
+	leafHash, err := sharding.UUIDFromEntryID(uuid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := pendingEntries.Load(leafHash); ok {
+		t.Error("expected the included entry to no longer be pending")
+	}
+}
+
+func TestEntryStatusPendingThenIncluded(t *testing.T) {
+	a := newTestLog(t, "")
+	setTestConfig(t, "async_create.pending_ttl", time.Hour)
+	s := newTestSigner(t)
+
+	entry, err := types.NewEntry(s.signedEntry("pending artifact"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := entry.Canonicalize(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	leafHash := hex.EncodeToString(hasher.DefaultHasher.HashLeaf(leaf))
+	queuedTime := time.Now().Add(-time.Minute)
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/log/entries", nil)
+	addPendingEntry(r, leafHash, queuedTime)
+	t.Cleanup(func() { pendingEntries.Delete(leafHash) })
+
+	status := getEntryStatus(t, leafHash)
+	if swag.StringValue(status.Status) != models.LogEntryStatusStatusQueued || status.QueuedTime != queuedTime.Unix() || status.LogIndex != nil {
+		t.Errorf("expected the entry to be reported as queued at %d, got %+v", queuedTime.Unix(), status)
+	}
+
+	if resp := NewEmbeddedClient(context.Background()).addLeaf(leaf); resp.err != nil {
+		t.Fatal(resp.err)
+	}
+	status = getEntryStatus(t, leafHash)
+	if swag.StringValue(status.Status) != models.LogEntryStatusStatusIntegrated || swag.Int64Value(status.LogIndex) != 0 {
+		t.Errorf("expected the entry to be reported as included, got %+v", status)
+	}
+	if want := entryIDFromLeafHash(a.logID, hasher.DefaultHasher.HashLeaf(leaf)); swag.StringValue(status.UUID) != want {
+		t.Errorf("expected UUID %v, got %v", want, swag.StringValue(status.UUID))
+	}
+	if _, ok := pendingEntries.Load(leafHash); ok {
+		t.Error("expected the included entry to no longer be pending")
+	}
+
+	// entries that are neither queued nor included are not found
+	resp := GetLogEntryStatusHandler(entries.GetLogEntryStatusParams{
+		HTTPRequest: httptest.NewRequest(http.MethodGet, "/api/v1/log/entries/status", nil),
+		EntryUUID:   strings.Repeat("ab", 32),
+	})
+	if _, ok := resp.(*entries.GetLogEntryStatusNotFound); !ok {
+		t.Errorf("expected an unknown entry not to be found, got %#v", resp)
+	}
+}
+
+func TestPendingEntryInRedis(t *testing.T) {
+	newTestLog(t, "")
+	setTestConfig(t, "async_create.pending_ttl", time.Hour)
+	redis := startFakeRedis(t)
+	leafHash := strings.Repeat("cd", 32)
+	queuedTime := time.Now().Add(-time.Minute)
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/log/entries", nil)
+
+	// the entry is queued by one instance, and its status is asked of another
+	addPendingEntry(r, leafHash, queuedTime)
+	t.Cleanup(func() { pendingEntries.Delete(leafHash) })
+	if !redis.has(pendingRedisKey(leafHash)) {
+		t.Fatal("expected the pending entry to be recorded in Redis")
+	}
+	clearLocalPendingEntries()
+
+	status := getEntryStatus(t, leafHash)
+	if swag.StringValue(status.Status) != models.LogEntryStatusStatusQueued || status.QueuedTime != queuedTime.Unix() {
+		t.Errorf("expected the entry queued by the other instance to be reported as queued at %d, got %+v", queuedTime.Unix(), status)
+	}
+
+	removePendingEntry(r, leafHash)
+	if redis.has(pendingRedisKey(leafHash)) {
+		t.Error("expected the pending entry to be removed from Redis")
+	}
+	if _, ok := pendingEntryQueuedTime(r, leafHash); ok {
+		t.Error("expected the removed entry not to be pending")
+	}
+}
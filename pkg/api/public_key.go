@@ -17,10 +17,104 @@ limitations under the License.
 package api
 
 import (
+	"crypto"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"time"
+
 	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/swag"
+
 	"github.com/sigstore/rekor/pkg/generated/restapi/operations/pubkey"
+	"github.com/sigstore/rekor/pkg/verify"
 )
 
+// keyConfig is an entry in rekor_server.historical_keys, or in the historical keys of a named log, in the config
+// file; it is a key that the log signed with before the current key
+type keyConfig struct {
+	PublicKey string `mapstructure:"public_key"`
+	// RFC 3339 times bounding the period in which the log signed with the key; either may be omitted
+	NotBefore string `mapstructure:"not_before"`
+	NotAfter  string `mapstructure:"not_after"`
+}
+
+// newLogKeys returns the current key of the log, followed by the historical keys in the config and the keys of
+// any shards that are not among them. The current key is not bounded, as it also signs the tree heads of frozen
+// shards, which may be dated before it was introduced.
+func newLogKeys(current crypto.PublicKey, configs []keyConfig, shards []*logShard) (verify.LogKeys, error) {
+	key, err := verify.NewLogKey(current)
+	if err != nil {
+		return nil, err
+	}
+	keys := verify.LogKeys{key}
+	for _, c := range configs {
+		b, err := ioutil.ReadFile(filepath.Clean(c.PublicKey))
+		if err != nil {
+			return nil, fmt.Errorf("reading historical key: %w", err)
+		}
+		parsed, err := verify.ParseLogKeys(b)
+		if err != nil {
+			return nil, fmt.Errorf("historical key %v: %w", c.PublicKey, err)
+		}
+		for _, k := range parsed {
+			if k.NotBefore, err = parseKeyTime(c.NotBefore); err != nil {
+				return nil, fmt.Errorf("not_before of historical key %v: %w", c.PublicKey, err)
+			}
+			if k.NotAfter, err = parseKeyTime(c.NotAfter); err != nil {
+				return nil, fmt.Errorf("not_after of historical key %v: %w", c.PublicKey, err)
+			}
+			if keys.ByHint(k.ID) != nil {
+				return nil, fmt.Errorf("historical key %v is configured more than once, or is the current key", c.PublicKey)
+			}
+			keys = append(keys, k)
+		}
+	}
+	for _, shard := range shards {
+		parsed, err := verify.ParseLogKeys([]byte(shard.pubkey))
+		if err != nil {
+			return nil, fmt.Errorf("public key of shard %d: %w", shard.treeID, err)
+		}
+		for _, k := range parsed {
+			if keys.ByHint(k.ID) == nil {
+				keys = append(keys, k)
+			}
+		}
+	}
+	return keys, nil
+}
+
+func parseKeyTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+// GetPublicKeyHandler returns the key that the log signed with while the shard requested was active, or the
+// current key of the log if no shard is requested; if all is set, every key the log signs or has signed with is
+// returned
 func GetPublicKeyHandler(params pubkey.GetPublicKeyParams) middleware.Responder {
-	return pubkey.NewGetPublicKeyOK().WithPayload(api.pubkey)
+	a := logFromContext(params.HTTPRequest.Context())
+	if swag.BoolValue(params.All) {
+		b, err := a.keys.MarshalPEM()
+		if err != nil {
+			return handleRekorAPIError(params, http.StatusInternalServerError, err, "")
+		}
+		return pubkey.NewGetPublicKeyOK().WithPayload(string(b))
+	}
+
+	treeID, err := parseTreeID(params.TreeID)
+	if err != nil {
+		return handleRekorAPIError(params, http.StatusBadRequest, err, err.Error())
+	}
+	if treeID == 0 {
+		return pubkey.NewGetPublicKeyOK().WithPayload(a.pubkey)
+	}
+	shard, ok := NewShardedClient(params.HTTPRequest.Context()).shardByTreeID(treeID)
+	if !ok {
+		return handleRekorAPIError(params, http.StatusBadRequest, nil, fmt.Sprintf(unknownTreeID, treeID))
+	}
+	return pubkey.NewGetPublicKeyOK().WithPayload(shard.pubkey)
 }
@@ -0,0 +1,366 @@
+//
+// Copyright 2021 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/google/trillian"
+	"github.com/google/trillian/client"
+	rfc6962 "github.com/google/trillian/merkle/rfc6962/hasher"
+	"github.com/google/trillian/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sigstore/rekor/pkg/log"
+	"github.com/sigstore/rekor/pkg/sharding"
+)
+
+// shardConfig is an entry in trillian_log_server.shards, or in the shards of a named log, in the config file
+type shardConfig struct {
+	TreeID     int64  `mapstructure:"tree_id"`
+	StartIndex int64  `mapstructure:"start_index"`
+	PublicKey  string `mapstructure:"public_key"`
+}
+
+// logShard is one of the trees that the log is split across; the last shard is active and new entries are added
+// to it, while the others are frozen
+type logShard struct {
+	treeID int64
+	// index in the log of the first entry in the tree
+	startIndex int64
+	// number of entries in a frozen shard; the active shard has no fixed length
+	length int64
+	// PEM encoded public key of the key that the log signed with while the shard was active
+	pubkey   string
+	verifier *client.LogVerifier
+}
+
+// validateShardConfig checks a list of shards from the config file, which must be ordered by start index with the
+// first starting at index 0
+func validateShardConfig(configs []shardConfig) error {
+	for i, c := range configs {
+		if i == 0 && c.StartIndex != 0 {
+			return errors.New("the first shard must start at index 0")
+		}
+		if i > 0 && c.StartIndex <= configs[i-1].StartIndex {
+			return fmt.Errorf("shards must be ordered by start_index; shard %d starts at %d", i, c.StartIndex)
+		}
+		if i < len(configs)-1 && c.TreeID == 0 {
+			return fmt.Errorf("tree_id must be set for frozen shard %d", i)
+		}
+	}
+	return nil
+}
+
+// newTrillianShards returns the shards of the log from their config; if no shards are configured, the log is a
+// single shard in the tree with the ID given. The tree ID of the active shard may be omitted from the config
+// file, in which case the ID given is used for it.
+func newTrillianShards(ctx context.Context, adminClient trillian.TrillianAdminClient, logClient trillian.TrillianLogClient, configs []shardConfig, activeTreeID int64) ([]*logShard, error) {
+	if len(configs) == 0 {
+		configs = []shardConfig{{TreeID: activeTreeID}}
+	}
+	if configs[len(configs)-1].TreeID == 0 {
+		configs[len(configs)-1].TreeID = activeTreeID
+	}
+
+	shards := make([]*logShard, len(configs))
+	for i, c := range configs {
+		t, err := adminClient.GetTree(ctx, &trillian.GetTreeRequest{TreeId: c.TreeID})
+		if err != nil {
+			return nil, fmt.Errorf("getting tree %d: %w", c.TreeID, err)
+		}
+		verifier, err := client.NewLogVerifierFromTree(t)
+		if err != nil {
+			return nil, err
+		}
+		shard := &logShard{
+			treeID:     c.TreeID,
+			startIndex: c.StartIndex,
+			verifier:   verifier,
+		}
+		if c.PublicKey != "" {
+			pubkey, err := ioutil.ReadFile(filepath.Clean(c.PublicKey))
+			if err != nil {
+				return nil, fmt.Errorf("reading public key of shard %d: %w", c.TreeID, err)
+			}
+			shard.pubkey = string(pubkey)
+		}
+
+		if i < len(configs)-1 {
+			shard.length = configs[i+1].StartIndex - c.StartIndex
+			resp, err := logClient.GetLatestSignedLogRoot(ctx, &trillian.GetLatestSignedLogRootRequest{LogId: c.TreeID})
+			if err != nil {
+				return nil, fmt.Errorf("getting root of tree %d: %w", c.TreeID, err)
+			}
+			var root types.LogRootV1
+			if err := root.UnmarshalBinary(resp.SignedLogRoot.LogRoot); err != nil {
+				return nil, err
+			}
+			if int64(root.TreeSize) > shard.length {
+				return nil, fmt.Errorf("frozen shard %d holds %d entries, but the next shard starts %d entries after it", c.TreeID, root.TreeSize, shard.length)
+			} else if int64(root.TreeSize) < shard.length {
+				log.Logger.Warnf("frozen shard %d holds %d entries; indexes up to %d are unused", c.TreeID, root.TreeSize, c.StartIndex+shard.length-1)
+			}
+		}
+		log.Logger.Infof("Using tree %d for shard starting at index %d", c.TreeID, c.StartIndex)
+		shards[i] = shard
+	}
+	return shards, nil
+}
+
+// entryIDFromLeafHash returns the ID of the entry with the leaf hash in the tree given
+func entryIDFromLeafHash(treeID int64, leafHash []byte) string {
+	return sharding.CreateEntryID(treeID, hex.EncodeToString(leafHash))
+}
+
+// ShardedClient routes requests to the shards of the log that the request is for. Entries are added to the active
+// shard, and indexes in the log are translated to and from indexes in the tree of the shard holding the entry;
+// inclusion proofs are relative to that tree. The tree that each result came from is set in its Response.
+type ShardedClient struct {
+	context context.Context
+	log     *API
+	shards  []*logShard
+}
+
+func NewShardedClient(ctx context.Context) *ShardedClient {
+	a := logFromContext(ctx)
+	return &ShardedClient{
+		context: ctx,
+		log:     a,
+		shards:  a.shards,
+	}
+}
+
+func (s *ShardedClient) active() *logShard {
+	return s.shards[len(s.shards)-1]
+}
+
+func (s *ShardedClient) frozen() []*logShard {
+	return s.shards[:len(s.shards)-1]
+}
+
+// backend returns a client for the tree of the shard in the configured log backend
+func (s *ShardedClient) backend(shard *logShard) LogBackend {
+	if s.log.embeddedLog != nil {
+		return NewEmbeddedClient(s.context)
+	}
+	return &TrillianClient{
+		client:   s.log.logClient,
+		logID:    shard.treeID,
+		context:  s.context,
+		verifier: shard.verifier,
+	}
+}
+
+// shardByTreeID returns the shard in the tree with the ID given, or the active shard if the ID is 0
+func (s *ShardedClient) shardByTreeID(treeID int64) (*logShard, bool) {
+	if treeID == 0 {
+		return s.active(), true
+	}
+	for _, shard := range s.shards {
+		if shard.treeID == treeID {
+			return shard, true
+		}
+	}
+	return nil, false
+}
+
+// shardByIndex returns the shard holding the entry at the index in the log
+func (s *ShardedClient) shardByIndex(index int64) (*logShard, bool) {
+	for i := len(s.shards) - 1; i >= 0; i-- {
+		shard := s.shards[i]
+		if index >= shard.startIndex {
+			if shard.length > 0 && index >= shard.startIndex+shard.length {
+				return nil, false
+			}
+			return shard, true
+		}
+	}
+	return nil, false
+}
+
+// fromShard records the tree the response came from, and translates the index of any leaf in it to its index
+// in the log
+func (s *ShardedClient) fromShard(shard *logShard, resp *Response) *Response {
+	resp.logID = shard.treeID
+	if result := resp.getLeafAndProofResult; result != nil && result.Leaf != nil {
+		result.Leaf.LeafIndex += shard.startIndex
+	}
+	return resp
+}
+
+func notFoundResponse(err error) *Response {
+	return &Response{
+		status: codes.NotFound,
+		err:    status.Error(codes.NotFound, err.Error()),
+	}
+}
+
+// findInFrozenShards looks for each of the leaves in the frozen shards, returning a response for each that is
+// found there, by position; the active shard detects leaves that it already holds itself
+func (s *ShardedClient) findInFrozenShards(byteValues [][]byte, concurrency int) map[int]*Response {
+	found := map[int]*Response{}
+	if len(s.frozen()) == 0 {
+		return found
+	}
+	responses := make([]*Response, len(byteValues))
+	forEachConcurrently(len(byteValues), concurrency, func(i int) {
+		leafHash := rfc6962.DefaultHasher.HashLeaf(byteValues[i])
+		for _, shard := range s.frozen() {
+			resp := s.backend(shard).getLeafAndProofByHash(leafHash)
+			if resp.status != codes.OK || resp.getLeafAndProofResult == nil || resp.getLeafAndProofResult.Leaf == nil {
+				continue
+			}
+			s.fromShard(shard, resp)
+			responses[i] = &Response{
+				status: codes.OK,
+				logID:  shard.treeID,
+				getAddResult: &trillian.QueueLeafResponse{
+					QueuedLeaf: &trillian.QueuedLogLeaf{
+						Leaf:   resp.getLeafAndProofResult.Leaf,
+						Status: status.New(codes.AlreadyExists, "leaf already exists in a frozen shard").Proto(),
+					},
+				},
+			}
+			return
+		}
+	})
+	for i, resp := range responses {
+		if resp != nil {
+			found[i] = resp
+		}
+	}
+	return found
+}
+
+func (s *ShardedClient) addLeaf(byteValue []byte) *Response {
+	return s.addLeaves([][]byte{byteValue}, 1)[0]
+}
+
+// addLeaves adds the leaves to the active shard, unless they are already in a frozen shard
+func (s *ShardedClient) addLeaves(byteValues [][]byte, concurrency int) []*Response {
+	return s.add(byteValues, concurrency, false)
+}
+
+func (s *ShardedClient) queueLeaf(byteValue []byte) *Response {
+	return s.add([][]byte{byteValue}, 1, true)[0]
+}
+
+func (s *ShardedClient) add(byteValues [][]byte, concurrency int, queueOnly bool) []*Response {
+	responses := make([]*Response, len(byteValues))
+	found := s.findInFrozenShards(byteValues, concurrency)
+
+	var positions []int
+	var toAdd [][]byte
+	for i, v := range byteValues {
+		if resp, ok := found[i]; ok {
+			responses[i] = resp
+			continue
+		}
+		positions = append(positions, i)
+		toAdd = append(toAdd, v)
+	}
+	if len(toAdd) == 0 {
+		return responses
+	}
+
+	active := s.active()
+	var added []*Response
+	if queueOnly {
+		added = []*Response{s.backend(active).queueLeaf(toAdd[0])}
+	} else {
+		added = s.backend(active).addLeaves(toAdd, concurrency)
+	}
+	for j, resp := range added {
+		resp.logID = active.treeID
+		// leaves that were queued but not yet included in the log have no index to translate
+		if !queueOnly && resp.status == codes.OK {
+			queuedLeaf := resp.getAddResult.QueuedLeaf
+			if queuedLeaf.Status == nil || queuedLeaf.Status.Code == int32(codes.OK) {
+				queuedLeaf.Leaf.LeafIndex += active.startIndex
+			}
+		}
+		responses[positions[j]] = resp
+	}
+	return responses
+}
+
+// getLeafAndProofByIndex returns the leaf at the index in the log, with an inclusion proof in the tree of its shard
+func (s *ShardedClient) getLeafAndProofByIndex(index int64) *Response {
+	shard, ok := s.shardByIndex(index)
+	if !ok {
+		return notFoundResponse(fmt.Errorf("no shard holds index %d", index))
+	}
+	return s.fromShard(shard, s.backend(shard).getLeafAndProofByIndex(index-shard.startIndex))
+}
+
+// getLeafAndProofByHash returns the leaf with the hash from the tree with the ID given; if the tree ID is 0, each
+// shard is searched, from the newest to the oldest
+func (s *ShardedClient) getLeafAndProofByHash(treeID int64, hash []byte) *Response {
+	if treeID != 0 {
+		shard, ok := s.shardByTreeID(treeID)
+		if !ok {
+			return notFoundResponse(fmt.Errorf("no shard is in tree %d", treeID))
+		}
+		return s.fromShard(shard, s.backend(shard).getLeafAndProofByHash(hash))
+	}
+	var resp *Response
+	for i := len(s.shards) - 1; i >= 0; i-- {
+		resp = s.fromShard(s.shards[i], s.backend(s.shards[i]).getLeafAndProofByHash(hash))
+		if resp.status != codes.NotFound {
+			return resp
+		}
+	}
+	return resp
+}
+
+// getLeafAndProofByEntryID returns the leaf with the entry ID, which may or may not have a tree ID prefix
+func (s *ShardedClient) getLeafAndProofByEntryID(entryID string) (*Response, error) {
+	treeID, uuid, err := sharding.ParseEntryID(entryID)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := hex.DecodeString(uuid)
+	if err != nil {
+		return nil, err
+	}
+	return s.getLeafAndProofByHash(treeID, hash), nil
+}
+
+// getLatest returns the latest root of the tree with the ID given, or of the active shard if the ID is 0
+func (s *ShardedClient) getLatest(treeID int64, firstSize int64) *Response {
+	shard, ok := s.shardByTreeID(treeID)
+	if !ok {
+		return notFoundResponse(fmt.Errorf("no shard is in tree %d", treeID))
+	}
+	return s.fromShard(shard, s.backend(shard).getLatest(firstSize))
+}
+
+// getConsistencyProof returns a consistency proof between two sizes of the tree with the ID given, or of the
+// active shard if the ID is 0
+func (s *ShardedClient) getConsistencyProof(treeID int64, firstSize, lastSize int64) *Response {
+	shard, ok := s.shardByTreeID(treeID)
+	if !ok {
+		return notFoundResponse(fmt.Errorf("no shard is in tree %d", treeID))
+	}
+	return s.fromShard(shard, s.backend(shard).getConsistencyProof(firstSize, lastSize))
+}
@@ -16,67 +16,116 @@
 package api
 
 import (
+	"context"
 	"encoding/hex"
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/go-openapi/runtime/middleware"
 	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+	"github.com/google/trillian"
 	"github.com/google/trillian/types"
 	"google.golang.org/grpc/codes"
 
 	"github.com/sigstore/rekor/pkg/generated/models"
 	"github.com/sigstore/rekor/pkg/generated/restapi/operations/tlog"
+	"github.com/sigstore/rekor/pkg/pki"
 )
 
-// GetLogInfoHandler returns the current size of the tree and the STH
+// GetLogInfoHandler returns the current size of the tree and the STH of the active shard, along with those of
+// any frozen shards, the cosignatures of witnesses over tree heads of the active shard and the signature formats
+// the server supports
 func GetLogInfoHandler(params tlog.GetLogInfoParams) middleware.Responder {
-	tc := NewTrillianClient(params.HTTPRequest.Context())
+	tc := NewShardedClient(params.HTTPRequest.Context())
 
-	resp := tc.getLatest(0)
+	resp := tc.getLatest(0, 0)
 	if resp.status != codes.OK {
 		return handleRekorAPIError(params, http.StatusInternalServerError, fmt.Errorf("grpc error: %w", resp.err), trillianCommunicationError)
 	}
-	result := resp.getLatestResult
-
-	root := &types.LogRootV1{}
-	if err := root.UnmarshalBinary(result.SignedLogRoot.LogRoot); err != nil {
+	root, sth, err := signedTreeHead(params.HTTPRequest.Context(), resp.getLatestResult.SignedLogRoot)
+	if err != nil {
 		return handleRekorAPIError(params, http.StatusInternalServerError, err, trillianUnexpectedResult)
 	}
 
 	hashString := hex.EncodeToString(root.RootHash)
 	treeSize := int64(root.TreeSize)
-	logRoot := strfmt.Base64(result.SignedLogRoot.GetLogRoot())
+
+	logInfo := models.LogInfo{
+		RootHash:         &hashString,
+		TreeSize:         &treeSize,
+		SignedTreeHead:   sth,
+		TreeID:           strconv.FormatInt(resp.logID, 10),
+		StartIndex:       tc.active().startIndex,
+		Cosignatures:     logFromContext(params.HTTPRequest.Context()).logCosignatures(resp.logID),
+		SupportedFormats: pki.SupportedFormats(),
+	}
+
+	for _, shard := range tc.frozen() {
+		resp := tc.getLatest(shard.treeID, 0)
+		if resp.status != codes.OK {
+			return handleRekorAPIError(params, http.StatusInternalServerError, fmt.Errorf("grpc error: %w", resp.err), trillianCommunicationError)
+		}
+		root, sth, err := signedTreeHead(params.HTTPRequest.Context(), resp.getLatestResult.SignedLogRoot)
+		if err != nil {
+			return handleRekorAPIError(params, http.StatusInternalServerError, err, trillianUnexpectedResult)
+		}
+		logInfo.InactiveShards = append(logInfo.InactiveShards, &models.InactiveShardLogInfo{
+			RootHash:   swag.String(hex.EncodeToString(root.RootHash)),
+			TreeSize:   swag.Int64(int64(root.TreeSize)),
+			TreeID:     swag.String(strconv.FormatInt(shard.treeID, 10)),
+			StartIndex: swag.Int64(shard.startIndex),
+			SignedTreeHead: &models.InactiveShardLogInfoSignedTreeHead{
+				KeyHint:   sth.KeyHint,
+				LogRoot:   sth.LogRoot,
+				Signature: sth.Signature,
+			},
+		})
+	}
+	return tlog.NewGetLogInfoOK().WithPayload(&logInfo)
+}
+
+// signedTreeHead returns the log root, signed by the log's signer and with the ID of its key as the key hint
+func signedTreeHead(ctx context.Context, signedLogRoot *trillian.SignedLogRoot) (*types.LogRootV1, *models.LogInfoSignedTreeHead, error) {
+	root := &types.LogRootV1{}
+	if err := root.UnmarshalBinary(signedLogRoot.LogRoot); err != nil {
+		return nil, nil, err
+	}
+	logRoot := strfmt.Base64(signedLogRoot.GetLogRoot())
 
 	// sign the log root ourselves to get the log root signature
-	sig, _, err := api.signer.Sign(params.HTTPRequest.Context(), result.SignedLogRoot.GetLogRoot())
+	a := logFromContext(ctx)
+	sig, _, err := a.signer.Sign(ctx, signedLogRoot.GetLogRoot())
 	if err != nil {
-		return handleRekorAPIError(params, http.StatusInternalServerError, fmt.Errorf("signing error: %w", err), trillianCommunicationError)
+		return nil, nil, fmt.Errorf("signing error: %w", err)
 	}
-
 	signature := strfmt.Base64(sig)
+	keyHint := strfmt.Base64(a.keyID)
 
-	sth := models.LogInfoSignedTreeHead{
+	return root, &models.LogInfoSignedTreeHead{
+		KeyHint:   &keyHint,
 		LogRoot:   &logRoot,
 		Signature: &signature,
-	}
-
-	logInfo := models.LogInfo{
-		RootHash:       &hashString,
-		TreeSize:       &treeSize,
-		SignedTreeHead: &sth,
-	}
-	return tlog.NewGetLogInfoOK().WithPayload(&logInfo)
+	}, nil
 }
 
-// GetLogProofHandler returns information required to compute a consistency proof between two snapshots of log
+// GetLogProofHandler returns information required to compute a consistency proof between two snapshots of log;
+// the proof is within the tree of the shard requested, or of the active shard if none is
 func GetLogProofHandler(params tlog.GetLogProofParams) middleware.Responder {
 	if *params.FirstSize > params.LastSize {
 		return handleRekorAPIError(params, http.StatusBadRequest, nil, fmt.Sprintf(firstSizeLessThanLastSize, *params.FirstSize, params.LastSize))
 	}
-	tc := NewTrillianClient(params.HTTPRequest.Context())
+	treeID, err := parseTreeID(params.TreeID)
+	if err != nil {
+		return handleRekorAPIError(params, http.StatusBadRequest, err, err.Error())
+	}
+	tc := NewShardedClient(params.HTTPRequest.Context())
+	if _, ok := tc.shardByTreeID(treeID); !ok {
+		return handleRekorAPIError(params, http.StatusBadRequest, nil, fmt.Sprintf(unknownTreeID, treeID))
+	}
 
-	resp := tc.getConsistencyProof(*params.FirstSize, params.LastSize)
+	resp := tc.getConsistencyProof(treeID, *params.FirstSize, params.LastSize)
 	if resp.status != codes.OK {
 		return handleRekorAPIError(params, http.StatusInternalServerError, fmt.Errorf("grpc error: %w", resp.err), trillianCommunicationError)
 	}
@@ -108,3 +157,15 @@ func GetLogProofHandler(params tlog.GetLogProofParams) middleware.Responder {
 
 	return tlog.NewGetLogProofOK().WithPayload(&consistencyProof)
 }
+
+// parseTreeID returns the tree ID given as a query parameter, or 0 if none was given
+func parseTreeID(treeID *string) (int64, error) {
+	if treeID == nil || *treeID == "" {
+		return 0, nil
+	}
+	id, err := strconv.ParseInt(*treeID, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid tree ID %v: %w", *treeID, err)
+	}
+	return id, nil
+}
@@ -19,6 +19,7 @@ import (
 	"context"
 	"encoding/hex"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/google/trillian/merkle/logverifier"
@@ -43,11 +44,12 @@ type TrillianClient struct {
 }
 
 func NewTrillianClient(ctx context.Context) TrillianClient {
+	a := logFromContext(ctx)
 	return TrillianClient{
-		client:   api.logClient,
-		logID:    api.logID,
+		client:   a.logClient,
+		logID:    a.logID,
 		context:  ctx,
-		verifier: api.verifier,
+		verifier: a.verifier,
 	}
 }
 
@@ -59,6 +61,8 @@ type Response struct {
 	getLeafAndProofResult     *trillian.GetEntryAndProofResponse
 	getLatestResult           *trillian.GetLatestSignedLogRootResponse
 	getConsistencyProofResult *trillian.GetConsistencyProofResponse
+	// tree of the shard that the response came from
+	logID int64
 }
 
 func (t *TrillianClient) root() (types.LogRootV1, error) {
@@ -77,102 +81,138 @@ func (t *TrillianClient) root() (types.LogRootV1, error) {
 }
 
 func (t *TrillianClient) addLeaf(byteValue []byte) *Response {
-	leaf := &trillian.LogLeaf{
-		LeafValue: byteValue,
-	}
+	return t.addLeaves([][]byte{byteValue}, 1)[0]
+}
+
+// queueLeaf queues the leaf to be included in the log, without waiting for it to be included
+func (t *TrillianClient) queueLeaf(byteValue []byte) *Response {
 	rqst := &trillian.QueueLeafRequest{
 		LogId: t.logID,
-		Leaf:  leaf,
+		Leaf: &trillian.LogLeaf{
+			LeafValue: byteValue,
+		},
 	}
 	resp, err := t.client.QueueLeaf(t.context, rqst)
+	return &Response{
+		status:       status.Code(err),
+		err:          err,
+		getAddResult: resp,
+	}
+}
 
-	// check for error
-	if err != nil || (resp.QueuedLeaf.Status != nil && resp.QueuedLeaf.Status.Code != int32(codes.OK)) {
-		return &Response{
+// addLeaves queues each of the leaves to the log, and then waits for all of those that were queued to be
+// included in the log; the response for each leaf is returned in the same position as the leaf. Trillian
+// can only add many leaves in a single request to a pre-ordered log, so each leaf is queued separately
+// with at most concurrency requests in flight.
+func (t *TrillianClient) addLeaves(byteValues [][]byte, concurrency int) []*Response {
+	responses := make([]*Response, len(byteValues))
+	forEachConcurrently(len(byteValues), concurrency, func(i int) {
+		responses[i] = t.queueLeaf(byteValues[i])
+	})
+
+	// leaf hashes of the queued leaves, by position
+	pending := map[int][]byte{}
+	for i, resp := range responses {
+		if resp.err == nil && (resp.getAddResult.QueuedLeaf.Status == nil || resp.getAddResult.QueuedLeaf.Status.Code == int32(codes.OK)) {
+			pending[i] = resp.getAddResult.QueuedLeaf.Leaf.MerkleLeafHash
+		}
+	}
+	fail := func(i int, err error) {
+		responses[i] = &Response{
 			status:       status.Code(err),
 			err:          err,
-			getAddResult: resp,
+			getAddResult: responses[i].getAddResult,
+		}
+	}
+	failPending := func(err error) []*Response {
+		for i := range pending {
+			fail(i, err)
 		}
+		return responses
+	}
+	if len(pending) == 0 {
+		return responses
 	}
 
 	root, err := t.root()
 	if err != nil {
-		return &Response{
-			status:       status.Code(err),
-			err:          err,
-			getAddResult: resp,
-		}
+		return failPending(err)
 	}
 	logClient := client.New(t.logID, t.client, t.verifier, root)
 
-	waitForInclusion := func(ctx context.Context, leafHash []byte) *Response {
-		if logClient.MinMergeDelay > 0 {
-			select {
-			case <-ctx.Done():
-				return &Response{
-					status: codes.DeadlineExceeded,
-					err:    ctx.Err(),
-				}
-			case <-time.After(logClient.MinMergeDelay):
-			}
+	if logClient.MinMergeDelay > 0 {
+		select {
+		case <-t.context.Done():
+			return failPending(t.context.Err())
+		case <-time.After(logClient.MinMergeDelay):
 		}
-		for {
-			root = *logClient.GetRoot()
-			if root.TreeSize >= 1 {
-				proofResp := t.getProofByHash(resp.QueuedLeaf.Leaf.MerkleLeafHash)
-				// if this call succeeds or returns an error other than "not found", return
-				if proofResp.err == nil || (proofResp.err != nil && status.Code(proofResp.err) != codes.NotFound) {
-					return proofResp
-				}
-				// otherwise wait for a root update before trying again
+	}
+	for {
+		if logClient.GetRoot().TreeSize >= 1 {
+			indexes := make([]int, 0, len(pending))
+			for i := range pending {
+				indexes = append(indexes, i)
 			}
-
-			if _, err := logClient.WaitForRootUpdate(ctx); err != nil {
-				return &Response{
-					status: codes.Unknown,
-					err:    err,
+			proofResps := make([]*Response, len(indexes))
+			forEachConcurrently(len(indexes), concurrency, func(j int) {
+				proofResps[j] = t.getProofByHash(pending[indexes[j]])
+			})
+
+			for j, proofResp := range proofResps {
+				// if this call returns "not found", wait for a root update before trying again
+				if proofResp.err != nil && status.Code(proofResp.err) == codes.NotFound {
+					continue
+				}
+				i := indexes[j]
+				delete(pending, i)
+				if proofResp.err != nil {
+					fail(i, proofResp.err)
+					continue
+				}
+				proofs := proofResp.getProofResult.Proof
+				if len(proofs) != 1 {
+					fail(i, fmt.Errorf("expected 1 proof from getProofByHash for %v, found %v", hex.EncodeToString(responses[i].getAddResult.QueuedLeaf.Leaf.MerkleLeafHash), len(proofs)))
+					continue
 				}
+				leafResp := t.getLeafAndProofByIndex(proofs[0].LeafIndex)
+				if leafResp.err != nil {
+					fail(i, leafResp.err)
+					continue
+				}
+				// overwrite queued leaf that doesn't have index set
+				responses[i].getAddResult.QueuedLeaf.Leaf = leafResp.getLeafAndProofResult.Leaf
+			}
+			if len(pending) == 0 {
+				return responses
 			}
 		}
-	}
-
-	proofResp := waitForInclusion(t.context, resp.QueuedLeaf.Leaf.MerkleLeafHash)
-	if proofResp.err != nil {
-		return &Response{
-			status:       status.Code(proofResp.err),
-			err:          proofResp.err,
-			getAddResult: resp,
-		}
-	}
 
-	proofs := proofResp.getProofResult.Proof
-	if len(proofs) != 1 {
-		err := fmt.Errorf("expected 1 proof from getProofByHash for %v, found %v", hex.EncodeToString(resp.QueuedLeaf.Leaf.MerkleLeafHash), len(proofs))
-		return &Response{
-			status:       status.Code(err),
-			err:          err,
-			getAddResult: resp,
+		if _, err := logClient.WaitForRootUpdate(t.context); err != nil {
+			return failPending(err)
 		}
 	}
+}
 
-	leafIndex := proofs[0].LeafIndex
-	leafResp := t.getLeafAndProofByIndex(leafIndex)
-	if leafResp.err != nil {
-		return &Response{
-			status:       status.Code(leafResp.err),
-			err:          leafResp.err,
-			getAddResult: resp,
-		}
+// forEachConcurrently calls fn for each index in [0, n), with at most concurrency calls running at once
+func forEachConcurrently(n, concurrency int, fn func(i int)) {
+	if concurrency < 1 {
+		concurrency = 1
 	}
-
-	// overwrite queued leaf that doesn't have index set
-	resp.QueuedLeaf.Leaf = leafResp.getLeafAndProofResult.Leaf
-
-	return &Response{
-		status:       status.Code(err),
-		err:          err,
-		getAddResult: resp,
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		i := i // https://golang.org/doc/faq#closures_and_goroutines
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			fn(i)
+		}()
 	}
+	wg.Wait()
 }
 
 func (t *TrillianClient) getLeafAndProofByHash(hash []byte) *Response {
@@ -321,6 +361,12 @@ func createAndInitTree(ctx context.Context, adminClient trillian.TrillianAdminCl
 	}
 
 	// Otherwise create and initialize one
+	return CreateLogTree(ctx, adminClient, logClient, "", "")
+}
+
+// CreateLogTree creates a new log tree with the display name and description given, and initializes it so that
+// entries can be added
+func CreateLogTree(ctx context.Context, adminClient trillian.TrillianAdminClient, logClient trillian.TrillianLogClient, displayName, description string) (*trillian.Tree, error) {
 	t, err := adminClient.CreateTree(ctx, &trillian.CreateTreeRequest{
 		Tree: &trillian.Tree{
 			TreeType:           trillian.TreeType_LOG,
@@ -328,6 +374,8 @@ func createAndInitTree(ctx context.Context, adminClient trillian.TrillianAdminCl
 			SignatureAlgorithm: sigpb.DigitallySigned_ECDSA,
 			TreeState:          trillian.TreeState_ACTIVE,
 			MaxRootDuration:    durationpb.New(time.Hour),
+			DisplayName:        displayName,
+			Description:        description,
 		},
 		KeySpec: &keyspb.Specification{
 			Params: &keyspb.Specification_EcdsaParams{
@@ -0,0 +1,212 @@
+//
+// Copyright 2021 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/viper"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	_ "google.golang.org/grpc/health" // registers the client side health check used by healthCheckConfig
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/resolver/manual"
+	"google.golang.org/grpc/status"
+
+	"github.com/sigstore/rekor/pkg/log"
+)
+
+// trillianResolverScheme is the scheme of the target dialed when several log server addresses are configured
+const trillianResolverScheme = "rekor-trillian"
+
+// DialTrillian connects to the Trillian log server(s), returning an error if no connection is ready within
+// trillian_log_server.dial_timeout
+func DialTrillian(ctx context.Context) (*grpc.ClientConn, error) {
+	ctx, cancel := context.WithTimeout(ctx, viper.GetDuration("trillian_log_server.dial_timeout"))
+	defer cancel()
+
+	opts := []grpc.DialOption{
+		grpc.WithBlock(),
+		grpc.WithChainUnaryInterceptor(retryInterceptor(retryPolicyFromConfig())),
+	}
+
+	if viper.GetBool("trillian_log_server.tls.enabled") {
+		tlsConfig, err := trillianTLSConfig()
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+	}
+
+	if kaTime := viper.GetDuration("trillian_log_server.keepalive.time"); kaTime > 0 {
+		opts = append(opts, grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                kaTime,
+			Timeout:             viper.GetDuration("trillian_log_server.keepalive.timeout"),
+			PermitWithoutStream: viper.GetBool("trillian_log_server.keepalive.permit_without_stream"),
+		}))
+	}
+
+	serviceConfig, err := trillianServiceConfig(viper.GetBool("trillian_log_server.health_check"))
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, grpc.WithDefaultServiceConfig(serviceConfig))
+
+	// requests are balanced across all of the addresses, if more than one is given
+	target := fmt.Sprintf("%s:%d", viper.GetString("trillian_log_server.address"), viper.GetUint("trillian_log_server.port"))
+	if addresses := viper.GetStringSlice("trillian_log_server.addresses"); len(addresses) > 0 {
+		r := manual.NewBuilderWithScheme(trillianResolverScheme)
+		state := resolver.State{}
+		for _, addr := range addresses {
+			state.Addresses = append(state.Addresses, resolver.Address{Addr: addr})
+		}
+		r.InitialState(state)
+		opts = append(opts, grpc.WithResolvers(r))
+		target = trillianResolverScheme + ":///trillian"
+	}
+
+	conn, err := grpc.DialContext(ctx, target, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to Trillian log server at %v: %w", target, err)
+	}
+	log.Logger.Infof("Connected to Trillian log server at %v", target)
+	return conn, nil
+}
+
+// trillianTLSConfig returns the configuration used to connect to the log server over TLS; a client certificate is
+// presented if one is configured
+func trillianTLSConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: viper.GetString("trillian_log_server.tls.server_name"),
+	}
+	if caFile := viper.GetString("trillian_log_server.tls.ca_file"); caFile != "" {
+		caPEM, err := ioutil.ReadFile(filepath.Clean(caFile))
+		if err != nil {
+			return nil, fmt.Errorf("reading Trillian CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("no certificates found in Trillian CA file %v", caFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	certFile := viper.GetString("trillian_log_server.tls.cert_file")
+	keyFile := viper.GetString("trillian_log_server.tls.key_file")
+	if (certFile == "") != (keyFile == "") {
+		return nil, errors.New("trillian_log_server.tls.cert_file and trillian_log_server.tls.key_file must be set together")
+	}
+	if certFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading Trillian client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+// trillianServiceConfig returns the gRPC service config for the connection to the log server; requests are
+// balanced round robin across the connected addresses, skipping any that fail health checks if enabled
+func trillianServiceConfig(healthCheck bool) (string, error) {
+	config := map[string]interface{}{
+		"loadBalancingConfig": []map[string]interface{}{
+			{"round_robin": map[string]interface{}{}},
+		},
+	}
+	if healthCheck {
+		config["healthCheckConfig"] = map[string]interface{}{
+			"serviceName": "",
+		}
+	}
+	b, err := json.Marshal(config)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// retryPolicy controls how calls to the log server that fail because the server is unavailable or overloaded are
+// retried
+type retryPolicy struct {
+	MaxAttempts       int
+	InitialBackoff    time.Duration
+	MaxBackoff        time.Duration
+	BackoffMultiplier float64
+}
+
+func retryPolicyFromConfig() retryPolicy {
+	return retryPolicy{
+		MaxAttempts:       viper.GetInt("trillian_log_server.retry.max_attempts"),
+		InitialBackoff:    viper.GetDuration("trillian_log_server.retry.initial_backoff"),
+		MaxBackoff:        viper.GetDuration("trillian_log_server.retry.max_backoff"),
+		BackoffMultiplier: viper.GetFloat64("trillian_log_server.retry.backoff_multiplier"),
+	}
+}
+
+// retryable returns whether a call that failed with the code may be retried
+func retryable(c codes.Code) bool {
+	return c == codes.Unavailable || c == codes.ResourceExhausted
+}
+
+// retryInterceptor retries unary calls that fail with a retryable code, up to MaxAttempts calls in all; the time
+// waited before each retry is chosen at random up to a backoff that grows by BackoffMultiplier after each attempt
+func retryInterceptor(p retryPolicy) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		backoff := p.InitialBackoff
+		for attempt := 1; ; attempt++ {
+			err := invoker(ctx, method, req, reply, cc, opts...)
+			if err == nil || attempt >= p.MaxAttempts || !retryable(status.Code(err)) {
+				return err
+			}
+
+			wait := time.Duration(0)
+			if backoff > 0 {
+				wait = time.Duration(rand.Int63n(int64(backoff))) //nolint:gosec
+			}
+			log.Logger.Warnf("retrying %v after %v (attempt %d of %d): %v", method, wait, attempt, p.MaxAttempts, err)
+			select {
+			case <-ctx.Done():
+				return err
+			case <-time.After(wait):
+			}
+
+			backoff = p.nextBackoff(backoff)
+		}
+	}
+}
+
+// nextBackoff returns the backoff after an attempt made with the given backoff, which is at most MaxBackoff if set
+func (p retryPolicy) nextBackoff(backoff time.Duration) time.Duration {
+	backoff = time.Duration(float64(backoff) * p.BackoffMultiplier)
+	if p.MaxBackoff > 0 && backoff > p.MaxBackoff {
+		backoff = p.MaxBackoff
+	}
+	return backoff
+}
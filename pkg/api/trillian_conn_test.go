@@ -0,0 +1,146 @@
+//
+// Copyright 2021 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// failingInvoker returns an invoker that fails with the given errors in turn and then succeeds, counting its calls
+func failingInvoker(calls *int, errs ...error) grpc.UnaryInvoker {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		*calls++
+		if *calls <= len(errs) {
+			return errs[*calls-1]
+		}
+		return nil
+	}
+}
+
+func TestRetryInterceptor(t *testing.T) {
+	unavailable := status.Error(codes.Unavailable, "unavailable")
+	exhausted := status.Error(codes.ResourceExhausted, "overloaded")
+	policy := retryPolicy{
+		MaxAttempts:       3,
+		InitialBackoff:    time.Millisecond,
+		MaxBackoff:        2 * time.Millisecond,
+		BackoffMultiplier: 2,
+	}
+
+	tests := []struct {
+		name      string
+		errs      []error
+		wantCalls int
+		wantCode  codes.Code
+	}{
+		{"success", nil, 1, codes.OK},
+		{"unavailable then success", []error{unavailable}, 2, codes.OK},
+		{"exhausted then success", []error{exhausted, unavailable}, 3, codes.OK},
+		{"attempts used up", []error{unavailable, unavailable, exhausted, unavailable}, 3, codes.ResourceExhausted},
+		{"not found", []error{status.Error(codes.NotFound, "missing")}, 1, codes.NotFound},
+		{"invalid argument", []error{status.Error(codes.InvalidArgument, "bad"), unavailable}, 1, codes.InvalidArgument},
+		{"not a grpc error", []error{errors.New("broken")}, 1, codes.Unknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			err := retryInterceptor(policy)(context.Background(), "/test", nil, nil, nil, failingInvoker(&calls, tt.errs...))
+			if calls != tt.wantCalls {
+				t.Errorf("expected %d calls, got %d", tt.wantCalls, calls)
+			}
+			if code := status.Code(err); code != tt.wantCode {
+				t.Errorf("expected code %v, got %v (%v)", tt.wantCode, code, err)
+			}
+		})
+	}
+}
+
+func TestRetryInterceptorSingleAttempt(t *testing.T) {
+	for _, maxAttempts := range []int{0, 1} {
+		calls := 0
+		policy := retryPolicy{MaxAttempts: maxAttempts, InitialBackoff: time.Millisecond, BackoffMultiplier: 2}
+		err := retryInterceptor(policy)(context.Background(), "/test", nil, nil, nil, failingInvoker(&calls, status.Error(codes.Unavailable, "unavailable")))
+		if calls != 1 || status.Code(err) != codes.Unavailable {
+			t.Errorf("MaxAttempts %d: expected a single failed call, got %d calls and %v", maxAttempts, calls, err)
+		}
+	}
+}
+
+func TestRetryInterceptorContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		calls++
+		cancel()
+		return status.Error(codes.Unavailable, "unavailable")
+	}
+	policy := retryPolicy{MaxAttempts: 5, InitialBackoff: time.Hour, MaxBackoff: time.Hour, BackoffMultiplier: 2}
+
+	start := time.Now()
+	err := retryInterceptor(policy)(ctx, "/test", nil, nil, nil, invoker)
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("expected the wait to end when the context was canceled, took %v", elapsed)
+	}
+	if calls != 1 {
+		t.Errorf("expected no retry after the context was canceled, got %d calls", calls)
+	}
+	if status.Code(err) != codes.Unavailable {
+		t.Errorf("expected the error of the last call, got %v", err)
+	}
+}
+
+func TestRetryInterceptorBackoffCap(t *testing.T) {
+	// without the cap the second wait could be as long as an hour
+	policy := retryPolicy{MaxAttempts: 4, InitialBackoff: 10 * time.Millisecond, MaxBackoff: 20 * time.Millisecond, BackoffMultiplier: 360000}
+	unavailable := status.Error(codes.Unavailable, "unavailable")
+	calls := 0
+
+	start := time.Now()
+	err := retryInterceptor(policy)(context.Background(), "/test", nil, nil, nil, failingInvoker(&calls, unavailable, unavailable, unavailable))
+	if err != nil || calls != 4 {
+		t.Fatalf("expected success on the fourth call, got %d calls and %v", calls, err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("expected the waits to be capped at %v, took %v", policy.MaxBackoff, elapsed)
+	}
+}
+
+func TestRetryPolicyNextBackoff(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  retryPolicy
+		backoff time.Duration
+		want    time.Duration
+	}{
+		{"grows", retryPolicy{MaxBackoff: time.Second, BackoffMultiplier: 1.5}, 100 * time.Millisecond, 150 * time.Millisecond},
+		{"capped", retryPolicy{MaxBackoff: time.Second, BackoffMultiplier: 4}, 500 * time.Millisecond, time.Second},
+		{"at cap", retryPolicy{MaxBackoff: time.Second, BackoffMultiplier: 2}, time.Second, time.Second},
+		{"no cap", retryPolicy{BackoffMultiplier: 10}, time.Second, 10 * time.Second},
+		{"constant", retryPolicy{MaxBackoff: time.Second, BackoffMultiplier: 1}, 200 * time.Millisecond, 200 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		if got := tt.policy.nextBackoff(tt.backoff); got != tt.want {
+			t.Errorf("%v: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
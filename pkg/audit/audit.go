@@ -0,0 +1,154 @@
+//
+// Copyright 2021 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package audit rebuilds the Merkle tree of a log from its leaves, and checks that each leaf is the canonical form
+// of a valid entry, independently of the log.
+package audit
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-openapi/runtime"
+	"github.com/google/trillian/merkle/compact"
+	rfc6962 "github.com/google/trillian/merkle/rfc6962/hasher"
+	"github.com/google/trillian/types"
+
+	"github.com/sigstore/rekor/pkg/generated/models"
+	rekortypes "github.com/sigstore/rekor/pkg/types"
+)
+
+var (
+	// ErrRootMismatch is returned if the root hash of the rebuilt tree is not that of the tree head of the log
+	ErrRootMismatch = errors.New("root hash of the rebuilt tree does not match the tree head")
+	// ErrNotCanonical is returned if an entry canonicalizes to something other than the leaf stored in the log
+	ErrNotCanonical = errors.New("leaf is not the canonical form of the entry")
+	// ErrArtifactUnavailable is returned if an entry can only be canonicalized with its artifact, and the artifact
+	// is not available
+	ErrArtifactUnavailable = errors.New("artifact of the entry is not available")
+)
+
+var rangeFactory = &compact.RangeFactory{Hash: rfc6962.DefaultHasher.HashChildren}
+
+// TreeState is the compact range of the leaves of a tree that have been audited, from which the root hash of the
+// tree is computed, and the audit resumed, without the leaves
+type TreeState struct {
+	TreeID string
+	Size   uint64
+	Hashes [][]byte
+}
+
+// Tree is a Merkle tree rebuilt from the leaves of a log
+type Tree struct {
+	treeID string
+	rng    *compact.Range
+}
+
+// NewTree returns a tree that resumes from the state given; the zero state is an empty tree
+func NewTree(state TreeState) (*Tree, error) {
+	rng, err := rangeFactory.NewRange(0, state.Size, state.Hashes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid state of tree %v: %w", state.TreeID, err)
+	}
+	return &Tree{treeID: state.TreeID, rng: rng}, nil
+}
+
+// Size returns the number of leaves in the tree
+func (t *Tree) Size() uint64 {
+	return t.rng.End()
+}
+
+// State returns the state to resume the tree from
+func (t *Tree) State() TreeState {
+	return TreeState{TreeID: t.treeID, Size: t.rng.End(), Hashes: t.rng.Hashes()}
+}
+
+// AppendLeaf appends the leaf to the tree, and returns its RFC 6962 leaf hash
+func (t *Tree) AppendLeaf(leaf []byte) ([]byte, error) {
+	hash := rfc6962.DefaultHasher.HashLeaf(leaf)
+	if err := t.rng.Append(hash, nil); err != nil {
+		return nil, err
+	}
+	return hash, nil
+}
+
+// RootHash returns the RFC 6962 root hash of the tree
+func (t *Tree) RootHash() ([]byte, error) {
+	if t.rng.End() == 0 {
+		return rfc6962.DefaultHasher.EmptyRoot(), nil
+	}
+	return t.rng.GetRootHash(nil)
+}
+
+// VerifyRoot checks that the tree has the size and root hash of the log root, which must already be verified
+func (t *Tree) VerifyRoot(lr *types.LogRootV1) error {
+	if t.rng.End() != lr.TreeSize {
+		return fmt.Errorf("tree has %d leaves, but the tree head is of size %d", t.rng.End(), lr.TreeSize)
+	}
+	root, err := t.RootHash()
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(root, lr.RootHash) {
+		return fmt.Errorf("%w at size %d: computed %x, tree head has %x", ErrRootMismatch, lr.TreeSize, root, lr.RootHash)
+	}
+	return nil
+}
+
+// ArtifactFunc returns the content of the artifact with the hex encoded SHA-256 digest, or nil if it is not
+// available
+type ArtifactFunc func(ctx context.Context, digest string) ([]byte, error)
+
+// CheckEntry decodes the leaf with the current type implementations and canonicalizes it again, checking that the
+// result is the leaf. This verifies the signature in the entry, and that it meets the current admission policy.
+// Entries that hold only the digest of their artifact are canonicalized with the artifact returned by the function,
+// or ErrArtifactUnavailable is returned if there is none.
+func CheckEntry(ctx context.Context, leaf []byte, artifact ArtifactFunc) error {
+	pe, err := models.UnmarshalProposedEntry(bytes.NewReader(leaf), runtime.JSONConsumer())
+	if err != nil {
+		return fmt.Errorf("decoding entry: %w", err)
+	}
+	entry, err := rekortypes.NewEntry(pe)
+	if err != nil {
+		return fmt.Errorf("decoding entry: %w", err)
+	}
+	if ae, ok := entry.(rekortypes.ArtifactEntry); ok {
+		digest := ae.ArtifactHash()
+		if digest == "" {
+			return errors.New("entry does not give the digest of its artifact")
+		}
+		var content []byte
+		if artifact != nil {
+			if content, err = artifact(ctx, digest); err != nil {
+				return fmt.Errorf("getting artifact %v: %w", digest, err)
+			}
+		}
+		if content == nil {
+			return fmt.Errorf("%w: %v", ErrArtifactUnavailable, digest)
+		}
+		ae.AttachArtifact(content)
+	}
+
+	canonical, err := entry.Canonicalize(ctx)
+	if err != nil {
+		return fmt.Errorf("canonicalizing entry: %w", err)
+	}
+	if !bytes.Equal(canonical, leaf) {
+		return ErrNotCanonical
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+//
+// Copyright 2021 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+	rfc6962 "github.com/google/trillian/merkle/rfc6962/hasher"
+	"github.com/google/trillian/types"
+
+	"github.com/sigstore/rekor/pkg/generated/models"
+	"github.com/sigstore/rekor/pkg/pki/pgp"
+	rekortypes "github.com/sigstore/rekor/pkg/types"
+	_ "github.com/sigstore/rekor/pkg/types/rekord/v0.0.1"
+)
+
+// merkleRoot computes the RFC 6962 root hash of the leaves from its definition
+func merkleRoot(leaves [][]byte) []byte {
+	h := rfc6962.DefaultHasher
+	switch len(leaves) {
+	case 0:
+		return h.EmptyRoot()
+	case 1:
+		return h.HashLeaf(leaves[0])
+	}
+	k := 1
+	for k*2 < len(leaves) {
+		k *= 2
+	}
+	return h.HashChildren(merkleRoot(leaves[:k]), merkleRoot(leaves[k:]))
+}
+
+func TestTree(t *testing.T) {
+	var leaves [][]byte
+	for i := 0; i < 20; i++ {
+		leaves = append(leaves, []byte(fmt.Sprintf("leaf %d", i)))
+	}
+
+	tree, err := NewTree(TreeState{TreeID: "1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i <= len(leaves); i++ {
+		lr := &types.LogRootV1{TreeSize: uint64(i), RootHash: merkleRoot(leaves[:i])}
+		if err := tree.VerifyRoot(lr); err != nil {
+			t.Fatalf("size %d: %v", i, err)
+		}
+
+		// a tree resumed from its state has the same root
+		resumed, err := NewTree(tree.State())
+		if err != nil {
+			t.Fatalf("size %d: resuming: %v", i, err)
+		}
+		if err := resumed.VerifyRoot(lr); err != nil {
+			t.Fatalf("size %d: resumed tree: %v", i, err)
+		}
+
+		if i == len(leaves) {
+			break
+		}
+		hash, err := tree.AppendLeaf(leaves[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(hash, rfc6962.DefaultHasher.HashLeaf(leaves[i])) {
+			t.Errorf("leaf %d: unexpected leaf hash %x", i, hash)
+		}
+	}
+	if tree.Size() != uint64(len(leaves)) {
+		t.Errorf("Size() = %d, want %d", tree.Size(), len(leaves))
+	}
+
+	if err := tree.VerifyRoot(&types.LogRootV1{TreeSize: 20, RootHash: merkleRoot(leaves[1:])}); !errors.Is(err, ErrRootMismatch) {
+		t.Errorf("expected root mismatch, got %v", err)
+	}
+	if err := tree.VerifyRoot(&types.LogRootV1{TreeSize: 19, RootHash: merkleRoot(leaves[:19])}); err == nil {
+		t.Errorf("expected error for tree head of another size")
+	}
+	if _, err := NewTree(TreeState{Size: 3, Hashes: [][]byte{{1}}}); err == nil {
+		t.Errorf("expected error for state with the wrong number of hashes")
+	}
+}
+
+func TestCheckEntry(t *testing.T) {
+	ctx := context.Background()
+	artifact, err := ioutil.ReadFile("../../tests/test_file.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := ioutil.ReadFile("../../tests/test_file.sig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := ioutil.ReadFile("../../tests/test_public_key.key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry, err := rekortypes.NewEntry(&models.Rekord{
+		APIVersion: swag.String("0.0.1"),
+		Spec: models.RekordV001Schema{
+			Signature: &models.RekordV001SchemaSignature{
+				Format:    pgp.FORMAT,
+				Content:   strfmt.Base64(sig),
+				PublicKey: &models.RekordV001SchemaSignaturePublicKey{Content: strfmt.Base64(key)},
+			},
+			Data: &models.RekordV001SchemaData{Content: strfmt.Base64(artifact)},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := entry.Canonicalize(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	artifacts := func(content []byte) ArtifactFunc {
+		return func(ctx context.Context, digest string) ([]byte, error) {
+			return content, nil
+		}
+	}
+
+	tests := []struct {
+		name     string
+		leaf     []byte
+		artifact ArtifactFunc
+		wantErr  error
+	}{
+		{name: "canonical", leaf: leaf, artifact: artifacts(artifact)},
+		{name: "artifact unavailable", leaf: leaf, artifact: artifacts(nil), wantErr: ErrArtifactUnavailable},
+		{name: "no artifact store", leaf: leaf, wantErr: ErrArtifactUnavailable},
+		{name: "not canonical", leaf: append(append([]byte{}, leaf...), '\n'), artifact: artifacts(artifact), wantErr: ErrNotCanonical},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CheckEntry(ctx, tt.leaf, tt.artifact); !errors.Is(err, tt.wantErr) {
+				t.Errorf("CheckEntry() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// the signature is verified again over the artifact
+	if err := CheckEntry(ctx, leaf, artifacts([]byte("other artifact"))); err == nil {
+		t.Errorf("expected error for the wrong artifact")
+	}
+	if err := CheckEntry(ctx, []byte(`{"kind":"unknown"}`), nil); err == nil {
+		t.Errorf("expected error for undecodable entry")
+	}
+}
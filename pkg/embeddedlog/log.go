@@ -0,0 +1,411 @@
+//
+// Copyright 2021 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package embeddedlog is an append-only Merkle tree log kept in a single file on disk, for running rekor
+// without a Trillian log server. Trees are computed as specified in RFC 6962, so roots and proofs can be
+// verified in the same way as those from Trillian.
+package embeddedlog
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	rfc6962 "github.com/google/trillian/merkle/rfc6962/hasher"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	// ErrNotFound is returned when there is no leaf with the hash requested
+	ErrNotFound = errors.New("leaf not found")
+	// ErrOutOfRange is returned when an index or tree size requested is beyond the current size of the log
+	ErrOutOfRange = errors.New("requested index or tree size is beyond the size of the log")
+)
+
+var (
+	// leaves holds the timestamp and value of each leaf, by index
+	leavesBucket = []byte("leaves")
+	// hashes holds the index of each leaf, by leaf hash
+	hashesBucket = []byte("hashes")
+	// nodes holds the hash of each complete subtree, by level and index within the level
+	nodesBucket = []byte("nodes")
+	// meta holds the log ID, size and the time the log was last appended to
+	metaBucket = []byte("meta")
+
+	logIDKey     = []byte("log_id")
+	sizeKey      = []byte("size")
+	timestampKey = []byte("timestamp")
+)
+
+var hasher = rfc6962.DefaultHasher
+
+// Leaf is an entry in the log
+type Leaf struct {
+	Index          int64
+	Value          []byte
+	Hash           []byte
+	IntegratedTime time.Time
+}
+
+// Root describes the log at a given size
+type Root struct {
+	TreeSize  uint64
+	RootHash  []byte
+	Timestamp time.Time
+}
+
+// AppendResult is the outcome of appending a leaf to the log; if an identical leaf was already present,
+// Leaf is the existing leaf and Existing is true
+type AppendResult struct {
+	Leaf     Leaf
+	Existing bool
+}
+
+// Log is an append-only Merkle tree log; it is safe for concurrent use
+type Log struct {
+	db *bolt.DB
+	id int64
+}
+
+// Open opens the log kept in the file at path, creating it if it does not exist
+func Open(path string) (*Log, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("opening embedded log %v: %w", path, err)
+	}
+	l := &Log{db: db}
+	if err := db.Update(func(tx *bolt.Tx) error {
+		for _, b := range [][]byte{leavesBucket, hashesBucket, nodesBucket, metaBucket} {
+			if _, err := tx.CreateBucketIfNotExists(b); err != nil {
+				return err
+			}
+		}
+		meta := tx.Bucket(metaBucket)
+		if id := meta.Get(logIDKey); id != nil {
+			l.id = int64(binary.BigEndian.Uint64(id))
+			return nil
+		}
+		id, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+		if err != nil {
+			return err
+		}
+		l.id = id.Int64() + 1
+		return meta.Put(logIDKey, uint64Bytes(uint64(l.id)))
+	}); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("initializing embedded log %v: %w", path, err)
+	}
+	return l, nil
+}
+
+// Close closes the file the log is kept in
+func (l *Log) Close() error {
+	return l.db.Close()
+}
+
+// ID returns the identifier chosen at random when the log was created
+func (l *Log) ID() int64 {
+	return l.id
+}
+
+// Append adds each of the values to the end of the log, unless an identical value is already in the log; the
+// results are returned in the same order as the values
+func (l *Log) Append(values [][]byte) ([]AppendResult, error) {
+	results := make([]AppendResult, len(values))
+	err := l.db.Update(func(tx *bolt.Tx) error {
+		leaves := tx.Bucket(leavesBucket)
+		hashes := tx.Bucket(hashesBucket)
+		nodes := tx.Bucket(nodesBucket)
+		meta := tx.Bucket(metaBucket)
+
+		size := treeSize(tx)
+		now := time.Now()
+		for i, value := range values {
+			leafHash := hasher.HashLeaf(value)
+			if existing := hashes.Get(leafHash); existing != nil {
+				leaf, err := getLeaf(tx, int64(binary.BigEndian.Uint64(existing)))
+				if err != nil {
+					return err
+				}
+				results[i] = AppendResult{Leaf: leaf, Existing: true}
+				continue
+			}
+
+			index := size
+			if err := leaves.Put(uint64Bytes(index), append(uint64Bytes(uint64(now.UnixNano())), value...)); err != nil {
+				return err
+			}
+			if err := hashes.Put(leafHash, uint64Bytes(index)); err != nil {
+				return err
+			}
+			// store the leaf, and the hash of each subtree that the leaf completes
+			hash, level, levelIndex := leafHash, uint8(0), index
+			if err := nodes.Put(nodeKey(level, levelIndex), hash); err != nil {
+				return err
+			}
+			for levelIndex&1 == 1 {
+				left := nodes.Get(nodeKey(level, levelIndex-1))
+				hash = hasher.HashChildren(left, hash)
+				level++
+				levelIndex >>= 1
+				if err := nodes.Put(nodeKey(level, levelIndex), hash); err != nil {
+					return err
+				}
+			}
+			size++
+
+			results[i] = AppendResult{
+				Leaf: Leaf{
+					Index:          int64(index),
+					Value:          value,
+					Hash:           leafHash,
+					IntegratedTime: time.Unix(0, now.UnixNano()),
+				},
+			}
+		}
+		if err := meta.Put(sizeKey, uint64Bytes(size)); err != nil {
+			return err
+		}
+		return meta.Put(timestampKey, uint64Bytes(uint64(now.UnixNano())))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// LeafByIndex returns the leaf at the index
+func (l *Log) LeafByIndex(index int64) (Leaf, error) {
+	var leaf Leaf
+	err := l.db.View(func(tx *bolt.Tx) error {
+		if index < 0 || uint64(index) >= treeSize(tx) {
+			return ErrOutOfRange
+		}
+		var err error
+		leaf, err = getLeaf(tx, index)
+		return err
+	})
+	return leaf, err
+}
+
+// LeafByHash returns the leaf with the RFC 6962 leaf hash
+func (l *Log) LeafByHash(leafHash []byte) (Leaf, error) {
+	var leaf Leaf
+	err := l.db.View(func(tx *bolt.Tx) error {
+		index := tx.Bucket(hashesBucket).Get(leafHash)
+		if index == nil {
+			return ErrNotFound
+		}
+		var err error
+		leaf, err = getLeaf(tx, int64(binary.BigEndian.Uint64(index)))
+		return err
+	})
+	return leaf, err
+}
+
+// Root returns the current root of the log
+func (l *Log) Root() (Root, error) {
+	var root Root
+	err := l.db.View(func(tx *bolt.Tx) error {
+		root.TreeSize = treeSize(tx)
+		if ts := tx.Bucket(metaBucket).Get(timestampKey); ts != nil {
+			root.Timestamp = time.Unix(0, int64(binary.BigEndian.Uint64(ts)))
+		}
+		var err error
+		root.RootHash, err = subtreeHash(tx, 0, root.TreeSize)
+		return err
+	})
+	return root, err
+}
+
+// RootHashAt returns the root hash of the log when it was of the size given
+func (l *Log) RootHashAt(size uint64) ([]byte, error) {
+	var hash []byte
+	err := l.db.View(func(tx *bolt.Tx) error {
+		if size > treeSize(tx) {
+			return ErrOutOfRange
+		}
+		var err error
+		hash, err = subtreeHash(tx, 0, size)
+		return err
+	})
+	return hash, err
+}
+
+// InclusionProof returns the audit path for the leaf at the index in the tree of the size given
+func (l *Log) InclusionProof(index int64, size uint64) ([][]byte, error) {
+	var proof [][]byte
+	err := l.db.View(func(tx *bolt.Tx) error {
+		if size > treeSize(tx) || index < 0 || uint64(index) >= size {
+			return ErrOutOfRange
+		}
+		var err error
+		proof, err = auditPath(tx, uint64(index), 0, size)
+		return err
+	})
+	return proof, err
+}
+
+// ConsistencyProof returns the proof that the tree of the second size is an extension of that of the first
+func (l *Log) ConsistencyProof(first, second uint64) ([][]byte, error) {
+	proof := [][]byte{}
+	err := l.db.View(func(tx *bolt.Tx) error {
+		if first > second || second > treeSize(tx) {
+			return ErrOutOfRange
+		}
+		if first == 0 || first == second {
+			return nil
+		}
+		var err error
+		proof, err = subproof(tx, first, 0, second, true)
+		return err
+	})
+	return proof, err
+}
+
+func treeSize(tx *bolt.Tx) uint64 {
+	if size := tx.Bucket(metaBucket).Get(sizeKey); size != nil {
+		return binary.BigEndian.Uint64(size)
+	}
+	return 0
+}
+
+func getLeaf(tx *bolt.Tx, index int64) (Leaf, error) {
+	v := tx.Bucket(leavesBucket).Get(uint64Bytes(uint64(index)))
+	if len(v) < 8 {
+		return Leaf{}, fmt.Errorf("leaf %d is missing or corrupt", index)
+	}
+	value := make([]byte, len(v)-8)
+	copy(value, v[8:])
+	return Leaf{
+		Index:          index,
+		Value:          value,
+		Hash:           hasher.HashLeaf(value),
+		IntegratedTime: time.Unix(0, int64(binary.BigEndian.Uint64(v[:8]))),
+	}, nil
+}
+
+// subtreeHash returns the hash of the leaves in [start, end); start must be a multiple of the largest power of
+// two less than end-start, as it is for every subtree in RFC 6962
+func subtreeHash(tx *bolt.Tx, start, end uint64) ([]byte, error) {
+	n := end - start
+	if n == 0 {
+		return hasher.EmptyRoot(), nil
+	}
+	if n&(n-1) == 0 && start%n == 0 {
+		level := uint8(0)
+		for 1<<level < n {
+			level++
+		}
+		hash := tx.Bucket(nodesBucket).Get(nodeKey(level, start>>level))
+		if hash == nil {
+			return nil, fmt.Errorf("hash of subtree [%d, %d) is missing", start, end)
+		}
+		return append([]byte{}, hash...), nil
+	}
+	k := largestPowerOfTwoBelow(n)
+	left, err := subtreeHash(tx, start, start+k)
+	if err != nil {
+		return nil, err
+	}
+	right, err := subtreeHash(tx, start+k, end)
+	if err != nil {
+		return nil, err
+	}
+	return hasher.HashChildren(left, right), nil
+}
+
+// auditPath returns PATH(m, D[start:end]) as defined in RFC 6962 section 2.1.1, where m is relative to start
+func auditPath(tx *bolt.Tx, m, start, end uint64) ([][]byte, error) {
+	n := end - start
+	if n == 1 {
+		return [][]byte{}, nil
+	}
+	k := largestPowerOfTwoBelow(n)
+	var path [][]byte
+	var sibling []byte
+	var err error
+	if m < k {
+		if path, err = auditPath(tx, m, start, start+k); err != nil {
+			return nil, err
+		}
+		sibling, err = subtreeHash(tx, start+k, end)
+	} else {
+		if path, err = auditPath(tx, m-k, start+k, end); err != nil {
+			return nil, err
+		}
+		sibling, err = subtreeHash(tx, start, start+k)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return append(path, sibling), nil
+}
+
+// subproof returns SUBPROOF(m, D[start:end], b) as defined in RFC 6962 section 2.1.2, where m is relative to start
+func subproof(tx *bolt.Tx, m, start, end uint64, b bool) ([][]byte, error) {
+	n := end - start
+	if m == n {
+		if b {
+			return [][]byte{}, nil
+		}
+		hash, err := subtreeHash(tx, start, end)
+		if err != nil {
+			return nil, err
+		}
+		return [][]byte{hash}, nil
+	}
+	k := largestPowerOfTwoBelow(n)
+	var proof [][]byte
+	var sibling []byte
+	var err error
+	if m <= k {
+		if proof, err = subproof(tx, m, start, start+k, b); err != nil {
+			return nil, err
+		}
+		sibling, err = subtreeHash(tx, start+k, end)
+	} else {
+		if proof, err = subproof(tx, m-k, start+k, end, false); err != nil {
+			return nil, err
+		}
+		sibling, err = subtreeHash(tx, start, start+k)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return append(proof, sibling), nil
+}
+
+// largestPowerOfTwoBelow returns the largest power of two less than n, for n > 1
+func largestPowerOfTwoBelow(n uint64) uint64 {
+	k := uint64(1)
+	for k<<1 < n {
+		k <<= 1
+	}
+	return k
+}
+
+func nodeKey(level uint8, index uint64) []byte {
+	return append([]byte{level}, uint64Bytes(index)...)
+}
+
+func uint64Bytes(v uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, v)
+	return b
+}
@@ -0,0 +1,191 @@
+//
+// Copyright 2021 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package embeddedlog
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/trillian/merkle/logverifier"
+)
+
+func openTestLog(t *testing.T) (*Log, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "log.db")
+	l, err := Open(path)
+	if err != nil {
+		t.Fatalf("unexpected error opening log: %v", err)
+	}
+	return l, path
+}
+
+func appendLeaves(t *testing.T, l *Log, start, end int) {
+	t.Helper()
+	values := [][]byte{}
+	for i := start; i < end; i++ {
+		values = append(values, []byte(fmt.Sprintf("leaf %d", i)))
+	}
+	results, err := l.Append(values)
+	if err != nil {
+		t.Fatalf("unexpected error appending leaves: %v", err)
+	}
+	for i, r := range results {
+		if r.Existing || r.Leaf.Index != int64(start+i) {
+			t.Fatalf("unexpected result appending leaf %d: %+v", start+i, r)
+		}
+	}
+}
+
+func TestProofs(t *testing.T) {
+	l, _ := openTestLog(t)
+	defer l.Close()
+	verifier := logverifier.New(hasher)
+
+	root, err := l.Root()
+	if err != nil {
+		t.Fatalf("unexpected error getting root: %v", err)
+	}
+	if root.TreeSize != 0 || !bytes.Equal(root.RootHash, hasher.EmptyRoot()) {
+		t.Fatalf("unexpected root of empty log: %+v", root)
+	}
+
+	const size = 37
+	roots := [][]byte{hasher.EmptyRoot()}
+	for i := 0; i < size; i++ {
+		appendLeaves(t, l, i, i+1)
+		root, err := l.Root()
+		if err != nil {
+			t.Fatalf("unexpected error getting root: %v", err)
+		}
+		if root.TreeSize != uint64(i+1) {
+			t.Fatalf("expected tree size %d, got %d", i+1, root.TreeSize)
+		}
+		roots = append(roots, root.RootHash)
+	}
+
+	for treeSize := 1; treeSize <= size; treeSize++ {
+		for index := 0; index < treeSize; index++ {
+			proof, err := l.InclusionProof(int64(index), uint64(treeSize))
+			if err != nil {
+				t.Fatalf("unexpected error getting inclusion proof for %d in %d: %v", index, treeSize, err)
+			}
+			leafHash := hasher.HashLeaf([]byte(fmt.Sprintf("leaf %d", index)))
+			if err := verifier.VerifyInclusionProof(int64(index), int64(treeSize), proof, roots[treeSize], leafHash); err != nil {
+				t.Errorf("inclusion proof for %d in %d did not verify: %v", index, treeSize, err)
+			}
+		}
+		for first := 0; first <= treeSize; first++ {
+			proof, err := l.ConsistencyProof(uint64(first), uint64(treeSize))
+			if err != nil {
+				t.Fatalf("unexpected error getting consistency proof from %d to %d: %v", first, treeSize, err)
+			}
+			if err := verifier.VerifyConsistencyProof(int64(first), int64(treeSize), roots[first], roots[treeSize], proof); err != nil {
+				t.Errorf("consistency proof from %d to %d did not verify: %v", first, treeSize, err)
+			}
+		}
+
+		hash, err := l.RootHashAt(uint64(treeSize))
+		if err != nil {
+			t.Fatalf("unexpected error getting root hash at %d: %v", treeSize, err)
+		}
+		if !bytes.Equal(hash, roots[treeSize]) {
+			t.Errorf("root hash at %d does not match the root when the log was that size", treeSize)
+		}
+	}
+
+	if _, err := l.InclusionProof(size, size); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("expected out of range error for index beyond tree size, got %v", err)
+	}
+	if _, err := l.ConsistencyProof(1, size+1); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("expected out of range error for tree size beyond log, got %v", err)
+	}
+}
+
+func TestAppendExisting(t *testing.T) {
+	l, _ := openTestLog(t)
+	defer l.Close()
+
+	appendLeaves(t, l, 0, 3)
+	results, err := l.Append([][]byte{[]byte("leaf 1"), []byte("leaf 3"), []byte("leaf 3")})
+	if err != nil {
+		t.Fatalf("unexpected error appending leaves: %v", err)
+	}
+	if !results[0].Existing || results[0].Leaf.Index != 1 {
+		t.Errorf("expected existing leaf at index 1, got %+v", results[0])
+	}
+	if results[1].Existing || results[1].Leaf.Index != 3 {
+		t.Errorf("expected new leaf at index 3, got %+v", results[1])
+	}
+	if !results[2].Existing || results[2].Leaf.Index != 3 {
+		t.Errorf("expected leaf repeated in the same append to exist at index 3, got %+v", results[2])
+	}
+
+	root, err := l.Root()
+	if err != nil {
+		t.Fatalf("unexpected error getting root: %v", err)
+	}
+	if root.TreeSize != 4 {
+		t.Errorf("expected tree size 4, got %d", root.TreeSize)
+	}
+}
+
+func TestLookups(t *testing.T) {
+	l, path := openTestLog(t)
+	appendLeaves(t, l, 0, 5)
+	id := l.ID()
+	if err := l.Close(); err != nil {
+		t.Fatalf("unexpected error closing log: %v", err)
+	}
+
+	// the contents of the log should survive it being reopened
+	l, err := Open(path)
+	if err != nil {
+		t.Fatalf("unexpected error reopening log: %v", err)
+	}
+	defer l.Close()
+	if l.ID() != id {
+		t.Errorf("expected log ID %d after reopening, got %d", id, l.ID())
+	}
+
+	leaf, err := l.LeafByIndex(2)
+	if err != nil {
+		t.Fatalf("unexpected error getting leaf by index: %v", err)
+	}
+	if string(leaf.Value) != "leaf 2" || !bytes.Equal(leaf.Hash, hasher.HashLeaf([]byte("leaf 2"))) {
+		t.Errorf("unexpected leaf at index 2: %+v", leaf)
+	}
+	if leaf.IntegratedTime.IsZero() {
+		t.Errorf("expected integrated time to be set")
+	}
+
+	leaf, err = l.LeafByHash(hasher.HashLeaf([]byte("leaf 4")))
+	if err != nil {
+		t.Fatalf("unexpected error getting leaf by hash: %v", err)
+	}
+	if leaf.Index != 4 {
+		t.Errorf("expected leaf at index 4, got %d", leaf.Index)
+	}
+
+	if _, err := l.LeafByIndex(5); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("expected out of range error, got %v", err)
+	}
+	if _, err := l.LeafByHash(hasher.HashLeaf([]byte("leaf 5"))); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
@@ -30,6 +30,7 @@ import (
 	"github.com/go-openapi/runtime"
 	cr "github.com/go-openapi/runtime/client"
 	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
 
 	"github.com/sigstore/rekor/pkg/generated/models"
 )
@@ -77,6 +78,12 @@ func NewCreateLogEntryParamsWithHTTPClient(client *http.Client) *CreateLogEntryP
 */
 type CreateLogEntryParams struct {
 
+	/* Async.
+
+	   respond once the entry has been queued, rather than waiting for it to be included in the log
+	*/
+	Async *bool
+
 	// ProposedEntry.
 	ProposedEntry models.ProposedEntry
 
@@ -97,7 +104,18 @@ func (o *CreateLogEntryParams) WithDefaults() *CreateLogEntryParams {
 //
 // All values with no default are reset to their zero value.
 func (o *CreateLogEntryParams) SetDefaults() {
-	// no default values defined for this parameter
+	var (
+		asyncDefault = bool(false)
+	)
+
+	val := CreateLogEntryParams{
+		Async: &asyncDefault,
+	}
+
+	val.timeout = o.timeout
+	val.Context = o.Context
+	val.HTTPClient = o.HTTPClient
+	*o = val
 }
 
 // WithTimeout adds the timeout to the create log entry params
@@ -133,6 +151,17 @@ func (o *CreateLogEntryParams) SetHTTPClient(client *http.Client) {
 	o.HTTPClient = client
 }
 
+// WithAsync adds the async to the create log entry params
+func (o *CreateLogEntryParams) WithAsync(async *bool) *CreateLogEntryParams {
+	o.SetAsync(async)
+	return o
+}
+
+// SetAsync adds the async to the create log entry params
+func (o *CreateLogEntryParams) SetAsync(async *bool) {
+	o.Async = async
+}
+
 // WithProposedEntry adds the proposedEntry to the create log entry params
 func (o *CreateLogEntryParams) WithProposedEntry(proposedEntry models.ProposedEntry) *CreateLogEntryParams {
 	o.SetProposedEntry(proposedEntry)
@@ -151,6 +180,23 @@ func (o *CreateLogEntryParams) WriteToRequest(r runtime.ClientRequest, reg strfm
 		return err
 	}
 	var res []error
+
+	if o.Async != nil {
+
+		// query param async
+		var qrAsync bool
+
+		if o.Async != nil {
+			qrAsync = *o.Async
+		}
+		qAsync := swag.FormatBool(qrAsync)
+		if qAsync != "" {
+
+			if err := r.SetQueryParam("async", qAsync); err != nil {
+				return err
+			}
+		}
+	}
 	if err := r.SetBodyParam(o.ProposedEntry); err != nil {
 		return err
 	}
@@ -46,6 +46,12 @@ func (o *CreateLogEntryReader) ReadResponse(response runtime.ClientResponse, con
 			return nil, err
 		}
 		return result, nil
+	case 202:
+		result := NewCreateLogEntryAccepted()
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		return result, nil
 	case 400:
 		result := NewCreateLogEntryBadRequest()
 		if err := result.readResponse(response, consumer, o.formats); err != nil {
@@ -129,6 +135,67 @@ func (o *CreateLogEntryCreated) readResponse(response runtime.ClientResponse, co
 	return nil
 }
 
+// NewCreateLogEntryAccepted creates a CreateLogEntryAccepted with default headers values
+func NewCreateLogEntryAccepted() *CreateLogEntryAccepted {
+	return &CreateLogEntryAccepted{}
+}
+
+/* CreateLogEntryAccepted describes a response with status code 202, with default header values.
+
+The entry was queued for inclusion in the transparency log
+*/
+type CreateLogEntryAccepted struct {
+
+	/* UUID of log entry
+	 */
+	ETag string
+
+	/* URI location of the status of the log entry
+
+	   Format: uri
+	*/
+	Location strfmt.URI
+
+	Payload *models.QueuedLogEntry
+}
+
+func (o *CreateLogEntryAccepted) Error() string {
+	return fmt.Sprintf("[POST /api/v1/log/entries][%d] createLogEntryAccepted  %+v", 202, o.Payload)
+}
+func (o *CreateLogEntryAccepted) GetPayload() *models.QueuedLogEntry {
+	return o.Payload
+}
+
+func (o *CreateLogEntryAccepted) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	// hydrates response header ETag
+	hdrETag := response.GetHeader("ETag")
+
+	if hdrETag != "" {
+		o.ETag = hdrETag
+	}
+
+	// hydrates response header Location
+	hdrLocation := response.GetHeader("Location")
+
+	if hdrLocation != "" {
+		vallocation, err := formats.Parse("uri", hdrLocation)
+		if err != nil {
+			return errors.InvalidType("Location", "header", "strfmt.URI", hdrLocation)
+		}
+		o.Location = *(vallocation.(*strfmt.URI))
+	}
+
+	o.Payload = new(models.QueuedLogEntry)
+
+	// response payload
+	if err := consumer.Consume(response.Body(), o.Payload); err != nil && err != io.EOF {
+		return err
+	}
+
+	return nil
+}
+
 // NewCreateLogEntryBadRequest creates a CreateLogEntryBadRequest with default headers values
 func NewCreateLogEntryBadRequest() *CreateLogEntryBadRequest {
 	return &CreateLogEntryBadRequest{}
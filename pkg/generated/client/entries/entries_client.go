@@ -44,24 +44,70 @@ type ClientOption func(*runtime.ClientOperation)
 
 // ClientService is the interface for Client methods
 type ClientService interface {
-	CreateLogEntry(params *CreateLogEntryParams, opts ...ClientOption) (*CreateLogEntryCreated, error)
+	CreateLogEntries(params *CreateLogEntriesParams, opts ...ClientOption) (*CreateLogEntriesOK, error)
+
+	CreateLogEntry(params *CreateLogEntryParams, opts ...ClientOption) (*CreateLogEntryCreated, *CreateLogEntryAccepted, error)
 
 	GetLogEntryByIndex(params *GetLogEntryByIndexParams, opts ...ClientOption) (*GetLogEntryByIndexOK, error)
 
 	GetLogEntryByUUID(params *GetLogEntryByUUIDParams, opts ...ClientOption) (*GetLogEntryByUUIDOK, error)
 
+	GetLogEntryStatus(params *GetLogEntryStatusParams, opts ...ClientOption) (*GetLogEntryStatusOK, error)
+
 	SearchLogQuery(params *SearchLogQueryParams, opts ...ClientOption) (*SearchLogQueryOK, error)
 
+	ValidateLogEntry(params *ValidateLogEntryParams, opts ...ClientOption) (*ValidateLogEntryOK, error)
+
 	SetTransport(transport runtime.ClientTransport)
 }
 
+/*
+  CreateLogEntries creates multiple entries in the transparency log
+
+  Creates an entry in the transparency log for each proposed entry, waiting once for all of the entries to be included in the log. The result for each proposed entry is returned in the same position as the entry in the request. The request may also be sent as multipart/form-data, with the array of proposed entries in a part named 'entries' and items as file parts, as for createLogEntry.
+
+*/
+func (a *Client) CreateLogEntries(params *CreateLogEntriesParams, opts ...ClientOption) (*CreateLogEntriesOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewCreateLogEntriesParams()
+	}
+	op := &runtime.ClientOperation{
+		ID:                 "createLogEntries",
+		Method:             "POST",
+		PathPattern:        "/api/v1/log/entries/batch",
+		ProducesMediaTypes: []string{"application/json;q=1", "application/yaml"},
+		ConsumesMediaTypes: []string{"application/json", "application/yaml", "multipart/form-data"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &CreateLogEntriesReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	}
+	for _, opt := range opts {
+		opt(op)
+	}
+
+	result, err := a.transport.Submit(op)
+	if err != nil {
+		return nil, err
+	}
+	success, ok := result.(*CreateLogEntriesOK)
+	if ok {
+		return success, nil
+	}
+	// unexpected success response
+	unexpectedSuccess := result.(*CreateLogEntriesDefault)
+	return nil, runtime.NewAPIError("unexpected success response: content available as default response in error", unexpectedSuccess, unexpectedSuccess.Code())
+}
+
 /*
   CreateLogEntry creates an entry in the transparency log
 
-  Creates an entry in the transparency log for a detached signature, public key, and content. Items can be included in the request or fetched by the server when URLs are specified.
+  Creates an entry in the transparency log for a detached signature, public key, and content. Items can be included in the request or fetched by the server when URLs are specified. The request may also be sent as multipart/form-data, with the proposed entry in a part named 'entry' and items as file parts, which the entry refers to with URLs of the form 'attachment:<part name>'. If async is set, the server responds as soon as the entry has been queued for inclusion in the log, and the status of the entry can then be polled at the URL returned.
 
 */
-func (a *Client) CreateLogEntry(params *CreateLogEntryParams, opts ...ClientOption) (*CreateLogEntryCreated, error) {
+func (a *Client) CreateLogEntry(params *CreateLogEntryParams, opts ...ClientOption) (*CreateLogEntryCreated, *CreateLogEntryAccepted, error) {
 	// TODO: Validate the params before sending
 	if params == nil {
 		params = NewCreateLogEntryParams()
@@ -71,7 +117,7 @@ func (a *Client) CreateLogEntry(params *CreateLogEntryParams, opts ...ClientOpti
 		Method:             "POST",
 		PathPattern:        "/api/v1/log/entries",
 		ProducesMediaTypes: []string{"application/json;q=1", "application/yaml"},
-		ConsumesMediaTypes: []string{"application/json", "application/yaml"},
+		ConsumesMediaTypes: []string{"application/json", "application/yaml", "multipart/form-data"},
 		Schemes:            []string{"http"},
 		Params:             params,
 		Reader:             &CreateLogEntryReader{formats: a.formats},
@@ -84,15 +130,17 @@ func (a *Client) CreateLogEntry(params *CreateLogEntryParams, opts ...ClientOpti
 
 	result, err := a.transport.Submit(op)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	success, ok := result.(*CreateLogEntryCreated)
-	if ok {
-		return success, nil
+	switch value := result.(type) {
+	case *CreateLogEntryCreated:
+		return value, nil, nil
+	case *CreateLogEntryAccepted:
+		return nil, value, nil
 	}
 	// unexpected success response
 	unexpectedSuccess := result.(*CreateLogEntryDefault)
-	return nil, runtime.NewAPIError("unexpected success response: content available as default response in error", unexpectedSuccess, unexpectedSuccess.Code())
+	return nil, nil, runtime.NewAPIError("unexpected success response: content available as default response in error", unexpectedSuccess, unexpectedSuccess.Code())
 }
 
 /*
@@ -171,8 +219,51 @@ func (a *Client) GetLogEntryByUUID(params *GetLogEntryByUUIDParams, opts ...Clie
 	return nil, runtime.NewAPIError("unexpected success response: content available as default response in error", unexpectedSuccess, unexpectedSuccess.Code())
 }
 
+/*
+  GetLogEntryStatus gets the status of an entry that was queued for inclusion in the transparency log
+
+  Returns whether the entry is still queued or has been included in the transparency log; once it has been included, its index and an inclusion proof are also returned. The status of a queued entry is best-effort: an entry queued by another instance of the server, or before the server restarted, may not be found until it has been included, unless the server records queued entries in Redis
+
+*/
+func (a *Client) GetLogEntryStatus(params *GetLogEntryStatusParams, opts ...ClientOption) (*GetLogEntryStatusOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewGetLogEntryStatusParams()
+	}
+	op := &runtime.ClientOperation{
+		ID:                 "getLogEntryStatus",
+		Method:             "GET",
+		PathPattern:        "/api/v1/log/entries/{entryUUID}/status",
+		ProducesMediaTypes: []string{"application/json;q=1", "application/yaml"},
+		ConsumesMediaTypes: []string{"application/json", "application/yaml"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &GetLogEntryStatusReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	}
+	for _, opt := range opts {
+		opt(op)
+	}
+
+	result, err := a.transport.Submit(op)
+	if err != nil {
+		return nil, err
+	}
+	success, ok := result.(*GetLogEntryStatusOK)
+	if ok {
+		return success, nil
+	}
+	// unexpected success response
+	unexpectedSuccess := result.(*GetLogEntryStatusDefault)
+	return nil, runtime.NewAPIError("unexpected success response: content available as default response in error", unexpectedSuccess, unexpectedSuccess.Code())
+}
+
 /*
   SearchLogQuery searches transparency log for one or more log entries
+
+  The request may also be sent as multipart/form-data, with the query in a part named 'query' and items of proposed entries as file parts, which the entries refer to with URLs of the form 'attachment:<part name>'.
+
 */
 func (a *Client) SearchLogQuery(params *SearchLogQueryParams, opts ...ClientOption) (*SearchLogQueryOK, error) {
 	// TODO: Validate the params before sending
@@ -184,7 +275,7 @@ func (a *Client) SearchLogQuery(params *SearchLogQueryParams, opts ...ClientOpti
 		Method:             "POST",
 		PathPattern:        "/api/v1/log/entries/retrieve",
 		ProducesMediaTypes: []string{"application/json;q=1", "application/yaml"},
-		ConsumesMediaTypes: []string{"application/json", "application/yaml"},
+		ConsumesMediaTypes: []string{"application/json", "application/yaml", "multipart/form-data"},
 		Schemes:            []string{"http"},
 		Params:             params,
 		Reader:             &SearchLogQueryReader{formats: a.formats},
@@ -208,6 +299,46 @@ func (a *Client) SearchLogQuery(params *SearchLogQueryParams, opts ...ClientOpti
 	return nil, runtime.NewAPIError("unexpected success response: content available as default response in error", unexpectedSuccess, unexpectedSuccess.Code())
 }
 
+/*
+  ValidateLogEntry checks whether an entry would be accepted into the transparency log
+
+  Performs every check made when an entry is created, without adding the entry to the transparency log. Returns the canonicalized entry, the UUID and index keys it would be assigned, and the reasons it would be rejected (if any). The request may also be sent as multipart/form-data, as for createLogEntry.
+
+*/
+func (a *Client) ValidateLogEntry(params *ValidateLogEntryParams, opts ...ClientOption) (*ValidateLogEntryOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewValidateLogEntryParams()
+	}
+	op := &runtime.ClientOperation{
+		ID:                 "validateLogEntry",
+		Method:             "POST",
+		PathPattern:        "/api/v1/log/entries/validate",
+		ProducesMediaTypes: []string{"application/json;q=1", "application/yaml"},
+		ConsumesMediaTypes: []string{"application/json", "application/yaml", "multipart/form-data"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &ValidateLogEntryReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	}
+	for _, opt := range opts {
+		opt(op)
+	}
+
+	result, err := a.transport.Submit(op)
+	if err != nil {
+		return nil, err
+	}
+	success, ok := result.(*ValidateLogEntryOK)
+	if ok {
+		return success, nil
+	}
+	// unexpected success response
+	unexpectedSuccess := result.(*ValidateLogEntryDefault)
+	return nil, runtime.NewAPIError("unexpected success response: content available as default response in error", unexpectedSuccess, unexpectedSuccess.Code())
+}
+
 // SetTransport changes the transport on the client
 func (a *Client) SetTransport(transport runtime.ClientTransport) {
 	a.transport = transport
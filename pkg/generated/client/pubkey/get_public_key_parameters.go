@@ -30,6 +30,7 @@ import (
 	"github.com/go-openapi/runtime"
 	cr "github.com/go-openapi/runtime/client"
 	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
 )
 
 // NewGetPublicKeyParams creates a new GetPublicKeyParams object,
@@ -74,6 +75,20 @@ func NewGetPublicKeyParamsWithHTTPClient(client *http.Client) *GetPublicKeyParam
    Typically these are written to a http.Request.
 */
 type GetPublicKeyParams struct {
+
+	/* All.
+
+	   Return every key that the log signs or has signed tree heads with, as a series of PEM blocks whose Key-ID header is the key hint of the tree heads signed by the key, and whose Not-Before and Not-After headers (if present) bound the period in which the log signed with it
+
+	*/
+	All *bool
+
+	/* TreeID.
+
+	   The tree ID of the shard whose public key is requested (defaults to the active shard)
+	*/
+	TreeID *string
+
 	timeout    time.Duration
 	Context    context.Context
 	HTTPClient *http.Client
@@ -91,7 +106,18 @@ func (o *GetPublicKeyParams) WithDefaults() *GetPublicKeyParams {
 //
 // All values with no default are reset to their zero value.
 func (o *GetPublicKeyParams) SetDefaults() {
-	// no default values defined for this parameter
+	var (
+		allDefault = bool(false)
+	)
+
+	val := GetPublicKeyParams{
+		All: &allDefault,
+	}
+
+	val.timeout = o.timeout
+	val.Context = o.Context
+	val.HTTPClient = o.HTTPClient
+	*o = val
 }
 
 // WithTimeout adds the timeout to the get public key params
@@ -127,6 +153,28 @@ func (o *GetPublicKeyParams) SetHTTPClient(client *http.Client) {
 	o.HTTPClient = client
 }
 
+// WithAll adds the all to the get public key params
+func (o *GetPublicKeyParams) WithAll(all *bool) *GetPublicKeyParams {
+	o.SetAll(all)
+	return o
+}
+
+// SetAll adds the all to the get public key params
+func (o *GetPublicKeyParams) SetAll(all *bool) {
+	o.All = all
+}
+
+// WithTreeID adds the treeID to the get public key params
+func (o *GetPublicKeyParams) WithTreeID(treeID *string) *GetPublicKeyParams {
+	o.SetTreeID(treeID)
+	return o
+}
+
+// SetTreeID adds the treeId to the get public key params
+func (o *GetPublicKeyParams) SetTreeID(treeID *string) {
+	o.TreeID = treeID
+}
+
 // WriteToRequest writes these params to a swagger request
 func (o *GetPublicKeyParams) WriteToRequest(r runtime.ClientRequest, reg strfmt.Registry) error {
 
@@ -135,6 +183,40 @@ func (o *GetPublicKeyParams) WriteToRequest(r runtime.ClientRequest, reg strfmt.
 	}
 	var res []error
 
+	if o.All != nil {
+
+		// query param all
+		var qrAll bool
+
+		if o.All != nil {
+			qrAll = *o.All
+		}
+		qAll := swag.FormatBool(qrAll)
+		if qAll != "" {
+
+			if err := r.SetQueryParam("all", qAll); err != nil {
+				return err
+			}
+		}
+	}
+
+	if o.TreeID != nil {
+
+		// query param treeID
+		var qrTreeID string
+
+		if o.TreeID != nil {
+			qrTreeID = *o.TreeID
+		}
+		qTreeID := qrTreeID
+		if qTreeID != "" {
+
+			if err := r.SetQueryParam("treeID", qTreeID); err != nil {
+				return err
+			}
+		}
+	}
+
 	if len(res) > 0 {
 		return errors.CompositeValidationError(res...)
 	}
@@ -44,6 +44,8 @@ type ClientOption func(*runtime.ClientOperation)
 
 // ClientService is the interface for Client methods
 type ClientService interface {
+	AddCosignature(params *AddCosignatureParams, opts ...ClientOption) (*AddCosignatureCreated, error)
+
 	GetLogInfo(params *GetLogInfoParams, opts ...ClientOption) (*GetLogInfoOK, error)
 
 	GetLogProof(params *GetLogProofParams, opts ...ClientOption) (*GetLogProofOK, error)
@@ -51,6 +53,45 @@ type ClientService interface {
 	SetTransport(transport runtime.ClientTransport)
 }
 
+/*
+  AddCosignature adds a witness cosignature of a signed tree head of the transparency log
+
+  Accepts the signature of a witness over a tree head of the transparency log, once the witness has verified that the tree head is consistent with those it has seen before. The cosignature is served alongside the tree head by getLogInfo.
+*/
+func (a *Client) AddCosignature(params *AddCosignatureParams, opts ...ClientOption) (*AddCosignatureCreated, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewAddCosignatureParams()
+	}
+	op := &runtime.ClientOperation{
+		ID:                 "addCosignature",
+		Method:             "POST",
+		PathPattern:        "/api/v1/log/cosignatures",
+		ProducesMediaTypes: []string{"application/json;q=1", "application/yaml"},
+		ConsumesMediaTypes: []string{"application/json", "application/yaml"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &AddCosignatureReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	}
+	for _, opt := range opts {
+		opt(op)
+	}
+
+	result, err := a.transport.Submit(op)
+	if err != nil {
+		return nil, err
+	}
+	success, ok := result.(*AddCosignatureCreated)
+	if ok {
+		return success, nil
+	}
+	// unexpected success response
+	unexpectedSuccess := result.(*AddCosignatureDefault)
+	return nil, runtime.NewAPIError("unexpected success response: content available as default response in error", unexpectedSuccess, unexpectedSuccess.Code())
+}
+
 /*
   GetLogInfo gets information about the current state of the transparency log
 
@@ -23,6 +23,7 @@ package models
 
 import (
 	"context"
+	"strconv"
 
 	"github.com/go-openapi/errors"
 	"github.com/go-openapi/strfmt"
@@ -35,6 +36,12 @@ import (
 // swagger:model LogInfo
 type LogInfo struct {
 
+	// Cosignatures of the signed tree head, or of earlier tree heads of the active shard, by witnesses of the log
+	Cosignatures []*Cosignature `json:"cosignatures"`
+
+	// The frozen shards of the log, which hold entries added before the active shard
+	InactiveShards []*InactiveShardLogInfo `json:"inactiveShards"`
+
 	// The current hash value stored at the root of the merkle tree
 	// Required: true
 	// Pattern: ^[0-9a-fA-F]{64}$
@@ -44,6 +51,17 @@ type LogInfo struct {
 	// Required: true
 	SignedTreeHead *LogInfoSignedTreeHead `json:"signedTreeHead"`
 
+	// The index in the log of the first entry of the active shard; indexes between the end of the frozen shards and this index are unused
+	// Minimum: 0
+	StartIndex int64 `json:"startIndex,omitempty"`
+
+	// The signature formats of public keys and signatures that the server accepts
+	SupportedFormats []string `json:"supportedFormats"`
+
+	// The tree ID of the active shard, which new entries are added to
+	// Pattern: ^[0-9]+$
+	TreeID string `json:"treeID,omitempty"`
+
 	// The current number of nodes in the merkle tree
 	// Required: true
 	// Minimum: 1
@@ -54,6 +72,14 @@ type LogInfo struct {
 func (m *LogInfo) Validate(formats strfmt.Registry) error {
 	var res []error
 
+	if err := m.validateCosignatures(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.validateInactiveShards(formats); err != nil {
+		res = append(res, err)
+	}
+
 	if err := m.validateRootHash(formats); err != nil {
 		res = append(res, err)
 	}
@@ -62,6 +88,14 @@ func (m *LogInfo) Validate(formats strfmt.Registry) error {
 		res = append(res, err)
 	}
 
+	if err := m.validateStartIndex(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.validateTreeID(formats); err != nil {
+		res = append(res, err)
+	}
+
 	if err := m.validateTreeSize(formats); err != nil {
 		res = append(res, err)
 	}
@@ -72,6 +106,54 @@ func (m *LogInfo) Validate(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *LogInfo) validateCosignatures(formats strfmt.Registry) error {
+	if swag.IsZero(m.Cosignatures) { // not required
+		return nil
+	}
+
+	for i := 0; i < len(m.Cosignatures); i++ {
+		if swag.IsZero(m.Cosignatures[i]) { // not required
+			continue
+		}
+
+		if m.Cosignatures[i] != nil {
+			if err := m.Cosignatures[i].Validate(formats); err != nil {
+				if ve, ok := err.(*errors.Validation); ok {
+					return ve.ValidateName("cosignatures" + "." + strconv.Itoa(i))
+				}
+				return err
+			}
+		}
+
+	}
+
+	return nil
+}
+
+func (m *LogInfo) validateInactiveShards(formats strfmt.Registry) error {
+	if swag.IsZero(m.InactiveShards) { // not required
+		return nil
+	}
+
+	for i := 0; i < len(m.InactiveShards); i++ {
+		if swag.IsZero(m.InactiveShards[i]) { // not required
+			continue
+		}
+
+		if m.InactiveShards[i] != nil {
+			if err := m.InactiveShards[i].Validate(formats); err != nil {
+				if ve, ok := err.(*errors.Validation); ok {
+					return ve.ValidateName("inactiveShards" + "." + strconv.Itoa(i))
+				}
+				return err
+			}
+		}
+
+	}
+
+	return nil
+}
+
 func (m *LogInfo) validateRootHash(formats strfmt.Registry) error {
 
 	if err := validate.Required("rootHash", "body", m.RootHash); err != nil {
@@ -103,6 +185,30 @@ func (m *LogInfo) validateSignedTreeHead(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *LogInfo) validateStartIndex(formats strfmt.Registry) error {
+	if swag.IsZero(m.StartIndex) { // not required
+		return nil
+	}
+
+	if err := validate.MinimumInt("startIndex", "body", m.StartIndex, 0, false); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (m *LogInfo) validateTreeID(formats strfmt.Registry) error {
+	if swag.IsZero(m.TreeID) { // not required
+		return nil
+	}
+
+	if err := validate.Pattern("treeID", "body", m.TreeID, `^[0-9]+$`); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (m *LogInfo) validateTreeSize(formats strfmt.Registry) error {
 
 	if err := validate.Required("treeSize", "body", m.TreeSize); err != nil {
@@ -120,6 +226,14 @@ func (m *LogInfo) validateTreeSize(formats strfmt.Registry) error {
 func (m *LogInfo) ContextValidate(ctx context.Context, formats strfmt.Registry) error {
 	var res []error
 
+	if err := m.contextValidateCosignatures(ctx, formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.contextValidateInactiveShards(ctx, formats); err != nil {
+		res = append(res, err)
+	}
+
 	if err := m.contextValidateSignedTreeHead(ctx, formats); err != nil {
 		res = append(res, err)
 	}
@@ -130,6 +244,42 @@ func (m *LogInfo) ContextValidate(ctx context.Context, formats strfmt.Registry)
 	return nil
 }
 
+func (m *LogInfo) contextValidateCosignatures(ctx context.Context, formats strfmt.Registry) error {
+
+	for i := 0; i < len(m.Cosignatures); i++ {
+
+		if m.Cosignatures[i] != nil {
+			if err := m.Cosignatures[i].ContextValidate(ctx, formats); err != nil {
+				if ve, ok := err.(*errors.Validation); ok {
+					return ve.ValidateName("cosignatures" + "." + strconv.Itoa(i))
+				}
+				return err
+			}
+		}
+
+	}
+
+	return nil
+}
+
+func (m *LogInfo) contextValidateInactiveShards(ctx context.Context, formats strfmt.Registry) error {
+
+	for i := 0; i < len(m.InactiveShards); i++ {
+
+		if m.InactiveShards[i] != nil {
+			if err := m.InactiveShards[i].ContextValidate(ctx, formats); err != nil {
+				if ve, ok := err.(*errors.Validation); ok {
+					return ve.ValidateName("inactiveShards" + "." + strconv.Itoa(i))
+				}
+				return err
+			}
+		}
+
+	}
+
+	return nil
+}
+
 func (m *LogInfo) contextValidateSignedTreeHead(ctx context.Context, formats strfmt.Registry) error {
 
 	if m.SignedTreeHead != nil {
@@ -167,7 +317,7 @@ func (m *LogInfo) UnmarshalBinary(b []byte) error {
 // swagger:model LogInfoSignedTreeHead
 type LogInfoSignedTreeHead struct {
 
-	// Key hint
+	// The SHA-256 digest of the DER encoded public key that signed the log root
 	// Required: true
 	// Format: byte
 	KeyHint *strfmt.Base64 `json:"keyHint"`
@@ -388,12 +388,14 @@ func (m *RekordV001SchemaDataHash) UnmarshalBinary(b []byte) error {
 // swagger:model RekordV001SchemaSignature
 type RekordV001SchemaSignature struct {
 
+	// Specifies the hash function and padding scheme used to generate the signature, for formats that support more than one; if omitted, it is inferred when the signature is verified
+	Algorithm string `json:"algorithm,omitempty"`
+
 	// Specifies the content of the signature inline within the document
 	// Format: byte
 	Content strfmt.Base64 `json:"content,omitempty"`
 
-	// Specifies the format of the signature
-	// Enum: [pgp minisign x509 ssh]
+	// Specifies the format of the signature; one of the supportedFormats of the log info
 	Format string `json:"format,omitempty"`
 
 	// public key
@@ -408,10 +410,6 @@ type RekordV001SchemaSignature struct {
 func (m *RekordV001SchemaSignature) Validate(formats strfmt.Registry) error {
 	var res []error
 
-	if err := m.validateFormat(formats); err != nil {
-		res = append(res, err)
-	}
-
 	if err := m.validatePublicKey(formats); err != nil {
 		res = append(res, err)
 	}
@@ -426,54 +424,6 @@ func (m *RekordV001SchemaSignature) Validate(formats strfmt.Registry) error {
 	return nil
 }
 
-var rekordV001SchemaSignatureTypeFormatPropEnum []interface{}
-
-func init() {
-	var res []string
-	if err := json.Unmarshal([]byte(`["pgp","minisign","x509","ssh"]`), &res); err != nil {
-		panic(err)
-	}
-	for _, v := range res {
-		rekordV001SchemaSignatureTypeFormatPropEnum = append(rekordV001SchemaSignatureTypeFormatPropEnum, v)
-	}
-}
-
-const (
-
-	// RekordV001SchemaSignatureFormatPgp captures enum value "pgp"
-	RekordV001SchemaSignatureFormatPgp string = "pgp"
-
-	// RekordV001SchemaSignatureFormatMinisign captures enum value "minisign"
-	RekordV001SchemaSignatureFormatMinisign string = "minisign"
-
-	// RekordV001SchemaSignatureFormatX509 captures enum value "x509"
-	RekordV001SchemaSignatureFormatX509 string = "x509"
-
-	// RekordV001SchemaSignatureFormatSSH captures enum value "ssh"
-	RekordV001SchemaSignatureFormatSSH string = "ssh"
-)
-
-// prop value enum
-func (m *RekordV001SchemaSignature) validateFormatEnum(path, location string, value string) error {
-	if err := validate.EnumCase(path, location, value, rekordV001SchemaSignatureTypeFormatPropEnum, true); err != nil {
-		return err
-	}
-	return nil
-}
-
-func (m *RekordV001SchemaSignature) validateFormat(formats strfmt.Registry) error {
-	if swag.IsZero(m.Format) { // not required
-		return nil
-	}
-
-	// value enum
-	if err := m.validateFormatEnum("signature"+"."+"format", "body", m.Format); err != nil {
-		return err
-	}
-
-	return nil
-}
-
 func (m *RekordV001SchemaSignature) validatePublicKey(formats strfmt.Registry) error {
 	if swag.IsZero(m.PublicKey) { // not required
 		return nil
@@ -23,7 +23,6 @@ package models
 
 import (
 	"context"
-	"encoding/json"
 
 	"github.com/go-openapi/errors"
 	"github.com/go-openapi/strfmt"
@@ -146,9 +145,8 @@ type SearchIndexPublicKey struct {
 	// Format: byte
 	Content strfmt.Base64 `json:"content,omitempty"`
 
-	// format
+	// The signature format of the public key; one of the supportedFormats of the log info
 	// Required: true
-	// Enum: [pgp x509 minisign ssh]
 	Format *string `json:"format"`
 
 	// url
@@ -174,52 +172,12 @@ func (m *SearchIndexPublicKey) Validate(formats strfmt.Registry) error {
 	return nil
 }
 
-var searchIndexPublicKeyTypeFormatPropEnum []interface{}
-
-func init() {
-	var res []string
-	if err := json.Unmarshal([]byte(`["pgp","x509","minisign","ssh"]`), &res); err != nil {
-		panic(err)
-	}
-	for _, v := range res {
-		searchIndexPublicKeyTypeFormatPropEnum = append(searchIndexPublicKeyTypeFormatPropEnum, v)
-	}
-}
-
-const (
-
-	// SearchIndexPublicKeyFormatPgp captures enum value "pgp"
-	SearchIndexPublicKeyFormatPgp string = "pgp"
-
-	// SearchIndexPublicKeyFormatX509 captures enum value "x509"
-	SearchIndexPublicKeyFormatX509 string = "x509"
-
-	// SearchIndexPublicKeyFormatMinisign captures enum value "minisign"
-	SearchIndexPublicKeyFormatMinisign string = "minisign"
-
-	// SearchIndexPublicKeyFormatSSH captures enum value "ssh"
-	SearchIndexPublicKeyFormatSSH string = "ssh"
-)
-
-// prop value enum
-func (m *SearchIndexPublicKey) validateFormatEnum(path, location string, value string) error {
-	if err := validate.EnumCase(path, location, value, searchIndexPublicKeyTypeFormatPropEnum, true); err != nil {
-		return err
-	}
-	return nil
-}
-
 func (m *SearchIndexPublicKey) validateFormat(formats strfmt.Registry) error {
 
 	if err := validate.Required("publicKey"+"."+"format", "body", m.Format); err != nil {
 		return err
 	}
 
-	// value enum
-	if err := m.validateFormatEnum("publicKey"+"."+"format", "body", *m.Format); err != nil {
-		return err
-	}
-
 	return nil
 }
 
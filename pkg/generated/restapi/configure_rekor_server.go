@@ -19,6 +19,7 @@ package restapi
 
 import (
 	"crypto/tls"
+	"fmt"
 	"net/http"
 	"strconv"
 	"time"
@@ -74,11 +75,15 @@ func configureAPI(api *operations.RekorServerAPI) http.Handler {
 	api.EntriesCreateLogEntryHandler = entries.CreateLogEntryHandlerFunc(pkgapi.CreateLogEntryHandler)
 	api.EntriesGetLogEntryByIndexHandler = entries.GetLogEntryByIndexHandlerFunc(pkgapi.GetLogEntryByIndexHandler)
 	api.EntriesGetLogEntryByUUIDHandler = entries.GetLogEntryByUUIDHandlerFunc(pkgapi.GetLogEntryByUUIDHandler)
+	api.EntriesGetLogEntryStatusHandler = entries.GetLogEntryStatusHandlerFunc(pkgapi.GetLogEntryStatusHandler)
 	api.EntriesSearchLogQueryHandler = entries.SearchLogQueryHandlerFunc(pkgapi.SearchLogQueryHandler)
+	api.EntriesCreateLogEntriesHandler = entries.CreateLogEntriesHandlerFunc(pkgapi.CreateLogEntriesHandler)
+	api.EntriesValidateLogEntryHandler = entries.ValidateLogEntryHandlerFunc(pkgapi.ValidateLogEntryHandler)
 
 	api.PubkeyGetPublicKeyHandler = pubkey.GetPublicKeyHandlerFunc(pkgapi.GetPublicKeyHandler)
 
 	api.TlogGetLogInfoHandler = tlog.GetLogInfoHandlerFunc(pkgapi.GetLogInfoHandler)
+	api.TlogAddCosignatureHandler = tlog.AddCosignatureHandlerFunc(pkgapi.AddCosignatureHandler)
 	api.TlogGetLogProofHandler = tlog.GetLogProofHandlerFunc(pkgapi.GetLogProofHandler)
 
 	if viper.GetBool("enable_retrieve_api") {
@@ -96,9 +101,16 @@ func configureAPI(api *operations.RekorServerAPI) http.Handler {
 	api.AddMiddlewareFor("GET", "/api/v1/log/proof", middleware.NoCache)
 	api.AddMiddlewareFor("GET", "/api/v1/log/entries", middleware.NoCache)
 	api.AddMiddlewareFor("GET", "/api/v1/log/entries/{entryUUID}", middleware.NoCache)
+	api.AddMiddlewareFor("GET", "/api/v1/log/entries/{entryUUID}/status", middleware.NoCache)
 
-	// cache forever
-	api.AddMiddlewareFor("GET", "/api/v1/log/publicKey", cacheForever)
+	// cached for a limited time, as the key of the log may be rotated
+	api.AddMiddlewareFor("GET", "/api/v1/log/publicKey", cacheFor(10*time.Minute))
+
+	// accept artifacts as multipart/form-data file parts in place of base64 encoded content
+	api.AddMiddlewareFor("POST", "/api/v1/log/entries", pkgapi.SpoolMultipart("entry"))
+	api.AddMiddlewareFor("POST", "/api/v1/log/entries/retrieve", pkgapi.SpoolMultipart("query"))
+	api.AddMiddlewareFor("POST", "/api/v1/log/entries/validate", pkgapi.SpoolMultipart("entry"))
+	api.AddMiddlewareFor("POST", "/api/v1/log/entries/batch", pkgapi.SpoolMultipart("entries"))
 
 	return setupGlobalMiddleware(api.Serve(setupMiddlewares))
 }
@@ -141,6 +153,9 @@ func setupGlobalMiddleware(handler http.Handler) http.Handler {
 	handleCORS := cors.Default().Handler
 	returnHandler = handleCORS(returnHandler)
 
+	// requests to /api/v1/logs/{logName}/... are served by the handlers of the default log
+	returnHandler = pkgapi.NamedLogs(returnHandler)
+
 	returnHandler = wrapMetrics(returnHandler)
 
 	return middleware.RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -171,16 +186,19 @@ func wrapMetrics(handler http.Handler) http.Handler {
 	})
 }
 
-func cacheForever(handler http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		ww := negroni.NewResponseWriter(w)
-		ww.Before(func(w negroni.ResponseWriter) {
-			if w.Status() >= 200 && w.Status() <= 299 {
-				w.Header().Set("Cache-Control", "s-maxage=31536000, max-age=31536000, immutable")
-			}
+func cacheFor(maxAge time.Duration) func(http.Handler) http.Handler {
+	return func(handler http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ww := negroni.NewResponseWriter(w)
+			ww.Before(func(w negroni.ResponseWriter) {
+				if w.Status() >= 200 && w.Status() <= 299 {
+					seconds := int64(maxAge.Seconds())
+					w.Header().Set("Cache-Control", fmt.Sprintf("s-maxage=%d, max-age=%d", seconds, seconds))
+				}
+			})
+			handler.ServeHTTP(ww, r)
 		})
-		handler.ServeHTTP(ww, r)
-	})
+	}
 }
 
 func logAndServeError(w http.ResponseWriter, r *http.Request, err error) {
@@ -78,7 +78,7 @@ func init() {
               "items": {
                 "description": "Entry UUID in transparency log",
                 "type": "string",
-                "pattern": "^[0-9a-fA-F]{64}$"
+                "pattern": "^([0-9a-fA-F]{64}|[0-9a-fA-F]{80})$"
               }
             }
           },
@@ -112,6 +112,40 @@ func init() {
         }
       }
     },
+    "/api/v1/log/cosignatures": {
+      "post": {
+        "description": "Accepts the signature of a witness over a tree head of the transparency log, once the witness has verified that the tree head is consistent with those it has seen before. The cosignature is served alongside the tree head by getLogInfo.\n",
+        "tags": [
+          "tlog"
+        ],
+        "summary": "Adds a witness cosignature of a signed tree head of the transparency log",
+        "operationId": "addCosignature",
+        "parameters": [
+          {
+            "name": "cosignature",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/Cosignature"
+            }
+          }
+        ],
+        "responses": {
+          "201": {
+            "description": "Returns the cosignature accepted by the transparency log",
+            "schema": {
+              "$ref": "#/definitions/Cosignature"
+            }
+          },
+          "400": {
+            "$ref": "#/responses/BadContent"
+          },
+          "default": {
+            "$ref": "#/responses/InternalServerError"
+          }
+        }
+      }
+    },
     "/api/v1/log/entries": {
       "get": {
         "tags": [
@@ -144,7 +178,12 @@ func init() {
         }
       },
       "post": {
-        "description": "Creates an entry in the transparency log for a detached signature, public key, and content. Items can be included in the request or fetched by the server when URLs are specified.\n",
+        "description": "Creates an entry in the transparency log for a detached signature, public key, and content. Items can be included in the request or fetched by the server when URLs are specified. The request may also be sent as multipart/form-data, with the proposed entry in a part named 'entry' and items as file parts, which the entry refers to with URLs of the form 'attachment:\u003cpart name\u003e'. If async is set, the server responds as soon as the entry has been queued for inclusion in the log, and the status of the entry can then be polled at the URL returned.\n",
+        "consumes": [
+          "application/json",
+          "application/yaml",
+          "multipart/form-data"
+        ],
         "tags": [
           "entries"
         ],
@@ -158,6 +197,13 @@ func init() {
             "schema": {
               "$ref": "#/definitions/ProposedEntry"
             }
+          },
+          {
+            "type": "boolean",
+            "default": false,
+            "description": "respond once the entry has been queued, rather than waiting for it to be included in the log",
+            "name": "async",
+            "in": "query"
           }
         ],
         "responses": {
@@ -178,6 +224,23 @@ func init() {
               }
             }
           },
+          "202": {
+            "description": "The entry was queued for inclusion in the transparency log",
+            "schema": {
+              "$ref": "#/definitions/QueuedLogEntry"
+            },
+            "headers": {
+              "ETag": {
+                "type": "string",
+                "description": "UUID of log entry"
+              },
+              "Location": {
+                "type": "string",
+                "format": "uri",
+                "description": "URI location of the status of the log entry"
+              }
+            }
+          },
           "400": {
             "$ref": "#/responses/BadContent"
           },
@@ -187,11 +250,89 @@ func init() {
           "default": {
             "$ref": "#/responses/InternalServerError"
           }
-        }
+        },
+        "x-multipart-form-data": [
+          {
+            "description": "The proposed entry as JSON, in place of the request body",
+            "name": "entry",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "description": "Any number of file parts, each of which is referred to by the URL 'attachment:\u003cpart name\u003e'",
+            "name": "\u003cpart name\u003e",
+            "type": "file"
+          }
+        ]
+      }
+    },
+    "/api/v1/log/entries/batch": {
+      "post": {
+        "description": "Creates an entry in the transparency log for each proposed entry, waiting once for all of the entries to be included in the log. The result for each proposed entry is returned in the same position as the entry in the request. The request may also be sent as multipart/form-data, with the array of proposed entries in a part named 'entries' and items as file parts, as for createLogEntry.\n",
+        "consumes": [
+          "application/json",
+          "application/yaml",
+          "multipart/form-data"
+        ],
+        "tags": [
+          "entries"
+        ],
+        "summary": "Creates multiple entries in the transparency log",
+        "operationId": "createLogEntries",
+        "parameters": [
+          {
+            "name": "proposedEntries",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "type": "array",
+              "minItems": 1,
+              "items": {
+                "$ref": "#/definitions/ProposedEntry"
+              }
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Returns the result of creating each entry",
+            "schema": {
+              "type": "array",
+              "items": {
+                "$ref": "#/definitions/BatchEntryResult"
+              }
+            }
+          },
+          "400": {
+            "$ref": "#/responses/BadContent"
+          },
+          "default": {
+            "$ref": "#/responses/InternalServerError"
+          }
+        },
+        "x-multipart-form-data": [
+          {
+            "description": "The array of proposed entries as JSON, in place of the request body",
+            "name": "entries",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "description": "Any number of file parts, each of which is referred to by the URL 'attachment:\u003cpart name\u003e'",
+            "name": "\u003cpart name\u003e",
+            "type": "file"
+          }
+        ]
       }
     },
     "/api/v1/log/entries/retrieve": {
       "post": {
+        "description": "The request may also be sent as multipart/form-data, with the query in a part named 'query' and items of proposed entries as file parts, which the entries refer to with URLs of the form 'attachment:\u003cpart name\u003e'.\n",
+        "consumes": [
+          "application/json",
+          "application/yaml",
+          "multipart/form-data"
+        ],
         "tags": [
           "entries"
         ],
@@ -223,7 +364,72 @@ func init() {
           "default": {
             "$ref": "#/responses/InternalServerError"
           }
-        }
+        },
+        "x-multipart-form-data": [
+          {
+            "description": "The search query as JSON, in place of the request body",
+            "name": "query",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "description": "Any number of file parts, each of which is referred to by the URL 'attachment:\u003cpart name\u003e'",
+            "name": "\u003cpart name\u003e",
+            "type": "file"
+          }
+        ]
+      }
+    },
+    "/api/v1/log/entries/validate": {
+      "post": {
+        "description": "Performs every check made when an entry is created, without adding the entry to the transparency log. Returns the canonicalized entry, the UUID and index keys it would be assigned, and the reasons it would be rejected (if any). The request may also be sent as multipart/form-data, as for createLogEntry.\n",
+        "consumes": [
+          "application/json",
+          "application/yaml",
+          "multipart/form-data"
+        ],
+        "tags": [
+          "entries"
+        ],
+        "summary": "Checks whether an entry would be accepted into the transparency log",
+        "operationId": "validateLogEntry",
+        "parameters": [
+          {
+            "name": "proposedEntry",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/ProposedEntry"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "The result of checking the entry; an entry that would be rejected is reported with valid set to false",
+            "schema": {
+              "$ref": "#/definitions/ValidationResult"
+            }
+          },
+          "400": {
+            "$ref": "#/responses/BadContent"
+          },
+          "default": {
+            "$ref": "#/responses/InternalServerError"
+          }
+        },
+        "x-multipart-form-data": [
+          {
+            "description": "The proposed entry as JSON, in place of the request body",
+            "name": "entry",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "description": "Any number of file parts, each of which is referred to by the URL 'attachment:\u003cpart name\u003e'",
+            "name": "\u003cpart name\u003e",
+            "type": "file"
+          }
+        ]
       }
     },
     "/api/v1/log/entries/{entryUUID}": {
@@ -236,7 +442,7 @@ func init() {
         "operationId": "getLogEntryByUUID",
         "parameters": [
           {
-            "pattern": "^[0-9a-fA-F]{64}$",
+            "pattern": "^([0-9a-fA-F]{64}|[0-9a-fA-F]{80})$",
             "type": "string",
             "description": "the UUID of the entry for which the inclusion proof information should be returned",
             "name": "entryUUID",
@@ -260,6 +466,40 @@ func init() {
         }
       }
     },
+    "/api/v1/log/entries/{entryUUID}/status": {
+      "get": {
+        "description": "Returns whether the entry is still queued or has been included in the transparency log; once it has been included, its index and an inclusion proof are also returned. The status of a queued entry is best-effort: an entry queued by another instance of the server, or before the server restarted, may not be found until it has been included, unless the server records queued entries in Redis\n",
+        "tags": [
+          "entries"
+        ],
+        "summary": "Get the status of an entry that was queued for inclusion in the transparency log",
+        "operationId": "getLogEntryStatus",
+        "parameters": [
+          {
+            "pattern": "^([0-9a-fA-F]{64}|[0-9a-fA-F]{80})$",
+            "type": "string",
+            "description": "the UUID of the entry",
+            "name": "entryUUID",
+            "in": "path",
+            "required": true
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "The status of the entry",
+            "schema": {
+              "$ref": "#/definitions/LogEntryStatus"
+            }
+          },
+          "404": {
+            "$ref": "#/responses/NotFound"
+          },
+          "default": {
+            "$ref": "#/responses/InternalServerError"
+          }
+        }
+      }
+    },
     "/api/v1/log/proof": {
       "get": {
         "description": "Returns a list of hashes for specified tree sizes that can be used to confirm the consistency of the transparency log",
@@ -284,6 +524,13 @@ func init() {
             "name": "lastSize",
             "in": "query",
             "required": true
+          },
+          {
+            "pattern": "^[0-9]+$",
+            "type": "string",
+            "description": "The tree ID of the shard that you wish to prove consistency for (defaults to the active shard)",
+            "name": "treeID",
+            "in": "query"
           }
         ],
         "responses": {
@@ -304,7 +551,7 @@ func init() {
     },
     "/api/v1/log/publicKey": {
       "get": {
-        "description": "Returns the public key that can be used to validate the signed tree head",
+        "description": "Returns the public key that can be used to validate the signed tree head, or every key the log has signed with if all is set\n",
         "produces": [
           "application/x-pem-file"
         ],
@@ -313,6 +560,22 @@ func init() {
         ],
         "summary": "Retrieve the public key that can be used to validate the signed tree head",
         "operationId": "getPublicKey",
+        "parameters": [
+          {
+            "pattern": "^[0-9]+$",
+            "type": "string",
+            "description": "The tree ID of the shard whose public key is requested (defaults to the active shard)",
+            "name": "treeID",
+            "in": "query"
+          },
+          {
+            "type": "boolean",
+            "default": false,
+            "description": "Return every key that the log signs or has signed tree heads with, as a series of PEM blocks whose Key-ID header is the key hint of the tree heads signed by the key, and whose Not-Before and Not-After headers (if present) bound the period in which the log signed with it\n",
+            "name": "all",
+            "in": "query"
+          }
+        ],
         "responses": {
           "200": {
             "description": "The public key",
@@ -328,6 +591,39 @@ func init() {
     }
   },
   "definitions": {
+    "BatchEntryResult": {
+      "type": "object",
+      "required": [
+        "status"
+      ],
+      "properties": {
+        "entry": {
+          "$ref": "#/definitions/LogEntry"
+        },
+        "error": {
+          "$ref": "#/definitions/Error"
+        },
+        "location": {
+          "description": "URI location of the entry in the transparency log",
+          "type": "string",
+          "format": "uri"
+        },
+        "status": {
+          "description": "whether the entry was created, already existed in the transparency log, or could not be created",
+          "type": "string",
+          "enum": [
+            "created",
+            "conflict",
+            "error"
+          ]
+        },
+        "uuid": {
+          "description": "the UUID of the entry in the transparency log",
+          "type": "string",
+          "pattern": "^([0-9a-fA-F]{64}|[0-9a-fA-F]{80})$"
+        }
+      }
+    },
     "ConsistencyProof": {
       "type": "object",
       "required": [
@@ -350,6 +646,38 @@ func init() {
         }
       }
     },
+    "Cosignature": {
+      "description": "a signature by a witness over a signed tree head of the transparency log, made once the witness has verified that the tree head is consistent with those it has seen before",
+      "type": "object",
+      "required": [
+        "treeID",
+        "keyHint",
+        "logRoot",
+        "signature"
+      ],
+      "properties": {
+        "keyHint": {
+          "description": "The SHA-256 digest of the DER encoded public key of the witness",
+          "type": "string",
+          "format": "byte"
+        },
+        "logRoot": {
+          "description": "The log root cosigned by the witness",
+          "type": "string",
+          "format": "byte"
+        },
+        "signature": {
+          "description": "Signature by the witness over the tree ID and log root",
+          "type": "string",
+          "format": "byte"
+        },
+        "treeID": {
+          "description": "The tree ID of the shard the log root is of",
+          "type": "string",
+          "pattern": "^[0-9]+$"
+        }
+      }
+    },
     "Error": {
       "type": "object",
       "properties": {
@@ -361,6 +689,83 @@ func init() {
         }
       }
     },
+    "InactiveShardLogInfo": {
+      "type": "object",
+      "required": [
+        "rootHash",
+        "treeSize",
+        "signedTreeHead",
+        "treeID",
+        "startIndex"
+      ],
+      "properties": {
+        "rootHash": {
+          "description": "The hash value stored at the root of the merkle tree of the shard",
+          "type": "string",
+          "pattern": "^[0-9a-fA-F]{64}$"
+        },
+        "signedTreeHead": {
+          "description": "The signed tree head of the shard",
+          "type": "object",
+          "required": [
+            "keyHint",
+            "logRoot",
+            "signature"
+          ],
+          "properties": {
+            "keyHint": {
+              "description": "The SHA-256 digest of the DER encoded public key that signed the log root",
+              "type": "string",
+              "format": "byte"
+            },
+            "logRoot": {
+              "description": "Log root",
+              "type": "string",
+              "format": "byte"
+            },
+            "signature": {
+              "description": "Signature for log root",
+              "type": "string",
+              "format": "byte"
+            }
+          }
+        },
+        "startIndex": {
+          "description": "The index in the log of the first entry in the shard",
+          "type": "integer"
+        },
+        "treeID": {
+          "description": "The tree ID of the shard",
+          "type": "string",
+          "pattern": "^[0-9]+$"
+        },
+        "treeSize": {
+          "description": "The number of nodes in the merkle tree of the shard",
+          "type": "integer",
+          "minimum": 1
+        }
+      }
+    },
+    "InclusionPromise": {
+      "description": "a commitment, signed by the log, to include a queued entry in the transparency log",
+      "type": "object",
+      "required": [
+        "payload",
+        "signature"
+      ],
+      "properties": {
+        "payload": {
+          "description": "JSON object holding the logID, queuedTime and uuid of the entry",
+          "type": "string",
+          "format": "byte"
+        },
+        "signature": {
+          "description": "signature over the payload, which can be verified with the public key of the log",
+          "type": "string",
+          "format": "byte"
+        }
+      }
+    },
     "InclusionProof": {
       "type": "object",
       "required": [
@@ -420,6 +825,42 @@ func init() {
         }
       }
     },
+    "LogEntryStatus": {
+      "type": "object",
+      "required": [
+        "uuid",
+        "status"
+      ],
+      "properties": {
+        "inclusionProof": {
+          "$ref": "#/definitions/InclusionProof"
+        },
+        "integratedTime": {
+          "type": "integer"
+        },
+        "logIndex": {
+          "type": "integer",
+          "x-nullable": true
+        },
+        "queuedTime": {
+          "description": "the time at which the entry was queued, in seconds since the Unix epoch (if known)",
+          "type": "integer"
+        },
+        "status": {
+          "description": "whether the entry is waiting to be included in the transparency log, or has been included",
+          "type": "string",
+          "enum": [
+            "queued",
+            "integrated"
+          ]
+        },
+        "uuid": {
+          "description": "the UUID of the entry",
+          "type": "string",
+          "pattern": "^([0-9a-fA-F]{64}|[0-9a-fA-F]{80})$"
+        }
+      }
+    },
     "LogInfo": {
       "type": "object",
       "required": [
@@ -428,6 +869,20 @@ func init() {
         "signedTreeHead"
       ],
       "properties": {
+        "cosignatures": {
+          "description": "Cosignatures of the signed tree head, or of earlier tree heads of the active shard, by witnesses of the log",
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/Cosignature"
+          }
+        },
+        "inactiveShards": {
+          "description": "The frozen shards of the log, which hold entries added before the active shard",
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/InactiveShardLogInfo"
+          }
+        },
         "rootHash": {
           "description": "The current hash value stored at the root of the merkle tree",
           "type": "string",
@@ -443,7 +898,7 @@ func init() {
           ],
           "properties": {
             "keyHint": {
-              "description": "Key hint",
+              "description": "The SHA-256 digest of the DER encoded public key that signed the log root",
               "type": "string",
               "format": "byte"
             },
@@ -459,6 +914,22 @@ func init() {
             }
           }
         },
+        "startIndex": {
+          "description": "The index in the log of the first entry of the active shard; indexes between the end of the frozen shards and this index are unused",
+          "type": "integer"
+        },
+        "supportedFormats": {
+          "description": "The signature formats of public keys and signatures that the server accepts",
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        },
+        "treeID": {
+          "description": "The tree ID of the active shard, which new entries are added to",
+          "type": "string",
+          "pattern": "^[0-9]+$"
+        },
         "treeSize": {
           "description": "The current number of nodes in the merkle tree",
           "type": "integer",
@@ -478,6 +949,33 @@ func init() {
       },
       "discriminator": "kind"
     },
+    "QueuedLogEntry": {
+      "type": "object",
+      "required": [
+        "uuid",
+        "statusURL",
+        "queuedTime"
+      ],
+      "properties": {
+        "promise": {
+          "$ref": "#/definitions/InclusionPromise"
+        },
+        "queuedTime": {
+          "description": "the time at which the entry was queued, in seconds since the Unix epoch",
+          "type": "integer"
+        },
+        "statusURL": {
+          "description": "URI location of the status of the entry",
+          "type": "string",
+          "format": "uri"
+        },
+        "uuid": {
+          "description": "the UUID the entry will have in the transparency log",
+          "type": "string",
+          "pattern": "^([0-9a-fA-F]{64}|[0-9a-fA-F]{80})$"
+        }
+      }
+    },
     "SearchIndex": {
       "type": "object",
       "properties": {
@@ -496,13 +994,8 @@ func init() {
               "format": "byte"
             },
             "format": {
-              "type": "string",
-              "enum": [
-                "pgp",
-                "x509",
-                "minisign",
-                "ssh"
-              ]
+              "description": "The signature format of the public key; one of the supportedFormats of the log info",
+              "type": "string"
             },
             "url": {
               "type": "string",
@@ -526,7 +1019,7 @@ func init() {
           "type": "array",
           "items": {
             "type": "string",
-            "pattern": "^[0-9a-fA-F]{64}$",
+            "pattern": "^([0-9a-fA-F]{64}|[0-9a-fA-F]{80})$",
             "minItems": 1
           }
         },
@@ -539,6 +1032,69 @@ func init() {
         }
       }
     },
+    "ValidationError": {
+      "type": "object",
+      "required": [
+        "stage",
+        "message"
+      ],
+      "properties": {
+        "format": {
+          "description": "signature format whose admission policy rejected the entry",
+          "type": "string"
+        },
+        "message": {
+          "type": "string"
+        },
+        "stage": {
+          "description": "the step of processing the entry that failed",
+          "type": "string",
+          "enum": [
+            "decode",
+            "validate",
+            "fetch",
+            "verify",
+            "policy"
+          ]
+        }
+      }
+    },
+    "ValidationResult": {
+      "type": "object",
+      "required": [
+        "valid"
+      ],
+      "properties": {
+        "body": {
+          "description": "the canonicalized entry, as it would be stored in the transparency log",
+          "type": "object",
+          "additionalProperties": true
+        },
+        "errors": {
+          "description": "the reasons the entry would be rejected, if any",
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/ValidationError"
+          }
+        },
+        "indexKeys": {
+          "description": "the keys under which the entry would be indexed",
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        },
+        "uuid": {
+          "description": "the UUID the entry would be assigned in the transparency log",
+          "type": "string",
+          "pattern": "^([0-9a-fA-F]{64}|[0-9a-fA-F]{80})$"
+        },
+        "valid": {
+          "description": "whether the entry would be accepted into the transparency log",
+          "type": "boolean"
+        }
+      }
+    },
     "jar": {
       "description": "Java Archive (JAR)",
       "type": "object",
@@ -693,7 +1249,7 @@ func init() {
               "items": {
                 "description": "Entry UUID in transparency log",
                 "type": "string",
-                "pattern": "^[0-9a-fA-F]{64}$"
+                "pattern": "^([0-9a-fA-F]{64}|[0-9a-fA-F]{80})$"
               }
             }
           },
@@ -736,6 +1292,46 @@ func init() {
         }
       }
     },
+    "/api/v1/log/cosignatures": {
+      "post": {
+        "description": "Accepts the signature of a witness over a tree head of the transparency log, once the witness has verified that the tree head is consistent with those it has seen before. The cosignature is served alongside the tree head by getLogInfo.\n",
+        "tags": [
+          "tlog"
+        ],
+        "summary": "Adds a witness cosignature of a signed tree head of the transparency log",
+        "operationId": "addCosignature",
+        "parameters": [
+          {
+            "name": "cosignature",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/Cosignature"
+            }
+          }
+        ],
+        "responses": {
+          "201": {
+            "description": "Returns the cosignature accepted by the transparency log",
+            "schema": {
+              "$ref": "#/definitions/Cosignature"
+            }
+          },
+          "400": {
+            "description": "The content supplied to the server was invalid",
+            "schema": {
+              "$ref": "#/definitions/Error"
+            }
+          },
+          "default": {
+            "description": "There was an internal error in the server while processing the request",
+            "schema": {
+              "$ref": "#/definitions/Error"
+            }
+          }
+        }
+      }
+    },
     "/api/v1/log/entries": {
       "get": {
         "tags": [
@@ -772,7 +1368,12 @@ func init() {
         }
       },
       "post": {
-        "description": "Creates an entry in the transparency log for a detached signature, public key, and content. Items can be included in the request or fetched by the server when URLs are specified.\n",
+        "description": "Creates an entry in the transparency log for a detached signature, public key, and content. Items can be included in the request or fetched by the server when URLs are specified. The request may also be sent as multipart/form-data, with the proposed entry in a part named 'entry' and items as file parts, which the entry refers to with URLs of the form 'attachment:\u003cpart name\u003e'. If async is set, the server responds as soon as the entry has been queued for inclusion in the log, and the status of the entry can then be polled at the URL returned.\n",
+        "consumes": [
+          "application/json",
+          "application/yaml",
+          "multipart/form-data"
+        ],
         "tags": [
           "entries"
         ],
@@ -786,6 +1387,13 @@ func init() {
             "schema": {
               "$ref": "#/definitions/ProposedEntry"
             }
+          },
+          {
+            "type": "boolean",
+            "default": false,
+            "description": "respond once the entry has been queued, rather than waiting for it to be included in the log",
+            "name": "async",
+            "in": "query"
           }
         ],
         "responses": {
@@ -806,6 +1414,23 @@ func init() {
               }
             }
           },
+          "202": {
+            "description": "The entry was queued for inclusion in the transparency log",
+            "schema": {
+              "$ref": "#/definitions/QueuedLogEntry"
+            },
+            "headers": {
+              "ETag": {
+                "type": "string",
+                "description": "UUID of log entry"
+              },
+              "Location": {
+                "type": "string",
+                "format": "uri",
+                "description": "URI location of the status of the log entry"
+              }
+            }
+          },
           "400": {
             "description": "The content supplied to the server was invalid",
             "schema": {
@@ -830,11 +1455,95 @@ func init() {
               "$ref": "#/definitions/Error"
             }
           }
-        }
+        },
+        "x-multipart-form-data": [
+          {
+            "description": "The proposed entry as JSON, in place of the request body",
+            "name": "entry",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "description": "Any number of file parts, each of which is referred to by the URL 'attachment:\u003cpart name\u003e'",
+            "name": "\u003cpart name\u003e",
+            "type": "file"
+          }
+        ]
+      }
+    },
+    "/api/v1/log/entries/batch": {
+      "post": {
+        "description": "Creates an entry in the transparency log for each proposed entry, waiting once for all of the entries to be included in the log. The result for each proposed entry is returned in the same position as the entry in the request. The request may also be sent as multipart/form-data, with the array of proposed entries in a part named 'entries' and items as file parts, as for createLogEntry.\n",
+        "consumes": [
+          "application/json",
+          "application/yaml",
+          "multipart/form-data"
+        ],
+        "tags": [
+          "entries"
+        ],
+        "summary": "Creates multiple entries in the transparency log",
+        "operationId": "createLogEntries",
+        "parameters": [
+          {
+            "name": "proposedEntries",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "type": "array",
+              "minItems": 1,
+              "items": {
+                "$ref": "#/definitions/ProposedEntry"
+              }
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Returns the result of creating each entry",
+            "schema": {
+              "type": "array",
+              "items": {
+                "$ref": "#/definitions/BatchEntryResult"
+              }
+            }
+          },
+          "400": {
+            "description": "The content supplied to the server was invalid",
+            "schema": {
+              "$ref": "#/definitions/Error"
+            }
+          },
+          "default": {
+            "description": "There was an internal error in the server while processing the request",
+            "schema": {
+              "$ref": "#/definitions/Error"
+            }
+          }
+        },
+        "x-multipart-form-data": [
+          {
+            "description": "The array of proposed entries as JSON, in place of the request body",
+            "name": "entries",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "description": "Any number of file parts, each of which is referred to by the URL 'attachment:\u003cpart name\u003e'",
+            "name": "\u003cpart name\u003e",
+            "type": "file"
+          }
+        ]
       }
     },
     "/api/v1/log/entries/retrieve": {
       "post": {
+        "description": "The request may also be sent as multipart/form-data, with the query in a part named 'query' and items of proposed entries as file parts, which the entries refer to with URLs of the form 'attachment:\u003cpart name\u003e'.\n",
+        "consumes": [
+          "application/json",
+          "application/yaml",
+          "multipart/form-data"
+        ],
         "tags": [
           "entries"
         ],
@@ -872,7 +1581,78 @@ func init() {
               "$ref": "#/definitions/Error"
             }
           }
-        }
+        },
+        "x-multipart-form-data": [
+          {
+            "description": "The search query as JSON, in place of the request body",
+            "name": "query",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "description": "Any number of file parts, each of which is referred to by the URL 'attachment:\u003cpart name\u003e'",
+            "name": "\u003cpart name\u003e",
+            "type": "file"
+          }
+        ]
+      }
+    },
+    "/api/v1/log/entries/validate": {
+      "post": {
+        "description": "Performs every check made when an entry is created, without adding the entry to the transparency log. Returns the canonicalized entry, the UUID and index keys it would be assigned, and the reasons it would be rejected (if any). The request may also be sent as multipart/form-data, as for createLogEntry.\n",
+        "consumes": [
+          "application/json",
+          "application/yaml",
+          "multipart/form-data"
+        ],
+        "tags": [
+          "entries"
+        ],
+        "summary": "Checks whether an entry would be accepted into the transparency log",
+        "operationId": "validateLogEntry",
+        "parameters": [
+          {
+            "name": "proposedEntry",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/ProposedEntry"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "The result of checking the entry; an entry that would be rejected is reported with valid set to false",
+            "schema": {
+              "$ref": "#/definitions/ValidationResult"
+            }
+          },
+          "400": {
+            "description": "The content supplied to the server was invalid",
+            "schema": {
+              "$ref": "#/definitions/Error"
+            }
+          },
+          "default": {
+            "description": "There was an internal error in the server while processing the request",
+            "schema": {
+              "$ref": "#/definitions/Error"
+            }
+          }
+        },
+        "x-multipart-form-data": [
+          {
+            "description": "The proposed entry as JSON, in place of the request body",
+            "name": "entry",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "description": "Any number of file parts, each of which is referred to by the URL 'attachment:\u003cpart name\u003e'",
+            "name": "\u003cpart name\u003e",
+            "type": "file"
+          }
+        ]
       }
     },
     "/api/v1/log/entries/{entryUUID}": {
@@ -881,13 +1661,50 @@ func init() {
         "tags": [
           "entries"
         ],
-        "summary": "Get log entry and information required to generate an inclusion proof for the entry in the transparency log",
-        "operationId": "getLogEntryByUUID",
+        "summary": "Get log entry and information required to generate an inclusion proof for the entry in the transparency log",
+        "operationId": "getLogEntryByUUID",
+        "parameters": [
+          {
+            "pattern": "^([0-9a-fA-F]{64}|[0-9a-fA-F]{80})$",
+            "type": "string",
+            "description": "the UUID of the entry for which the inclusion proof information should be returned",
+            "name": "entryUUID",
+            "in": "path",
+            "required": true
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Information needed for a client to compute the inclusion proof",
+            "schema": {
+              "$ref": "#/definitions/LogEntry"
+            }
+          },
+          "404": {
+            "description": "The content requested could not be found"
+          },
+          "default": {
+            "description": "There was an internal error in the server while processing the request",
+            "schema": {
+              "$ref": "#/definitions/Error"
+            }
+          }
+        }
+      }
+    },
+    "/api/v1/log/entries/{entryUUID}/status": {
+      "get": {
+        "description": "Returns whether the entry is still queued or has been included in the transparency log; once it has been included, its index and an inclusion proof are also returned. The status of a queued entry is best-effort: an entry queued by another instance of the server, or before the server restarted, may not be found until it has been included, unless the server records queued entries in Redis\n",
+        "tags": [
+          "entries"
+        ],
+        "summary": "Get the status of an entry that was queued for inclusion in the transparency log",
+        "operationId": "getLogEntryStatus",
         "parameters": [
           {
-            "pattern": "^[0-9a-fA-F]{64}$",
+            "pattern": "^([0-9a-fA-F]{64}|[0-9a-fA-F]{80})$",
             "type": "string",
-            "description": "the UUID of the entry for which the inclusion proof information should be returned",
+            "description": "the UUID of the entry",
             "name": "entryUUID",
             "in": "path",
             "required": true
@@ -895,9 +1712,9 @@ func init() {
         ],
         "responses": {
           "200": {
-            "description": "Information needed for a client to compute the inclusion proof",
+            "description": "The status of the entry",
             "schema": {
-              "$ref": "#/definitions/LogEntry"
+              "$ref": "#/definitions/LogEntryStatus"
             }
           },
           "404": {
@@ -936,6 +1753,13 @@ func init() {
             "name": "lastSize",
             "in": "query",
             "required": true
+          },
+          {
+            "pattern": "^[0-9]+$",
+            "type": "string",
+            "description": "The tree ID of the shard that you wish to prove consistency for (defaults to the active shard)",
+            "name": "treeID",
+            "in": "query"
           }
         ],
         "responses": {
@@ -962,7 +1786,7 @@ func init() {
     },
     "/api/v1/log/publicKey": {
       "get": {
-        "description": "Returns the public key that can be used to validate the signed tree head",
+        "description": "Returns the public key that can be used to validate the signed tree head, or every key the log has signed with if all is set\n",
         "produces": [
           "application/x-pem-file"
         ],
@@ -971,6 +1795,22 @@ func init() {
         ],
         "summary": "Retrieve the public key that can be used to validate the signed tree head",
         "operationId": "getPublicKey",
+        "parameters": [
+          {
+            "pattern": "^[0-9]+$",
+            "type": "string",
+            "description": "The tree ID of the shard whose public key is requested (defaults to the active shard)",
+            "name": "treeID",
+            "in": "query"
+          },
+          {
+            "type": "boolean",
+            "default": false,
+            "description": "Return every key that the log signs or has signed tree heads with, as a series of PEM blocks whose Key-ID header is the key hint of the tree heads signed by the key, and whose Not-Before and Not-After headers (if present) bound the period in which the log signed with it\n",
+            "name": "all",
+            "in": "query"
+          }
+        ],
         "responses": {
           "200": {
             "description": "The public key",
@@ -989,6 +1829,39 @@ func init() {
     }
   },
   "definitions": {
+    "BatchEntryResult": {
+      "type": "object",
+      "required": [
+        "status"
+      ],
+      "properties": {
+        "entry": {
+          "$ref": "#/definitions/LogEntry"
+        },
+        "error": {
+          "$ref": "#/definitions/Error"
+        },
+        "location": {
+          "description": "URI location of the entry in the transparency log",
+          "type": "string",
+          "format": "uri"
+        },
+        "status": {
+          "description": "whether the entry was created, already existed in the transparency log, or could not be created",
+          "type": "string",
+          "enum": [
+            "created",
+            "conflict",
+            "error"
+          ]
+        },
+        "uuid": {
+          "description": "the UUID of the entry in the transparency log",
+          "type": "string",
+          "pattern": "^([0-9a-fA-F]{64}|[0-9a-fA-F]{80})$"
+        }
+      }
+    },
     "ConsistencyProof": {
       "type": "object",
       "required": [
@@ -1011,6 +1884,38 @@ func init() {
         }
       }
     },
+    "Cosignature": {
+      "description": "a signature by a witness over a signed tree head of the transparency log, made once the witness has verified that the tree head is consistent with those it has seen before",
+      "type": "object",
+      "required": [
+        "treeID",
+        "keyHint",
+        "logRoot",
+        "signature"
+      ],
+      "properties": {
+        "keyHint": {
+          "description": "The SHA-256 digest of the DER encoded public key of the witness",
+          "type": "string",
+          "format": "byte"
+        },
+        "logRoot": {
+          "description": "The log root cosigned by the witness",
+          "type": "string",
+          "format": "byte"
+        },
+        "signature": {
+          "description": "Signature by the witness over the tree ID and log root",
+          "type": "string",
+          "format": "byte"
+        },
+        "treeID": {
+          "description": "The tree ID of the shard the log root is of",
+          "type": "string",
+          "pattern": "^[0-9]+$"
+        }
+      }
+    },
     "Error": {
       "type": "object",
       "properties": {
@@ -1022,6 +1927,110 @@ func init() {
         }
       }
     },
+    "InactiveShardLogInfo": {
+      "type": "object",
+      "required": [
+        "rootHash",
+        "treeSize",
+        "signedTreeHead",
+        "treeID",
+        "startIndex"
+      ],
+      "properties": {
+        "rootHash": {
+          "description": "The hash value stored at the root of the merkle tree of the shard",
+          "type": "string",
+          "pattern": "^[0-9a-fA-F]{64}$"
+        },
+        "signedTreeHead": {
+          "description": "The signed tree head of the shard",
+          "type": "object",
+          "required": [
+            "keyHint",
+            "logRoot",
+            "signature"
+          ],
+          "properties": {
+            "keyHint": {
+              "description": "The SHA-256 digest of the DER encoded public key that signed the log root",
+              "type": "string",
+              "format": "byte"
+            },
+            "logRoot": {
+              "description": "Log root",
+              "type": "string",
+              "format": "byte"
+            },
+            "signature": {
+              "description": "Signature for log root",
+              "type": "string",
+              "format": "byte"
+            }
+          }
+        },
+        "startIndex": {
+          "description": "The index in the log of the first entry in the shard",
+          "type": "integer",
+          "minimum": 0
+        },
+        "treeID": {
+          "description": "The tree ID of the shard",
+          "type": "string",
+          "pattern": "^[0-9]+$"
+        },
+        "treeSize": {
+          "description": "The number of nodes in the merkle tree of the shard",
+          "type": "integer",
+          "minimum": 1
+        }
+      }
+    },
+    "InactiveShardLogInfoSignedTreeHead": {
+      "description": "The signed tree head of the shard",
+      "type": "object",
+      "required": [
+        "keyHint",
+        "logRoot",
+        "signature"
+      ],
+      "properties": {
+        "keyHint": {
+          "description": "The SHA-256 digest of the DER encoded public key that signed the log root",
+          "type": "string",
+          "format": "byte"
+        },
+        "logRoot": {
+          "description": "Log root",
+          "type": "string",
+          "format": "byte"
+        },
+        "signature": {
+          "description": "Signature for log root",
+          "type": "string",
+          "format": "byte"
+        }
+      }
+    },
+    "InclusionPromise": {
+      "description": "a commitment, signed by the log, to include a queued entry in the transparency log",
+      "type": "object",
+      "required": [
+        "payload",
+        "signature"
+      ],
+      "properties": {
+        "payload": {
+          "description": "JSON object holding the logID, queuedTime and uuid of the entry",
+          "type": "string",
+          "format": "byte"
+        },
+        "signature": {
+          "description": "signature over the payload, which can be verified with the public key of the log",
+          "type": "string",
+          "format": "byte"
+        }
+      }
+    },
     "InclusionProof": {
       "type": "object",
       "required": [
@@ -1199,6 +2208,43 @@ func init() {
         }
       }
     },
+    "LogEntryStatus": {
+      "type": "object",
+      "required": [
+        "uuid",
+        "status"
+      ],
+      "properties": {
+        "inclusionProof": {
+          "$ref": "#/definitions/InclusionProof"
+        },
+        "integratedTime": {
+          "type": "integer"
+        },
+        "logIndex": {
+          "type": "integer",
+          "minimum": 0,
+          "x-nullable": true
+        },
+        "queuedTime": {
+          "description": "the time at which the entry was queued, in seconds since the Unix epoch (if known)",
+          "type": "integer"
+        },
+        "status": {
+          "description": "whether the entry is waiting to be included in the transparency log, or has been included",
+          "type": "string",
+          "enum": [
+            "queued",
+            "integrated"
+          ]
+        },
+        "uuid": {
+          "description": "the UUID of the entry",
+          "type": "string",
+          "pattern": "^([0-9a-fA-F]{64}|[0-9a-fA-F]{80})$"
+        }
+      }
+    },
     "LogInfo": {
       "type": "object",
       "required": [
@@ -1207,6 +2253,20 @@ func init() {
         "signedTreeHead"
       ],
       "properties": {
+        "cosignatures": {
+          "description": "Cosignatures of the signed tree head, or of earlier tree heads of the active shard, by witnesses of the log",
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/Cosignature"
+          }
+        },
+        "inactiveShards": {
+          "description": "The frozen shards of the log, which hold entries added before the active shard",
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/InactiveShardLogInfo"
+          }
+        },
         "rootHash": {
           "description": "The current hash value stored at the root of the merkle tree",
           "type": "string",
@@ -1222,7 +2282,7 @@ func init() {
           ],
           "properties": {
             "keyHint": {
-              "description": "Key hint",
+              "description": "The SHA-256 digest of the DER encoded public key that signed the log root",
               "type": "string",
               "format": "byte"
             },
@@ -1238,6 +2298,23 @@ func init() {
             }
           }
         },
+        "startIndex": {
+          "description": "The index in the log of the first entry of the active shard; indexes between the end of the frozen shards and this index are unused",
+          "type": "integer",
+          "minimum": 0
+        },
+        "supportedFormats": {
+          "description": "The signature formats of public keys and signatures that the server accepts",
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        },
+        "treeID": {
+          "description": "The tree ID of the active shard, which new entries are added to",
+          "type": "string",
+          "pattern": "^[0-9]+$"
+        },
         "treeSize": {
           "description": "The current number of nodes in the merkle tree",
           "type": "integer",
@@ -1255,7 +2332,7 @@ func init() {
       ],
       "properties": {
         "keyHint": {
-          "description": "Key hint",
+          "description": "The SHA-256 digest of the DER encoded public key that signed the log root",
           "type": "string",
           "format": "byte"
         },
@@ -1283,6 +2360,33 @@ func init() {
       },
       "discriminator": "kind"
     },
+    "QueuedLogEntry": {
+      "type": "object",
+      "required": [
+        "uuid",
+        "statusURL",
+        "queuedTime"
+      ],
+      "properties": {
+        "promise": {
+          "$ref": "#/definitions/InclusionPromise"
+        },
+        "queuedTime": {
+          "description": "the time at which the entry was queued, in seconds since the Unix epoch",
+          "type": "integer"
+        },
+        "statusURL": {
+          "description": "URI location of the status of the entry",
+          "type": "string",
+          "format": "uri"
+        },
+        "uuid": {
+          "description": "the UUID the entry will have in the transparency log",
+          "type": "string",
+          "pattern": "^([0-9a-fA-F]{64}|[0-9a-fA-F]{80})$"
+        }
+      }
+    },
     "RekordV001SchemaData": {
       "description": "Information about the content associated with the entry",
       "type": "object",
@@ -1373,20 +2477,18 @@ func init() {
         }
       ],
       "properties": {
+        "algorithm": {
+          "description": "Specifies the hash function and padding scheme used to generate the signature, for formats that support more than one; if omitted, it is inferred when the signature is verified",
+          "type": "string"
+        },
         "content": {
           "description": "Specifies the content of the signature inline within the document",
           "type": "string",
           "format": "byte"
         },
         "format": {
-          "description": "Specifies the format of the signature",
-          "type": "string",
-          "enum": [
-            "pgp",
-            "minisign",
-            "x509",
-            "ssh"
-          ]
+          "description": "Specifies the format of the signature; one of the supportedFormats of the log info",
+          "type": "string"
         },
         "publicKey": {
           "description": "The public key that can verify the signature",
@@ -1574,13 +2676,8 @@ func init() {
               "format": "byte"
             },
             "format": {
-              "type": "string",
-              "enum": [
-                "pgp",
-                "x509",
-                "minisign",
-                "ssh"
-              ]
+              "description": "The signature format of the public key; one of the supportedFormats of the log info",
+              "type": "string"
             },
             "url": {
               "type": "string",
@@ -1601,13 +2698,8 @@ func init() {
           "format": "byte"
         },
         "format": {
-          "type": "string",
-          "enum": [
-            "pgp",
-            "x509",
-            "minisign",
-            "ssh"
-          ]
+          "description": "The signature format of the public key; one of the supportedFormats of the log info",
+          "type": "string"
         },
         "url": {
           "type": "string",
@@ -1628,7 +2720,7 @@ func init() {
           "type": "array",
           "items": {
             "type": "string",
-            "pattern": "^[0-9a-fA-F]{64}$"
+            "pattern": "^([0-9a-fA-F]{64}|[0-9a-fA-F]{80})$"
           }
         },
         "logIndexes": {
@@ -1641,6 +2733,69 @@ func init() {
         }
       }
     },
+    "ValidationError": {
+      "type": "object",
+      "required": [
+        "stage",
+        "message"
+      ],
+      "properties": {
+        "format": {
+          "description": "signature format whose admission policy rejected the entry",
+          "type": "string"
+        },
+        "message": {
+          "type": "string"
+        },
+        "stage": {
+          "description": "the step of processing the entry that failed",
+          "type": "string",
+          "enum": [
+            "decode",
+            "validate",
+            "fetch",
+            "verify",
+            "policy"
+          ]
+        }
+      }
+    },
+    "ValidationResult": {
+      "type": "object",
+      "required": [
+        "valid"
+      ],
+      "properties": {
+        "body": {
+          "description": "the canonicalized entry, as it would be stored in the transparency log",
+          "type": "object",
+          "additionalProperties": true
+        },
+        "errors": {
+          "description": "the reasons the entry would be rejected, if any",
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/ValidationError"
+          }
+        },
+        "indexKeys": {
+          "description": "the keys under which the entry would be indexed",
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        },
+        "uuid": {
+          "description": "the UUID the entry would be assigned in the transparency log",
+          "type": "string",
+          "pattern": "^([0-9a-fA-F]{64}|[0-9a-fA-F]{80})$"
+        },
+        "valid": {
+          "description": "whether the entry would be accepted into the transparency log",
+          "type": "boolean"
+        }
+      }
+    },
     "jar": {
       "description": "Java Archive (JAR)",
       "type": "object",
@@ -1893,20 +3048,18 @@ func init() {
             }
           ],
           "properties": {
+            "algorithm": {
+              "description": "Specifies the hash function and padding scheme used to generate the signature, for formats that support more than one; if omitted, it is inferred when the signature is verified",
+              "type": "string"
+            },
             "content": {
               "description": "Specifies the content of the signature inline within the document",
               "type": "string",
               "format": "byte"
             },
             "format": {
-              "description": "Specifies the format of the signature",
-              "type": "string",
-              "enum": [
-                "pgp",
-                "minisign",
-                "x509",
-                "ssh"
-              ]
+              "description": "Specifies the format of the signature; one of the supportedFormats of the log info",
+              "type": "string"
             },
             "publicKey": {
               "description": "The public key that can verify the signature",
@@ -49,7 +49,7 @@ func NewCreateLogEntry(ctx *middleware.Context, handler CreateLogEntryHandler) *
 
 Creates an entry in the transparency log
 
-Creates an entry in the transparency log for a detached signature, public key, and content. Items can be included in the request or fetched by the server when URLs are specified.
+Creates an entry in the transparency log for a detached signature, public key, and content. Items can be included in the request or fetched by the server when URLs are specified. The request may also be sent as multipart/form-data, with the proposed entry in a part named 'entry' and items as file parts, which the entry refers to with URLs of the form 'attachment:<part name>'. If async is set, the server responds as soon as the entry has been queued for inclusion in the log, and the status of the entry can then be polled at the URL returned.
 
 
 */
@@ -29,17 +29,26 @@ import (
 	"github.com/go-openapi/errors"
 	"github.com/go-openapi/runtime"
 	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
 	"github.com/go-openapi/validate"
 
 	"github.com/sigstore/rekor/pkg/generated/models"
 )
 
 // NewCreateLogEntryParams creates a new CreateLogEntryParams object
-//
-// There are no default values defined in the spec.
+// with the default values initialized.
 func NewCreateLogEntryParams() CreateLogEntryParams {
 
-	return CreateLogEntryParams{}
+	var (
+		// initialize parameters with default values
+
+		asyncDefault = bool(false)
+	)
+
+	return CreateLogEntryParams{
+		Async: &asyncDefault,
+	}
 }
 
 // CreateLogEntryParams contains all the bound params for the create log entry operation
@@ -51,6 +60,11 @@ type CreateLogEntryParams struct {
 	// HTTP Request Object
 	HTTPRequest *http.Request `json:"-"`
 
+	/*respond once the entry has been queued, rather than waiting for it to be included in the log
+	  In: query
+	  Default: false
+	*/
+	Async *bool
 	/*
 	  Required: true
 	  In: body
@@ -67,6 +81,13 @@ func (o *CreateLogEntryParams) BindRequest(r *http.Request, route *middleware.Ma
 
 	o.HTTPRequest = r
 
+	qs := runtime.Values(r.URL.Query())
+
+	qAsync, qhkAsync, _ := qs.GetOK("async")
+	if err := o.bindAsync(qAsync, qhkAsync, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
 	if runtime.HasBody(r) {
 		defer r.Body.Close()
 		body, err := models.UnmarshalProposedEntry(r.Body, route.Consumer)
@@ -98,3 +119,27 @@ func (o *CreateLogEntryParams) BindRequest(r *http.Request, route *middleware.Ma
 	}
 	return nil
 }
+
+// bindAsync binds and validates parameter Async from query.
+func (o *CreateLogEntryParams) bindAsync(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+
+	// Required: false
+	// AllowEmptyValue: false
+
+	if raw == "" { // empty values pass all other validations
+		// Default values have been previously initialized by NewCreateLogEntryParams()
+		return nil
+	}
+
+	value, err := swag.ConvertBool(raw)
+	if err != nil {
+		return errors.InvalidType("async", "query", "bool", raw)
+	}
+	o.Async = &value
+
+	return nil
+}
@@ -121,6 +121,94 @@ func (o *CreateLogEntryCreated) WriteResponse(rw http.ResponseWriter, producer r
 	}
 }
 
+// CreateLogEntryAcceptedCode is the HTTP code returned for type CreateLogEntryAccepted
+const CreateLogEntryAcceptedCode int = 202
+
+/*CreateLogEntryAccepted The entry was queued for inclusion in the transparency log
+
+swagger:response createLogEntryAccepted
+*/
+type CreateLogEntryAccepted struct {
+	/*UUID of log entry
+
+	 */
+	ETag string `json:"ETag"`
+	/*URI location of the status of the log entry
+
+	 */
+	Location strfmt.URI `json:"Location"`
+
+	/*
+	  In: Body
+	*/
+	Payload *models.QueuedLogEntry `json:"body,omitempty"`
+}
+
+// NewCreateLogEntryAccepted creates CreateLogEntryAccepted with default headers values
+func NewCreateLogEntryAccepted() *CreateLogEntryAccepted {
+
+	return &CreateLogEntryAccepted{}
+}
+
+// WithETag adds the eTag to the create log entry accepted response
+func (o *CreateLogEntryAccepted) WithETag(eTag string) *CreateLogEntryAccepted {
+	o.ETag = eTag
+	return o
+}
+
+// SetETag sets the eTag to the create log entry accepted response
+func (o *CreateLogEntryAccepted) SetETag(eTag string) {
+	o.ETag = eTag
+}
+
+// WithLocation adds the location to the create log entry accepted response
+func (o *CreateLogEntryAccepted) WithLocation(location strfmt.URI) *CreateLogEntryAccepted {
+	o.Location = location
+	return o
+}
+
+// SetLocation sets the location to the create log entry accepted response
+func (o *CreateLogEntryAccepted) SetLocation(location strfmt.URI) {
+	o.Location = location
+}
+
+// WithPayload adds the payload to the create log entry accepted response
+func (o *CreateLogEntryAccepted) WithPayload(payload *models.QueuedLogEntry) *CreateLogEntryAccepted {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the create log entry accepted response
+func (o *CreateLogEntryAccepted) SetPayload(payload *models.QueuedLogEntry) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *CreateLogEntryAccepted) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	// response header ETag
+
+	eTag := o.ETag
+	if eTag != "" {
+		rw.Header().Set("ETag", eTag)
+	}
+
+	// response header Location
+
+	location := o.Location.String()
+	if location != "" {
+		rw.Header().Set("Location", location)
+	}
+
+	rw.WriteHeader(202)
+	if o.Payload != nil {
+		payload := o.Payload
+		if err := producer.Produce(rw, payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
+
 // CreateLogEntryBadRequestCode is the HTTP code returned for type CreateLogEntryBadRequest
 const CreateLogEntryBadRequestCode int = 400
 
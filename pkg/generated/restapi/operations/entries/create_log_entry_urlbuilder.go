@@ -25,11 +25,17 @@ import (
 	"errors"
 	"net/url"
 	golangswaggerpaths "path"
+
+	"github.com/go-openapi/swag"
 )
 
 // CreateLogEntryURL generates an URL for the create log entry operation
 type CreateLogEntryURL struct {
+	Async *bool
+
 	_basePath string
+	// avoid unkeyed usage
+	_ struct{}
 }
 
 // WithBasePath sets the base path for this url builder, only required when it's different from the
@@ -56,6 +62,18 @@ func (o *CreateLogEntryURL) Build() (*url.URL, error) {
 	_basePath := o._basePath
 	_result.Path = golangswaggerpaths.Join(_basePath, _path)
 
+	qs := make(url.Values)
+
+	var asyncQ string
+	if o.Async != nil {
+		asyncQ = swag.FormatBool(*o.Async)
+	}
+	if asyncQ != "" {
+		qs.Set("async", asyncQ)
+	}
+
+	_result.RawQuery = qs.Encode()
+
 	return &_result, nil
 }
 
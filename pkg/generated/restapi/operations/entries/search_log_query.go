@@ -49,6 +49,8 @@ func NewSearchLogQuery(ctx *middleware.Context, handler SearchLogQueryHandler) *
 
 Searches transparency log for one or more log entries
 
+The request may also be sent as multipart/form-data, with the query in a part named 'query' and items of proposed entries as file parts, which the entries refer to with URLs of the form 'attachment:<part name>'.
+
 */
 type SearchLogQuery struct {
 	Context *middleware.Context
@@ -70,6 +70,9 @@ func NewRekorServerAPI(spec *loads.Document) *RekorServerAPI {
 		JSONProducer: runtime.JSONProducer(),
 		YamlProducer: yamlpc.YAMLProducer(),
 
+		EntriesCreateLogEntriesHandler: entries.CreateLogEntriesHandlerFunc(func(params entries.CreateLogEntriesParams) middleware.Responder {
+			return middleware.NotImplemented("operation entries.CreateLogEntries has not yet been implemented")
+		}),
 		EntriesCreateLogEntryHandler: entries.CreateLogEntryHandlerFunc(func(params entries.CreateLogEntryParams) middleware.Responder {
 			return middleware.NotImplemented("operation entries.CreateLogEntry has not yet been implemented")
 		}),
@@ -79,6 +82,9 @@ func NewRekorServerAPI(spec *loads.Document) *RekorServerAPI {
 		EntriesGetLogEntryByUUIDHandler: entries.GetLogEntryByUUIDHandlerFunc(func(params entries.GetLogEntryByUUIDParams) middleware.Responder {
 			return middleware.NotImplemented("operation entries.GetLogEntryByUUID has not yet been implemented")
 		}),
+		TlogAddCosignatureHandler: tlog.AddCosignatureHandlerFunc(func(params tlog.AddCosignatureParams) middleware.Responder {
+			return middleware.NotImplemented("operation tlog.AddCosignature has not yet been implemented")
+		}),
 		TlogGetLogInfoHandler: tlog.GetLogInfoHandlerFunc(func(params tlog.GetLogInfoParams) middleware.Responder {
 			return middleware.NotImplemented("operation tlog.GetLogInfo has not yet been implemented")
 		}),
@@ -91,9 +97,15 @@ func NewRekorServerAPI(spec *loads.Document) *RekorServerAPI {
 		IndexSearchIndexHandler: index.SearchIndexHandlerFunc(func(params index.SearchIndexParams) middleware.Responder {
 			return middleware.NotImplemented("operation index.SearchIndex has not yet been implemented")
 		}),
+		EntriesGetLogEntryStatusHandler: entries.GetLogEntryStatusHandlerFunc(func(params entries.GetLogEntryStatusParams) middleware.Responder {
+			return middleware.NotImplemented("operation entries.GetLogEntryStatus has not yet been implemented")
+		}),
 		EntriesSearchLogQueryHandler: entries.SearchLogQueryHandlerFunc(func(params entries.SearchLogQueryParams) middleware.Responder {
 			return middleware.NotImplemented("operation entries.SearchLogQuery has not yet been implemented")
 		}),
+		EntriesValidateLogEntryHandler: entries.ValidateLogEntryHandlerFunc(func(params entries.ValidateLogEntryParams) middleware.Responder {
+			return middleware.NotImplemented("operation entries.ValidateLogEntry has not yet been implemented")
+		}),
 	}
 }
 
@@ -139,12 +151,16 @@ type RekorServerAPI struct {
 	//   - application/yaml
 	YamlProducer runtime.Producer
 
+	// EntriesCreateLogEntriesHandler sets the operation handler for the create log entries operation
+	EntriesCreateLogEntriesHandler entries.CreateLogEntriesHandler
 	// EntriesCreateLogEntryHandler sets the operation handler for the create log entry operation
 	EntriesCreateLogEntryHandler entries.CreateLogEntryHandler
 	// EntriesGetLogEntryByIndexHandler sets the operation handler for the get log entry by index operation
 	EntriesGetLogEntryByIndexHandler entries.GetLogEntryByIndexHandler
 	// EntriesGetLogEntryByUUIDHandler sets the operation handler for the get log entry by UUID operation
 	EntriesGetLogEntryByUUIDHandler entries.GetLogEntryByUUIDHandler
+	// TlogAddCosignatureHandler sets the operation handler for the add cosignature operation
+	TlogAddCosignatureHandler tlog.AddCosignatureHandler
 	// TlogGetLogInfoHandler sets the operation handler for the get log info operation
 	TlogGetLogInfoHandler tlog.GetLogInfoHandler
 	// TlogGetLogProofHandler sets the operation handler for the get log proof operation
@@ -153,8 +169,12 @@ type RekorServerAPI struct {
 	PubkeyGetPublicKeyHandler pubkey.GetPublicKeyHandler
 	// IndexSearchIndexHandler sets the operation handler for the search index operation
 	IndexSearchIndexHandler index.SearchIndexHandler
+	// EntriesGetLogEntryStatusHandler sets the operation handler for the get log entry status operation
+	EntriesGetLogEntryStatusHandler entries.GetLogEntryStatusHandler
 	// EntriesSearchLogQueryHandler sets the operation handler for the search log query operation
 	EntriesSearchLogQueryHandler entries.SearchLogQueryHandler
+	// EntriesValidateLogEntryHandler sets the operation handler for the validate log entry operation
+	EntriesValidateLogEntryHandler entries.ValidateLogEntryHandler
 
 	// ServeError is called when an error is received, there is a default handler
 	// but you can set your own with this
@@ -241,6 +261,9 @@ func (o *RekorServerAPI) Validate() error {
 		unregistered = append(unregistered, "YamlProducer")
 	}
 
+	if o.EntriesCreateLogEntriesHandler == nil {
+		unregistered = append(unregistered, "entries.CreateLogEntriesHandler")
+	}
 	if o.EntriesCreateLogEntryHandler == nil {
 		unregistered = append(unregistered, "entries.CreateLogEntryHandler")
 	}
@@ -250,6 +273,9 @@ func (o *RekorServerAPI) Validate() error {
 	if o.EntriesGetLogEntryByUUIDHandler == nil {
 		unregistered = append(unregistered, "entries.GetLogEntryByUUIDHandler")
 	}
+	if o.TlogAddCosignatureHandler == nil {
+		unregistered = append(unregistered, "tlog.AddCosignatureHandler")
+	}
 	if o.TlogGetLogInfoHandler == nil {
 		unregistered = append(unregistered, "tlog.GetLogInfoHandler")
 	}
@@ -262,9 +288,15 @@ func (o *RekorServerAPI) Validate() error {
 	if o.IndexSearchIndexHandler == nil {
 		unregistered = append(unregistered, "index.SearchIndexHandler")
 	}
+	if o.EntriesGetLogEntryStatusHandler == nil {
+		unregistered = append(unregistered, "entries.GetLogEntryStatusHandler")
+	}
 	if o.EntriesSearchLogQueryHandler == nil {
 		unregistered = append(unregistered, "entries.SearchLogQueryHandler")
 	}
+	if o.EntriesValidateLogEntryHandler == nil {
+		unregistered = append(unregistered, "entries.ValidateLogEntryHandler")
+	}
 
 	if len(unregistered) > 0 {
 		return fmt.Errorf("missing registration: %s", strings.Join(unregistered, ", "))
@@ -359,6 +391,14 @@ func (o *RekorServerAPI) initHandlerCache() {
 		o.handlers = make(map[string]map[string]http.Handler)
 	}
 
+	if o.handlers["POST"] == nil {
+		o.handlers["POST"] = make(map[string]http.Handler)
+	}
+	o.handlers["POST"]["/api/v1/log/cosignatures"] = tlog.NewAddCosignature(o.context, o.TlogAddCosignatureHandler)
+	if o.handlers["POST"] == nil {
+		o.handlers["POST"] = make(map[string]http.Handler)
+	}
+	o.handlers["POST"]["/api/v1/log/entries/batch"] = entries.NewCreateLogEntries(o.context, o.EntriesCreateLogEntriesHandler)
 	if o.handlers["POST"] == nil {
 		o.handlers["POST"] = make(map[string]http.Handler)
 	}
@@ -374,6 +414,10 @@ func (o *RekorServerAPI) initHandlerCache() {
 	if o.handlers["GET"] == nil {
 		o.handlers["GET"] = make(map[string]http.Handler)
 	}
+	o.handlers["GET"]["/api/v1/log/entries/{entryUUID}/status"] = entries.NewGetLogEntryStatus(o.context, o.EntriesGetLogEntryStatusHandler)
+	if o.handlers["GET"] == nil {
+		o.handlers["GET"] = make(map[string]http.Handler)
+	}
 	o.handlers["GET"]["/api/v1/log"] = tlog.NewGetLogInfo(o.context, o.TlogGetLogInfoHandler)
 	if o.handlers["GET"] == nil {
 		o.handlers["GET"] = make(map[string]http.Handler)
@@ -391,6 +435,10 @@ func (o *RekorServerAPI) initHandlerCache() {
 		o.handlers["POST"] = make(map[string]http.Handler)
 	}
 	o.handlers["POST"]["/api/v1/log/entries/retrieve"] = entries.NewSearchLogQuery(o.context, o.EntriesSearchLogQueryHandler)
+	if o.handlers["POST"] == nil {
+		o.handlers["POST"] = make(map[string]http.Handler)
+	}
+	o.handlers["POST"]["/api/v1/log/entries/validate"] = entries.NewValidateLogEntry(o.context, o.EntriesValidateLogEntryHandler)
 }
 
 // Serve creates a http handler to serve the API over HTTP
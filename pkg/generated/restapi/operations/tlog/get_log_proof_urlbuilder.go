@@ -33,6 +33,7 @@ import (
 type GetLogProofURL struct {
 	FirstSize *int64
 	LastSize  int64
+	TreeID    *string
 
 	_basePath string
 	// avoid unkeyed usage
@@ -78,6 +79,14 @@ func (o *GetLogProofURL) Build() (*url.URL, error) {
 		qs.Set("lastSize", lastSizeQ)
 	}
 
+	var treeIDQ string
+	if o.TreeID != nil {
+		treeIDQ = *o.TreeID
+	}
+	if treeIDQ != "" {
+		qs.Set("treeID", treeIDQ)
+	}
+
 	_result.RawQuery = qs.Encode()
 
 	return &_result, nil
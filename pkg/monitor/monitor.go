@@ -0,0 +1,187 @@
+//
+// Copyright 2021 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitor
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-openapi/runtime"
+
+	"github.com/sigstore/rekor/pkg/generated/models"
+	"github.com/sigstore/rekor/pkg/pki"
+	"github.com/sigstore/rekor/pkg/types"
+)
+
+// WatchList is the set of key fingerprints and identities that entries of the log are matched against
+type WatchList struct {
+	fingerprints map[string]bool
+	identities   map[string]bool
+}
+
+// Match is an entry of the log whose signer is on the watch list, or that could not be decoded and so may have
+// been signed by anyone
+type Match struct {
+	LogIndex       int64
+	UUID           string
+	Kind           string `json:",omitempty"`
+	IntegratedTime int64
+	// the fingerprints and identities of the watch list that the signer of the entry matched
+	Fingerprints []string `json:",omitempty"`
+	Identities   []string `json:",omitempty"`
+	// the reason the entry could not be decoded
+	Error string `json:",omitempty"`
+}
+
+// UndecodableEntry returns the match reported for an entry that could not be decoded
+func UndecodableEntry(uuid string, entry models.LogEntryAnon, err error) *Match {
+	m := &Match{
+		UUID:           uuid,
+		IntegratedTime: entry.IntegratedTime,
+		Error:          err.Error(),
+	}
+	if entry.LogIndex != nil {
+		m.LogIndex = *entry.LogIndex
+	}
+	return m
+}
+
+// NewWatchList returns a watch list of the fingerprints and identities given, and the fingerprints of the keys given.
+// Fingerprints are hex encoded SHA-256 digests, and identities are matched without regard to case.
+func NewWatchList(fingerprints, identities []string, keys []pki.PublicKey) (*WatchList, error) {
+	w := &WatchList{fingerprints: map[string]bool{}, identities: map[string]bool{}}
+	for _, f := range fingerprints {
+		f = strings.ToLower(strings.ReplaceAll(f, ":", ""))
+		if b, err := hex.DecodeString(f); err != nil || len(b) != sha256.Size {
+			return nil, fmt.Errorf("fingerprint %v is not a hex encoded SHA-256 digest", f)
+		}
+		w.fingerprints[f] = true
+	}
+	for _, id := range identities {
+		w.identities[strings.ToLower(id)] = true
+	}
+	for _, k := range keys {
+		f, err := Fingerprints(k)
+		if err != nil {
+			return nil, err
+		}
+		// the digest of the canonical value identifies the key exactly as it was given
+		w.fingerprints[f[0]] = true
+	}
+	if len(w.fingerprints) == 0 && len(w.identities) == 0 {
+		return nil, errors.New("watch list is empty")
+	}
+	return w, nil
+}
+
+// Fingerprints returns the fingerprints of a public key: the SHA-256 digest of its canonical value, which is the
+// key's entry in the search index, followed by the SHA-256 digest of the DER encoded SubjectPublicKeyInfo of each
+// key it holds
+func Fingerprints(key pki.PublicKey) ([]string, error) {
+	canonical, err := key.CanonicalValue()
+	if err != nil {
+		return nil, err
+	}
+	digest := sha256.Sum256(canonical)
+	fingerprints := []string{hex.EncodeToString(digest[:])}
+	if km, ok := key.(pki.KeyMaterial); ok {
+		for _, k := range km.CryptoPublicKeys() {
+			der, err := x509.MarshalPKIXPublicKey(k)
+			if err != nil {
+				continue
+			}
+			digest := sha256.Sum256(der)
+			fingerprints = append(fingerprints, hex.EncodeToString(digest[:]))
+		}
+	}
+	return fingerprints, nil
+}
+
+// MatchKey returns the fingerprints and identities on the watch list that the public key matches
+func (w *WatchList) MatchKey(key pki.PublicKey) (fingerprints, identities []string, err error) {
+	keyFingerprints, err := Fingerprints(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, f := range keyFingerprints {
+		if w.fingerprints[f] {
+			fingerprints = append(fingerprints, f)
+		}
+	}
+	if identifier, ok := key.(pki.Identifier); ok {
+		for _, id := range identifier.Identities() {
+			if w.identities[strings.ToLower(id)] {
+				identities = append(identities, id)
+			}
+		}
+	}
+	return fingerprints, identities, nil
+}
+
+// MatchEntry decodes the entry and returns a match if its signer is on the watch list, or nil if not. Entries of
+// kinds that do not give the signer's key are never matched. The signer's key is decoded without applying the
+// admission policy, so that entries admitted under an earlier policy are still matched.
+func (w *WatchList) MatchEntry(uuid string, entry models.LogEntryAnon) (*Match, error) {
+	body, ok := entry.Body.(string)
+	if !ok {
+		return nil, errors.New("entry body is not a string")
+	}
+	b, err := base64.StdEncoding.DecodeString(body)
+	if err != nil {
+		return nil, err
+	}
+	pe, err := models.UnmarshalProposedEntry(bytes.NewReader(b), runtime.JSONConsumer())
+	if err != nil {
+		return nil, err
+	}
+	eimpl, err := types.NewEntry(pe)
+	if err != nil {
+		return nil, err
+	}
+	keyed, ok := eimpl.(types.KeyedEntry)
+	if !ok {
+		return nil, nil
+	}
+	key, err := keyed.SignerPublicKey()
+	if err != nil {
+		return nil, err
+	}
+	fingerprints, identities, err := w.MatchKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(fingerprints) == 0 && len(identities) == 0 {
+		return nil, nil
+	}
+
+	m := &Match{
+		UUID:           uuid,
+		Kind:           pe.Kind(),
+		IntegratedTime: entry.IntegratedTime,
+		Fingerprints:   fingerprints,
+		Identities:     identities,
+	}
+	if entry.LogIndex != nil {
+		m.LogIndex = *entry.LogIndex
+	}
+	return m, nil
+}
@@ -0,0 +1,194 @@
+//
+// Copyright 2021 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitor
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+
+	"github.com/sigstore/rekor/pkg/generated/models"
+	"github.com/sigstore/rekor/pkg/pki"
+	"github.com/sigstore/rekor/pkg/pki/pgp"
+	_ "github.com/sigstore/rekor/pkg/types/rekord/v0.0.1"
+)
+
+func rekordEntry(t *testing.T, keyFile string) models.LogEntryAnon {
+	t.Helper()
+	key, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := ioutil.ReadFile("../../tests/test_file.sig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry := &models.Rekord{
+		APIVersion: swag.String("0.0.1"),
+		Spec: models.RekordV001Schema{
+			Signature: &models.RekordV001SchemaSignature{
+				Format:    pgp.FORMAT,
+				Content:   strfmt.Base64(sig),
+				PublicKey: &models.RekordV001SchemaSignaturePublicKey{Content: strfmt.Base64(key)},
+			},
+			Data: &models.RekordV001SchemaData{
+				Hash: &models.RekordV001SchemaDataHash{
+					Algorithm: swag.String(models.RekordV001SchemaDataHashAlgorithmSha256),
+					Value:     swag.String(strings.Repeat("ab", 32)),
+				},
+			},
+		},
+	}
+	b, err := json.Marshal(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return models.LogEntryAnon{
+		Body:     base64.StdEncoding.EncodeToString(b),
+		LogIndex: swag.Int64(7),
+	}
+}
+
+func TestWatchList(t *testing.T) {
+	b, err := ioutil.ReadFile("../../tests/test_public_key.key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := pki.NewArtifactFactory(pgp.FORMAT).NewPublicKey(bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fingerprints, err := Fingerprints(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fingerprints) < 2 {
+		t.Fatalf("expected canonical and key material fingerprints, got %v", fingerprints)
+	}
+	entry := rekordEntry(t, "../../tests/test_public_key.key")
+	otherEntry := rekordEntry(t, "../../pkg/pki/pgp/testdata/valid_armored_public.pgp")
+
+	tests := []struct {
+		name             string
+		fingerprints     []string
+		identities       []string
+		keys             []pki.PublicKey
+		wantErr          bool
+		wantFingerprints int
+		wantIdentities   int
+	}{
+		{name: "empty", wantErr: true},
+		{name: "invalid fingerprint", fingerprints: []string{"abc"}, wantErr: true},
+		{name: "key", keys: []pki.PublicKey{key}, wantFingerprints: 1},
+		{name: "canonical fingerprint", fingerprints: []string{strings.ToUpper(fingerprints[0])}, wantFingerprints: 1},
+		{name: "key material fingerprint", fingerprints: []string{fingerprints[1]}, wantFingerprints: 1},
+		{name: "unknown identity", identities: []string{"Identity@Example.com"}},
+		{name: "other key", fingerprints: []string{strings.Repeat("00", 32)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, err := NewWatchList(tt.fingerprints, tt.identities, tt.keys)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewWatchList() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			m, err := w.MatchEntry("uuid", entry)
+			if err != nil {
+				t.Fatalf("MatchEntry() error = %v", err)
+			}
+			if tt.wantFingerprints == 0 && tt.wantIdentities == 0 {
+				if m != nil {
+					t.Errorf("expected no match, got %+v", m)
+				}
+				return
+			}
+			if m == nil {
+				t.Fatalf("expected match")
+			}
+			if len(m.Fingerprints) != tt.wantFingerprints || len(m.Identities) != tt.wantIdentities {
+				t.Errorf("unexpected match %+v", m)
+			}
+			if m.LogIndex != 7 || m.UUID != "uuid" || m.Kind != "rekord" {
+				t.Errorf("unexpected entry details in match %+v", m)
+			}
+
+			// entries signed with other keys are not matched
+			if m, err := w.MatchEntry("other", otherEntry); err != nil || m != nil {
+				t.Errorf("expected no match for other key, got %+v, %v", m, err)
+			}
+		})
+	}
+
+	// the identities of the key are matched without regard to case
+	w, err := NewWatchList(nil, []string{"NOT@REAL.COM"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := w.MatchEntry("other", otherEntry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m == nil || len(m.Identities) != 1 {
+		t.Errorf("expected identity match, got %+v", m)
+	}
+}
+
+func TestMatchEntryIgnoresPolicy(t *testing.T) {
+	w, err := NewWatchList(nil, []string{"not@real.com"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry := rekordEntry(t, "../../pkg/pki/pgp/testdata/valid_armored_public.pgp")
+
+	// the key of an entry admitted under an earlier policy is still matched
+	pki.PolicyMap.Store(pgp.FORMAT, pki.Policy{AllowedKeyTypes: []string{"ed25519"}})
+	defer pki.PolicyMap.Delete(pgp.FORMAT)
+	m, err := w.MatchEntry("uuid", entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m == nil || len(m.Identities) != 1 {
+		t.Errorf("expected identity match, got %+v", m)
+	}
+}
+
+func TestUndecodableEntry(t *testing.T) {
+	w, err := NewWatchList(nil, []string{"not@real.com"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry := models.LogEntryAnon{
+		Body:           base64.StdEncoding.EncodeToString([]byte(`{"kind":"unknown"}`)),
+		LogIndex:       swag.Int64(3),
+		IntegratedTime: 1234,
+	}
+	_, err = w.MatchEntry("uuid", entry)
+	if err == nil {
+		t.Fatal("expected error decoding entry of unknown kind")
+	}
+	m := UndecodableEntry("uuid", entry, err)
+	if m.LogIndex != 3 || m.UUID != "uuid" || m.IntegratedTime != 1234 || m.Error == "" {
+		t.Errorf("unexpected match for undecodable entry %+v", m)
+	}
+}
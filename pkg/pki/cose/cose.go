@@ -0,0 +1,429 @@
+//
+// Copyright 2021 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cose
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+
+	// these are required to be imported for the hash functions to be available to crypto.Hash.New()
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+
+	"github.com/fxamacker/cbor/v2"
+
+	"github.com/sigstore/rekor/pkg/log"
+	"github.com/sigstore/rekor/pkg/pki"
+)
+
+// FORMAT is the name this signature format is registered under
+const FORMAT = "cose"
+
+func init() {
+	if err := pki.RegisterFormat(pki.Format{
+		Name:         FORMAT,
+		Capabilities: pki.Capabilities{},
+		NewPublicKey: func(r io.Reader) (pki.PublicKey, error) {
+			return NewPublicKey(r)
+		},
+		NewSignature: func(r io.Reader) (pki.Signature, error) {
+			return NewSignature(r)
+		},
+	}); err != nil {
+		log.Logger.Panic(err)
+	}
+}
+
+// COSE_Sign1 structures are optionally wrapped in this tag (RFC 8152 section 2)
+const sign1Tag = 18
+
+// header labels (RFC 8152 section 3.1)
+const (
+	headerAlgorithm = 1
+	headerCritical  = 2
+)
+
+// algorithm identifiers registered with IANA for use with COSE
+const (
+	algES256 = -7
+	algEdDSA = -8
+	algES384 = -35
+	algES512 = -36
+	algPS256 = -37
+	algPS384 = -38
+	algPS512 = -39
+	algRS256 = -257
+	algRS384 = -258
+	algRS512 = -259
+)
+
+var algorithmHashes = map[int64]crypto.Hash{
+	algES256: crypto.SHA256,
+	algES384: crypto.SHA384,
+	algES512: crypto.SHA512,
+	algPS256: crypto.SHA256,
+	algPS384: crypto.SHA384,
+	algPS512: crypto.SHA512,
+	algRS256: crypto.SHA256,
+	algRS384: crypto.SHA384,
+	algRS512: crypto.SHA512,
+}
+
+var (
+	decMode cbor.DecMode
+	encMode cbor.EncMode
+)
+
+func init() {
+	var err error
+	if decMode, err = (cbor.DecOptions{IntDec: cbor.IntDecConvertSigned}).DecMode(); err != nil {
+		log.Logger.Panic(err)
+	}
+	if encMode, err = cbor.CoreDetEncOptions().EncMode(); err != nil {
+		log.Logger.Panic(err)
+	}
+}
+
+// sign1Message is a COSE_Sign1 structure (RFC 8152 section 4.2)
+type sign1Message struct {
+	_           struct{} `cbor:",toarray"`
+	Protected   []byte
+	Unprotected cbor.RawMessage
+	Payload     cbor.RawMessage
+	Signature   []byte
+}
+
+// Signature is a COSE_Sign1 signature over a detached payload
+type Signature struct {
+	protected []byte
+	signature []byte
+	algorithm int64
+}
+
+var cborNull = []byte{0xf6}
+
+// NewSignature parses a (optionally tagged) COSE_Sign1 structure; the payload must be detached (i.e. nil)
+func NewSignature(r io.Reader) (*Signature, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var tag cbor.RawTag
+	if err := decMode.Unmarshal(b, &tag); err == nil {
+		if tag.Number != sign1Tag {
+			return nil, fmt.Errorf("unexpected CBOR tag %v; only COSE_Sign1 is supported", tag.Number)
+		}
+		b = tag.Content
+	}
+
+	var msg sign1Message
+	if err := decMode.Unmarshal(b, &msg); err != nil {
+		return nil, fmt.Errorf("invalid COSE_Sign1 structure: %w", err)
+	}
+	if !bytes.Equal(msg.Payload, cborNull) {
+		return nil, errors.New("COSE_Sign1 payload must be detached")
+	}
+
+	protected := map[interface{}]interface{}{}
+	if len(msg.Protected) > 0 {
+		if err := decMode.Unmarshal(msg.Protected, &protected); err != nil {
+			return nil, fmt.Errorf("invalid protected header: %w", err)
+		}
+	}
+	if _, ok := protected[int64(headerCritical)]; ok {
+		return nil, errors.New("critical header parameters are not supported")
+	}
+	alg, ok := protected[int64(headerAlgorithm)].(int64)
+	if !ok {
+		return nil, errors.New("protected header must contain an integer algorithm identifier")
+	}
+	if _, ok := algorithmHashes[alg]; !ok && alg != algEdDSA {
+		return nil, fmt.Errorf("unsupported COSE algorithm %v", alg)
+	}
+
+	// the unprotected header is not covered by the signature, so it is checked but not kept
+	unprotected := map[interface{}]interface{}{}
+	if err := decMode.Unmarshal(msg.Unprotected, &unprotected); err != nil {
+		return nil, fmt.Errorf("invalid unprotected header: %w", err)
+	}
+
+	return &Signature{
+		protected: msg.Protected,
+		signature: msg.Signature,
+		algorithm: alg,
+	}, nil
+}
+
+// CanonicalValue implements the pki.Signature interface; the signature is emitted as a tagged COSE_Sign1
+// structure with a detached payload and an empty unprotected header, so that it holds only the signed parts
+// and the same signature always yields the same value
+func (s Signature) CanonicalValue() ([]byte, error) {
+	if s.signature == nil {
+		return nil, errors.New("cose signature has not been initialized")
+	}
+	unprotected, err := encMode.Marshal(map[interface{}]interface{}{})
+	if err != nil {
+		return nil, err
+	}
+	msg, err := encMode.Marshal(sign1Message{
+		Protected:   s.protected,
+		Unprotected: unprotected,
+		Payload:     cborNull,
+		Signature:   s.signature,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return encMode.Marshal(cbor.RawTag{Number: sign1Tag, Content: msg})
+}
+
+// Verify implements the pki.Signature interface
+func (s Signature) Verify(r io.Reader, k interface{}) error {
+	if s.signature == nil {
+		return errors.New("cose signature has not been initialized")
+	}
+
+	key, ok := k.(*PublicKey)
+	if !ok {
+		return fmt.Errorf("invalid public key type for: %v", k)
+	}
+	if key.key == nil {
+		return errors.New("cose public key has not been initialized")
+	}
+
+	payload, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	// Sig_structure (RFC 8152 section 4.4), with an empty external_aad
+	toBeSigned, err := encMode.Marshal([]interface{}{"Signature1", s.protected, []byte{}, payload})
+	if err != nil {
+		return err
+	}
+
+	if s.algorithm == algEdDSA {
+		pub, ok := key.key.(ed25519.PublicKey)
+		if !ok {
+			return fmt.Errorf("algorithm %v cannot be used with key of type %T", s.algorithm, key.key)
+		}
+		if !ed25519.Verify(pub, toBeSigned, s.signature) {
+			return errors.New("cose signature verification failed")
+		}
+		return nil
+	}
+
+	hash := algorithmHashes[s.algorithm]
+	h := hash.New()
+	_, _ = h.Write(toBeSigned)
+	digest := h.Sum(nil)
+
+	switch s.algorithm {
+	case algES256, algES384, algES512:
+		pub, ok := key.key.(*ecdsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("algorithm %v cannot be used with key of type %T", s.algorithm, key.key)
+		}
+		// ECDSA signatures are the fixed length concatenation of r and s (RFC 8152 section 8.1)
+		n := (pub.Curve.Params().BitSize + 7) / 8
+		if len(s.signature) != 2*n {
+			return errors.New("cose signature verification failed: invalid ECDSA signature length")
+		}
+		r := new(big.Int).SetBytes(s.signature[:n])
+		ss := new(big.Int).SetBytes(s.signature[n:])
+		if !ecdsa.Verify(pub, digest, r, ss) {
+			return errors.New("cose signature verification failed")
+		}
+		return nil
+	case algPS256, algPS384, algPS512:
+		pub, ok := key.key.(*rsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("algorithm %v cannot be used with key of type %T", s.algorithm, key.key)
+		}
+		if err := rsa.VerifyPSS(pub, hash, digest, s.signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}); err != nil {
+			return fmt.Errorf("cose signature verification failed: %w", err)
+		}
+		return nil
+	case algRS256, algRS384, algRS512:
+		pub, ok := key.key.(*rsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("algorithm %v cannot be used with key of type %T", s.algorithm, key.key)
+		}
+		if err := rsa.VerifyPKCS1v15(pub, hash, digest, s.signature); err != nil {
+			return fmt.Errorf("cose signature verification failed: %w", err)
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported COSE algorithm %v", s.algorithm)
+}
+
+// HashAlgorithms implements the pki.HashedSignature interface; EdDSA signatures do not prehash the payload
+func (s Signature) HashAlgorithms() []string {
+	if h, ok := algorithmHashes[s.algorithm]; ok {
+		return []string{pki.HashName(h)}
+	}
+	return nil
+}
+
+// PublicKey is a public key used to verify COSE signatures
+type PublicKey struct {
+	key crypto.PublicKey
+}
+
+// COSE_Key parameters (RFC 8152 section 7 and 13)
+const (
+	keyType    = 1
+	keyCurve   = -1
+	keyX       = -2
+	keyY       = -3
+	keyRSAN    = -1
+	keyRSAE    = -2
+	ktyOKP     = 1
+	ktyEC2     = 2
+	ktyRSA     = 3
+	crvP256    = 1
+	crvP384    = 2
+	crvP521    = 3
+	crvEd25519 = 6
+)
+
+// NewPublicKey parses a public key supplied either as a CBOR encoded COSE_Key, or as a PEM encoded
+// public key or certificate
+func NewPublicKey(r io.Reader) (*PublicKey, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if block, _ := pem.Decode(b); block != nil {
+		switch block.Type {
+		case "PUBLIC KEY":
+			key, err := x509.ParsePKIXPublicKey(block.Bytes)
+			if err != nil {
+				return nil, err
+			}
+			return newPublicKey(key)
+		case "CERTIFICATE":
+			c, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, err
+			}
+			return newPublicKey(c.PublicKey)
+		}
+		return nil, fmt.Errorf("unsupported PEM block type '%v'", block.Type)
+	}
+
+	key, err := parseCOSEKey(b)
+	if err != nil {
+		return nil, err
+	}
+	return newPublicKey(key)
+}
+
+func parseCOSEKey(b []byte) (crypto.PublicKey, error) {
+	var m map[interface{}]interface{}
+	if err := decMode.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("public key must be a COSE_Key or PEM encoded: %w", err)
+	}
+	param := func(label int64) []byte {
+		v, _ := m[label].([]byte)
+		return v
+	}
+
+	kty, _ := m[int64(keyType)].(int64)
+	crv, _ := m[int64(keyCurve)].(int64)
+	switch kty {
+	case ktyOKP:
+		x := param(keyX)
+		if crv != crvEd25519 || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid COSE_Key: only Ed25519 OKP keys are supported")
+		}
+		return ed25519.PublicKey(x), nil
+	case ktyEC2:
+		pub := &ecdsa.PublicKey{
+			X: new(big.Int).SetBytes(param(keyX)),
+			Y: new(big.Int).SetBytes(param(keyY)),
+		}
+		switch crv {
+		case crvP256:
+			pub.Curve = elliptic.P256()
+		case crvP384:
+			pub.Curve = elliptic.P384()
+		case crvP521:
+			pub.Curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("invalid COSE_Key: unsupported curve %v", crv)
+		}
+		if !pub.Curve.IsOnCurve(pub.X, pub.Y) {
+			return nil, errors.New("invalid COSE_Key: point is not on curve")
+		}
+		return pub, nil
+	case ktyRSA:
+		n, e := param(keyRSAN), param(keyRSAE)
+		if len(n) == 0 || len(e) == 0 || len(e) > 4 {
+			return nil, errors.New("invalid COSE_Key: malformed RSA key")
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	}
+	return nil, fmt.Errorf("invalid COSE_Key: unsupported key type %v", kty)
+}
+
+func newPublicKey(key crypto.PublicKey) (*PublicKey, error) {
+	switch key.(type) {
+	case *rsa.PublicKey, *ecdsa.PublicKey, ed25519.PublicKey:
+		return &PublicKey{key: key}, nil
+	}
+	return nil, fmt.Errorf("unsupported public key type %T", key)
+}
+
+// CanonicalValue implements the pki.PublicKey interface; keys are always emitted as PEM encoded
+// public keys so that the same key supplied as a COSE_Key or in PEM form yields the same value
+func (k PublicKey) CanonicalValue() ([]byte, error) {
+	if k.key == nil {
+		return nil, errors.New("cose public key has not been initialized")
+	}
+	b, err := x509.MarshalPKIXPublicKey(k.key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  "PUBLIC KEY",
+		Bytes: b,
+	}), nil
+}
+
+// CryptoPublicKeys implements the pki.KeyMaterial interface
+func (k PublicKey) CryptoPublicKeys() []crypto.PublicKey {
+	if k.key == nil {
+		return nil
+	}
+	return []crypto.PublicKey{k.key}
+}
@@ -0,0 +1,200 @@
+//
+// Copyright 2021 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cose
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+func mustMarshal(t *testing.T, v interface{}) []byte {
+	t.Helper()
+	b, err := encMode.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// sign1 produces a tagged COSE_Sign1 structure with a detached payload
+func sign1(t *testing.T, alg int64, sign func([]byte) []byte, payload []byte) []byte {
+	t.Helper()
+	protected := mustMarshal(t, map[int64]int64{headerAlgorithm: alg})
+	toBeSigned := mustMarshal(t, []interface{}{"Signature1", protected, []byte{}, payload})
+	msg := mustMarshal(t, []interface{}{protected, map[int64]string{4: "firmware-key"}, nil, sign(toBeSigned)})
+	return mustMarshal(t, cbor.RawTag{Number: sign1Tag, Content: msg})
+}
+
+func TestSignatureRoundTrip(t *testing.T) {
+	payload := []byte("firmware image v1.2.3")
+
+	ecPriv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edPub, edPriv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	es256 := func(tbs []byte) []byte {
+		digest := sha256.Sum256(tbs)
+		r, s, err := ecdsa.Sign(rand.Reader, ecPriv, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig := make([]byte, 64)
+		r.FillBytes(sig[:32])
+		s.FillBytes(sig[32:])
+		return sig
+	}
+	eddsa := func(tbs []byte) []byte {
+		return ed25519.Sign(edPriv, tbs)
+	}
+
+	ecCOSEKey := mustMarshal(t, map[int64]interface{}{
+		keyType:  ktyEC2,
+		keyCurve: crvP256,
+		keyX:     ecPriv.X.FillBytes(make([]byte, 32)),
+		keyY:     ecPriv.Y.FillBytes(make([]byte, 32)),
+	})
+	edCOSEKey := mustMarshal(t, map[int64]interface{}{
+		keyType:  ktyOKP,
+		keyCurve: crvEd25519,
+		keyX:     []byte(edPub),
+	})
+	der, err := x509.MarshalPKIXPublicKey(&ecPriv.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+	tests := []struct {
+		name   string
+		sig    []byte
+		key    []byte
+		others [][]byte
+	}{
+		{name: "ES256 with COSE_Key", sig: sign1(t, algES256, es256, payload), key: ecCOSEKey, others: [][]byte{edCOSEKey}},
+		{name: "ES256 with PEM", sig: sign1(t, algES256, es256, payload), key: ecPEM, others: [][]byte{edCOSEKey}},
+		{name: "EdDSA with COSE_Key", sig: sign1(t, algEdDSA, eddsa, payload), key: edCOSEKey, others: [][]byte{ecPEM}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			key, err := NewPublicKey(bytes.NewReader(tc.key))
+			if err != nil {
+				t.Fatal(err)
+			}
+			sig, err := NewSignature(bytes.NewReader(tc.sig))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := sig.Verify(bytes.NewReader(payload), key); err != nil {
+				t.Errorf("unexpected error verifying signature: %v", err)
+			}
+			if err := sig.Verify(strings.NewReader("tampered"), key); err == nil {
+				t.Error("expected error verifying different payload")
+			}
+			for _, o := range tc.others {
+				otherKey, err := NewPublicKey(bytes.NewReader(o))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := sig.Verify(bytes.NewReader(payload), otherKey); err == nil {
+					t.Error("expected error verifying with the wrong key")
+				}
+			}
+
+			cv, err := sig.CanonicalValue()
+			if err != nil {
+				t.Fatal(err)
+			}
+			canonicalSig, err := NewSignature(bytes.NewReader(cv))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := canonicalSig.Verify(bytes.NewReader(payload), key); err != nil {
+				t.Errorf("unexpected error verifying canonical signature: %v", err)
+			}
+
+			// the unprotected header is not signed, and so is not part of the canonical value
+			var tag cbor.RawTag
+			if err := decMode.Unmarshal(cv, &tag); err != nil {
+				t.Fatal(err)
+			}
+			var msg sign1Message
+			if err := decMode.Unmarshal(tag.Content, &msg); err != nil {
+				t.Fatal(err)
+			}
+			msg.Unprotected = mustMarshal(t, map[int64]string{4: "another-key"})
+			relabelled, err := NewSignature(bytes.NewReader(mustMarshal(t, msg)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			relabelledCV, err := relabelled.CanonicalValue()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(cv, relabelledCV) {
+				t.Errorf("canonical value depends on the unprotected header")
+			}
+		})
+	}
+
+	// the same key supplied as a COSE_Key or in PEM form must canonicalize identically
+	coseKey, _ := NewPublicKey(bytes.NewReader(ecCOSEKey))
+	pemKey, _ := NewPublicKey(bytes.NewReader(ecPEM))
+	coseCV, _ := coseKey.CanonicalValue()
+	pemCV, _ := pemKey.CanonicalValue()
+	if !bytes.Equal(coseCV, pemCV) {
+		t.Errorf("canonical values of COSE_Key and PEM keys differ:\n%s\n%s", coseCV, pemCV)
+	}
+}
+
+func TestNewSignatureErrors(t *testing.T) {
+	protected := mustMarshal(t, map[int64]int64{headerAlgorithm: algES256})
+	for name, input := range map[string][]byte{
+		"attached payload":      mustMarshal(t, []interface{}{protected, map[int64]int64{}, []byte("payload"), []byte("sig")}),
+		"unsupported algorithm": mustMarshal(t, []interface{}{mustMarshal(t, map[int64]int64{headerAlgorithm: -65535}), map[int64]int64{}, nil, []byte("sig")}),
+		"missing algorithm":     mustMarshal(t, []interface{}{[]byte{}, map[int64]int64{}, nil, []byte("sig")}),
+		"critical headers":      mustMarshal(t, []interface{}{mustMarshal(t, map[int64]interface{}{headerAlgorithm: algES256, headerCritical: []int64{99}}), map[int64]int64{}, nil, []byte("sig")}),
+		"COSE_Sign":             mustMarshal(t, cbor.RawTag{Number: 98, Content: mustMarshal(t, []interface{}{protected, map[int64]int64{}, nil, []interface{}{}})}),
+		"not CBOR":              []byte("not a signature"),
+	} {
+		if _, err := NewSignature(bytes.NewReader(input)); err == nil {
+			t.Errorf("%v: expected error", name)
+		}
+	}
+
+	var s Signature
+	if _, err := s.CanonicalValue(); err == nil {
+		t.Error("expected error canonicalizing uninitialized signature")
+	}
+	if _, err := NewPublicKey(bytes.NewReader(mustMarshal(t, map[int64]interface{}{keyType: 4, -1: []byte("secret")}))); err == nil {
+		t.Error("expected error for symmetric COSE_Key")
+	}
+}
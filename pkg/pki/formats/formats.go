@@ -0,0 +1,28 @@
+//
+// Copyright 2021 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package formats registers every signature format implemented in this repository with the pki package; it is
+// imported for its side effects, so that a new format only needs to be added here to be supported everywhere
+package formats
+
+import (
+	_ "github.com/sigstore/rekor/pkg/pki/cose"     // registers the cose format
+	_ "github.com/sigstore/rekor/pkg/pki/jws"      // registers the jws format
+	_ "github.com/sigstore/rekor/pkg/pki/minisign" // registers the minisign format
+	_ "github.com/sigstore/rekor/pkg/pki/pgp"      // registers the pgp format
+	_ "github.com/sigstore/rekor/pkg/pki/pkcs7"    // registers the pkcs7 format
+	_ "github.com/sigstore/rekor/pkg/pki/ssh"      // registers the ssh format
+	_ "github.com/sigstore/rekor/pkg/pki/x509"     // registers the x509 format
+)
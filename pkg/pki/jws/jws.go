@@ -0,0 +1,295 @@
+//
+// Copyright 2021 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jws
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	jose "gopkg.in/square/go-jose.v2"
+
+	"github.com/sigstore/rekor/pkg/log"
+	"github.com/sigstore/rekor/pkg/pki"
+)
+
+// FORMAT is the name this signature format is registered under
+const FORMAT = "jws"
+
+func init() {
+	if err := pki.RegisterFormat(pki.Format{
+		Name:         FORMAT,
+		Capabilities: pki.Capabilities{},
+		NewPublicKey: func(r io.Reader) (pki.PublicKey, error) {
+			return NewPublicKey(r)
+		},
+		NewSignature: func(r io.Reader) (pki.Signature, error) {
+			return NewSignature(r)
+		},
+	}); err != nil {
+		log.Logger.Panic(err)
+	}
+}
+
+// rawSignature holds one signature of a JSON Web Signature, in the encoding used by the JSON serialization
+type rawSignature struct {
+	Protected string          `json:"protected,omitempty"`
+	Header    json.RawMessage `json:"header,omitempty"`
+	Signature string          `json:"signature"`
+}
+
+// rawJWS is the JSON serialization of a JSON Web Signature; both the flattened and general syntax are accepted
+type rawJWS struct {
+	Payload    *string         `json:"payload,omitempty"`
+	Protected  string          `json:"protected,omitempty"`
+	Header     json.RawMessage `json:"header,omitempty"`
+	Signature  string          `json:"signature,omitempty"`
+	Signatures []rawSignature  `json:"signatures,omitempty"`
+}
+
+// Signature is a JSON Web Signature (RFC 7515) over a detached payload, made with a single key. Only the
+// integrity protected parts of the signature are kept; the unprotected header can be changed by anyone without
+// invalidating the signature.
+type Signature struct {
+	protected string
+	signature string
+}
+
+// NewSignature parses a JSON Web Signature in either the compact or JSON serialization. The payload
+// must be detached, i.e. empty in the compact serialization or absent in the JSON serialization, and there
+// must be exactly one signature, whose algorithm is given in the protected header.
+func NewSignature(r io.Reader) (*Signature, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	input := strings.TrimSpace(string(b))
+
+	var sig rawSignature
+	if strings.HasPrefix(input, "{") {
+		var raw rawJWS
+		if err := json.Unmarshal([]byte(input), &raw); err != nil {
+			return nil, fmt.Errorf("invalid JWS JSON serialization: %w", err)
+		}
+		if raw.Payload != nil && *raw.Payload != "" {
+			return nil, errors.New("JWS payload must be detached")
+		}
+		switch {
+		case raw.Signature != "" && len(raw.Signatures) > 0:
+			return nil, errors.New("invalid JWS JSON serialization: both flattened and general syntax used")
+		case raw.Signature != "":
+			sig = rawSignature{Protected: raw.Protected, Header: raw.Header, Signature: raw.Signature}
+		case len(raw.Signatures) == 0:
+			return nil, errors.New("JWS does not contain any signatures")
+		case len(raw.Signatures) > 1:
+			return nil, fmt.Errorf("JWS contains %d signatures; only a single signature is accepted", len(raw.Signatures))
+		default:
+			sig = raw.Signatures[0]
+		}
+	} else {
+		parts := strings.Split(input, ".")
+		if len(parts) != 3 {
+			return nil, errors.New("invalid JWS compact serialization: must have three parts")
+		}
+		if parts[1] != "" {
+			return nil, errors.New("JWS payload must be detached")
+		}
+		sig = rawSignature{Protected: parts[0], Signature: parts[2]}
+	}
+
+	if len(sig.Header) > 0 {
+		var header map[string]interface{}
+		if err := json.Unmarshal(sig.Header, &header); err != nil {
+			return nil, fmt.Errorf("invalid JWS header: %w", err)
+		}
+	}
+
+	// ensure the protected header and algorithm can be understood without the unprotected header, which is not
+	// kept, before accepting the signature
+	s := Signature{protected: sig.Protected, signature: sig.Signature}
+	jws, err := s.parse()
+	if err != nil {
+		return nil, err
+	}
+	alg := jose.SignatureAlgorithm(jws.Signatures[0].Header.Algorithm)
+	if _, ok := algorithmHashes[alg]; !ok && alg != jose.EdDSA {
+		return nil, fmt.Errorf("unsupported JWS algorithm %v in protected header", alg)
+	}
+	return &s, nil
+}
+
+// parse returns the go-jose representation of the signature, with an empty payload
+func (s Signature) parse() (*jose.JSONWebSignature, error) {
+	jws, err := jose.ParseSigned(fmt.Sprintf("%s..%s", s.protected, s.signature))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWS: %w", err)
+	}
+	return jws, nil
+}
+
+// CanonicalValue implements the pki.Signature interface; the signature is emitted in the compact serialization,
+// which leaves out the unprotected header
+func (s Signature) CanonicalValue() ([]byte, error) {
+	if s.signature == "" {
+		return nil, errors.New("jws signature has not been initialized")
+	}
+	return []byte(fmt.Sprintf("%s..%s", s.protected, s.signature)), nil
+}
+
+// Verify implements the pki.Signature interface
+func (s Signature) Verify(r io.Reader, k interface{}) error {
+	if s.signature == "" {
+		return errors.New("jws signature has not been initialized")
+	}
+
+	key, ok := k.(*PublicKey)
+	if !ok {
+		return fmt.Errorf("invalid public key type for: %v", k)
+	}
+	if key.key == nil {
+		return errors.New("jws public key has not been initialized")
+	}
+
+	payload, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	jws, err := s.parse()
+	if err != nil {
+		return err
+	}
+	if err := jws.DetachedVerify(payload, key.key); err != nil {
+		return fmt.Errorf("jws signature verification failed: %w", err)
+	}
+	return nil
+}
+
+// hashes used by each JWS algorithm (RFC 7518 section 3.1); EdDSA signatures do not prehash the payload. HMAC
+// algorithms are not listed, as a MAC cannot be verified with a public key.
+var algorithmHashes = map[jose.SignatureAlgorithm]crypto.Hash{
+	jose.RS256: crypto.SHA256,
+	jose.RS384: crypto.SHA384,
+	jose.RS512: crypto.SHA512,
+	jose.PS256: crypto.SHA256,
+	jose.PS384: crypto.SHA384,
+	jose.PS512: crypto.SHA512,
+	jose.ES256: crypto.SHA256,
+	jose.ES384: crypto.SHA384,
+	jose.ES512: crypto.SHA512,
+}
+
+// HashAlgorithms implements the pki.HashedSignature interface
+func (s Signature) HashAlgorithms() []string {
+	jws, err := s.parse()
+	if err != nil {
+		return nil
+	}
+	var hashes []string
+	for _, sig := range jws.Signatures {
+		if h, ok := algorithmHashes[jose.SignatureAlgorithm(sig.Header.Algorithm)]; ok {
+			hashes = append(hashes, pki.HashName(h))
+		}
+	}
+	return hashes
+}
+
+// PublicKey is a public key used to verify JSON Web Signatures
+type PublicKey struct {
+	key crypto.PublicKey
+}
+
+// NewPublicKey parses a public key supplied either as a JSON Web Key (RFC 7517), or as a PEM encoded
+// public key or certificate
+func NewPublicKey(r io.Reader) (*PublicKey, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	trimmed := bytes.TrimSpace(b)
+	if bytes.HasPrefix(trimmed, []byte("{")) {
+		var jwk jose.JSONWebKey
+		if err := jwk.UnmarshalJSON(trimmed); err != nil {
+			return nil, fmt.Errorf("invalid JWK: %w", err)
+		}
+		if !jwk.Valid() || !jwk.IsPublic() {
+			return nil, errors.New("JWK must contain a valid public key")
+		}
+		return newPublicKey(jwk.Key)
+	}
+
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, errors.New("public key must be a JWK or PEM encoded")
+	}
+	switch block.Type {
+	case "PUBLIC KEY":
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return newPublicKey(key)
+	case "CERTIFICATE":
+		c, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return newPublicKey(c.PublicKey)
+	}
+	return nil, fmt.Errorf("unsupported PEM block type '%v'", block.Type)
+}
+
+func newPublicKey(key crypto.PublicKey) (*PublicKey, error) {
+	switch key.(type) {
+	case *rsa.PublicKey, *ecdsa.PublicKey, ed25519.PublicKey:
+		return &PublicKey{key: key}, nil
+	}
+	return nil, fmt.Errorf("unsupported public key type %T", key)
+}
+
+// CanonicalValue implements the pki.PublicKey interface; keys are always emitted as PEM encoded
+// public keys so that the same key supplied as a JWK or in PEM form yields the same value
+func (k PublicKey) CanonicalValue() ([]byte, error) {
+	if k.key == nil {
+		return nil, errors.New("jws public key has not been initialized")
+	}
+	b, err := x509.MarshalPKIXPublicKey(k.key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  "PUBLIC KEY",
+		Bytes: b,
+	}), nil
+}
+
+// CryptoPublicKeys implements the pki.KeyMaterial interface
+func (k PublicKey) CryptoPublicKeys() []crypto.PublicKey {
+	if k.key == nil {
+		return nil
+	}
+	return []crypto.PublicKey{k.key}
+}
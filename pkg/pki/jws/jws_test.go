@@ -0,0 +1,233 @@
+//
+// Copyright 2021 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jws
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"testing"
+
+	jose "gopkg.in/square/go-jose.v2"
+)
+
+func signDetached(t *testing.T, priv *ecdsa.PrivateKey, payload []byte) (string, string) {
+	t.Helper()
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.ES256, Key: priv}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj, err := signer.Sign(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	compact, err := obj.DetachedCompactSerialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := strings.Split(compact, ".")
+	flattened := fmt.Sprintf(`{"protected": %q, "header": {"kid": "release-key"}, "signature": %q}`, parts[0], parts[2])
+	return compact, flattened
+}
+
+func TestSignatureRoundTrip(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload := []byte(`{"release": "v1.2.3"}`)
+	compact, flattened := signDetached(t, priv, payload)
+
+	der, err := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pemKey := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	jwk, err := json.Marshal(jose.JSONWebKey{Key: &priv.PublicKey, KeyID: "release-key"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherJWK, err := json.Marshal(jose.JSONWebKey{Key: &other.PublicKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pemPub, err := NewPublicKey(bytes.NewReader(pemKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwkPub, err := NewPublicKey(bytes.NewReader(jwk))
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherPub, err := NewPublicKey(bytes.NewReader(otherJWK))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// keys in either representation must canonicalize identically so that they can be searched for
+	pemCV, _ := pemPub.CanonicalValue()
+	jwkCV, _ := jwkPub.CanonicalValue()
+	if !bytes.Equal(pemCV, jwkCV) {
+		t.Errorf("canonical values of PEM and JWK keys differ:\n%s\n%s", pemCV, jwkCV)
+	}
+
+	for name, input := range map[string]string{"compact": compact, "flattened": flattened} {
+		t.Run(name, func(t *testing.T) {
+			sig, err := NewSignature(strings.NewReader(input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, key := range []*PublicKey{pemPub, jwkPub} {
+				if err := sig.Verify(bytes.NewReader(payload), key); err != nil {
+					t.Errorf("unexpected error verifying signature: %v", err)
+				}
+			}
+			if err := sig.Verify(bytes.NewReader(payload), otherPub); err == nil {
+				t.Error("expected error verifying with the wrong key")
+			}
+			if err := sig.Verify(strings.NewReader("tampered"), pemPub); err == nil {
+				t.Error("expected error verifying a different payload")
+			}
+
+			cv, err := sig.CanonicalValue()
+			if err != nil {
+				t.Fatal(err)
+			}
+			canonicalSig, err := NewSignature(bytes.NewReader(cv))
+			if err != nil {
+				t.Fatalf("unable to parse canonical value %s: %v", cv, err)
+			}
+			if err := canonicalSig.Verify(bytes.NewReader(payload), pemPub); err != nil {
+				t.Errorf("unexpected error verifying canonical signature: %v", err)
+			}
+		})
+	}
+}
+
+func TestCanonicalValueIgnoresUnprotectedParts(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	compact, _ := signDetached(t, priv, []byte("payload"))
+	parts := strings.Split(compact, ".")
+
+	inputs := []string{
+		compact,
+		fmt.Sprintf(`{"protected": %q, "signature": %q}`, parts[0], parts[2]),
+		fmt.Sprintf(`{"protected": %q, "header": {"kid": "release-key"}, "signature": %q}`, parts[0], parts[2]),
+		fmt.Sprintf(`{"protected": %q, "header": {"kid": "another-key", "x": [1, 2]}, "signature": %q}`, parts[0], parts[2]),
+		fmt.Sprintf(`{"signatures": [{"protected": %q, "header": {"kid": "release-key"}, "signature": %q}]}`, parts[0], parts[2]),
+	}
+	for _, input := range inputs {
+		sig, err := NewSignature(strings.NewReader(input))
+		if err != nil {
+			t.Fatalf("%v: %v", input, err)
+		}
+		cv, err := sig.CanonicalValue()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(cv) != compact {
+			t.Errorf("%v: expected canonical value %v, got %s", input, compact, cv)
+		}
+	}
+
+	// junk signatures cannot be logged alongside a valid one
+	_, junk := signDetached(t, priv, []byte("other payload"))
+	var junkSig rawSignature
+	if err := json.Unmarshal([]byte(junk), &junkSig); err != nil {
+		t.Fatal(err)
+	}
+	junkSig.Signature = base64.RawURLEncoding.EncodeToString([]byte("junk"))
+	multi, err := json.Marshal(rawJWS{Signatures: []rawSignature{{Protected: parts[0], Signature: parts[2]}, junkSig}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewSignature(bytes.NewReader(multi)); err == nil {
+		t.Error("expected error for a JWS with more than one signature")
+	}
+
+	// the algorithm cannot be given only in the unprotected header, which is not kept
+	noAlg := base64.RawURLEncoding.EncodeToString([]byte(`{"kid": "release-key"}`))
+	if _, err := NewSignature(strings.NewReader(fmt.Sprintf(`{"protected": %q, "header": {"alg": "ES256"}, "signature": %q}`, noAlg, parts[2]))); err == nil {
+		t.Error("expected error for an algorithm given only in the unprotected header")
+	}
+}
+
+func TestNewSignatureErrors(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.ES256, Key: priv}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj, err := signer.Sign([]byte("attached"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	attached, err := obj.CompactSerialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	macSigner, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.HS256, Key: []byte("a shared secret of 32 bytes.....")}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	macObj, err := macSigner.Sign([]byte("detached"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mac, err := macObj.DetachedCompactSerialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, input := range map[string]string{
+		"attached compact payload": attached,
+		"attached JSON payload":    obj.FullSerialize(),
+		"two parts":                "abc.def",
+		"no signatures":            `{"signatures": []}`,
+		"not JSON":                 `{"protected": `,
+		"HMAC":                     mac,
+	} {
+		if _, err := NewSignature(strings.NewReader(input)); err == nil {
+			t.Errorf("%v: expected error", name)
+		}
+	}
+
+	var s Signature
+	if _, err := s.CanonicalValue(); err == nil {
+		t.Error("expected error canonicalizing uninitialized signature")
+	}
+	if _, err := NewPublicKey(strings.NewReader(`{"kty": "oct", "k": "c2VjcmV0"}`)); err == nil {
+		t.Error("expected error for symmetric JWK")
+	}
+}
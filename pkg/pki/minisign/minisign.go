@@ -17,6 +17,7 @@ package minisign
 
 import (
 	"bytes"
+	"crypto"
 	"crypto/ed25519"
 	"encoding/base64"
 	"fmt"
@@ -25,8 +26,40 @@ import (
 	"strings"
 
 	minisign "github.com/jedisct1/go-minisign"
+	"golang.org/x/crypto/blake2b"
+
+	"github.com/sigstore/rekor/pkg/log"
+	"github.com/sigstore/rekor/pkg/pki"
+)
+
+// FORMAT is the name this signature format is registered under
+const FORMAT = "minisign"
+
+func init() {
+	if err := pki.RegisterFormat(pki.Format{
+		Name:         FORMAT,
+		Capabilities: pki.Capabilities{},
+		NewPublicKey: func(r io.Reader) (pki.PublicKey, error) {
+			return NewPublicKey(r)
+		},
+		NewSignature: func(r io.Reader) (pki.Signature, error) {
+			return NewSignature(r)
+		},
+	}); err != nil {
+		log.Logger.Panic(err)
+	}
+}
+
+var (
+	// legacyAlgorithm signatures are computed over the message itself; this is the only algorithm used by signify
+	legacyAlgorithm = [2]byte{'E', 'd'}
+	// prehashedAlgorithm signatures are computed over the BLAKE2b-512 digest of the message; this is the
+	// default for minisign 0.10 and later
+	prehashedAlgorithm = [2]byte{'E', 'D'}
 )
 
+const trustedCommentPrefix = "trusted comment: "
+
 // Signature Signature that follows the minisign standard; supports both minisign and signify generated signatures
 type Signature struct {
 	signature *minisign.Signature
@@ -43,7 +76,11 @@ func NewSignature(r io.Reader) (*Signature, error) {
 
 	inputString := inputBuffer.String()
 	signature, err := minisign.DecodeSignature(inputString)
-	if err != nil {
+	if err == nil {
+		if !strings.HasPrefix(signature.TrustedComment, trustedCommentPrefix) {
+			return nil, fmt.Errorf("invalid signature provided: unexpected format for the trusted comment")
+		}
+	} else {
 		// try to parse as signify
 		lines := strings.Split(strings.TrimRight(inputString, "\n"), "\n")
 		if len(lines) != 2 {
@@ -61,10 +98,34 @@ func NewSignature(r io.Reader) (*Signature, error) {
 		copy(signature.Signature[:], sigBytes[10:])
 	}
 
+	if signature.SignatureAlgorithm != legacyAlgorithm && signature.SignatureAlgorithm != prehashedAlgorithm {
+		return nil, fmt.Errorf("invalid signature provided: unsupported signature algorithm '%s'", signature.SignatureAlgorithm[:])
+	}
+
 	s.signature = &signature
 	return &s, nil
 }
 
+// hasTrustedComment returns true if the signature was produced by minisign and therefore carries a
+// trusted comment and a global signature; signify signatures carry neither
+func (s Signature) hasTrustedComment() bool {
+	return s.signature.TrustedComment != ""
+}
+
+// TrustedComment returns the comment covered by the global signature, or an empty string if the signature
+// does not carry one
+func (s Signature) TrustedComment() string {
+	if s.signature == nil {
+		return ""
+	}
+	return strings.TrimPrefix(s.signature.TrustedComment, trustedCommentPrefix)
+}
+
+// Prehashed returns true if the signature was computed over the BLAKE2b-512 digest of the message
+func (s Signature) Prehashed() bool {
+	return s.signature != nil && s.signature.SignatureAlgorithm == prehashedAlgorithm
+}
+
 // CanonicalValue implements the pki.Signature interface
 func (s Signature) CanonicalValue() ([]byte, error) {
 	if s.signature == nil {
@@ -82,6 +143,11 @@ func (s Signature) CanonicalValue() ([]byte, error) {
 	if _, err := buf.WriteString(base64.StdEncoding.EncodeToString(b64Buf.Bytes())); err != nil {
 		return nil, fmt.Errorf("error canonicalizing minisign signature: %w", err)
 	}
+	if s.hasTrustedComment() {
+		if _, err := fmt.Fprintf(buf, "\n%v\n%v\n", s.signature.TrustedComment, base64.StdEncoding.EncodeToString(s.signature.GlobalSignature[:])); err != nil {
+			return nil, fmt.Errorf("error canonicalizing minisign signature: %w", err)
+		}
+	}
 	return buf.Bytes(), nil
 }
 
@@ -98,16 +164,46 @@ func (s Signature) Verify(r io.Reader, k interface{}) error {
 	if key.key == nil {
 		return fmt.Errorf("minisign public key has not been initialized")
 	}
+	pk := ed25519.PublicKey(key.key.PublicKey[:])
 
-	msg, err := ioutil.ReadAll(r)
-	if err != nil {
-		return fmt.Errorf("error reading message to verify signature: %w", err)
+	var msg []byte
+	if s.Prehashed() {
+		h, err := blake2b.New512(nil)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(h, r); err != nil {
+			return fmt.Errorf("error reading message to verify signature: %w", err)
+		}
+		msg = h.Sum(nil)
+	} else {
+		var err error
+		msg, err = ioutil.ReadAll(r)
+		if err != nil {
+			return fmt.Errorf("error reading message to verify signature: %w", err)
+		}
 	}
 
-	if !ed25519.Verify(ed25519.PublicKey(key.key.PublicKey[:]), msg, s.signature.Signature[:]) {
+	if !ed25519.Verify(pk, msg, s.signature.Signature[:]) {
 		return fmt.Errorf("verification of signed message failed")
 	}
 
+	if s.hasTrustedComment() {
+		global := append(append([]byte{}, s.signature.Signature[:]...), s.TrustedComment()...)
+		if !ed25519.Verify(pk, global, s.signature.GlobalSignature[:]) {
+			return fmt.Errorf("verification of trusted comment failed")
+		}
+	}
+
+	return nil
+}
+
+// HashAlgorithms implements the pki.HashedSignature interface; legacy signatures are computed over the
+// message itself and so do not use a hash function
+func (s Signature) HashAlgorithms() []string {
+	if s.Prehashed() {
+		return []string{pki.HashName(crypto.BLAKE2b_512)}
+	}
 	return nil
 }
 
@@ -148,3 +244,11 @@ func (k PublicKey) CanonicalValue() ([]byte, error) {
 	b64Key := base64.StdEncoding.EncodeToString(k.key.PublicKey[:])
 	return []byte(b64Key), nil
 }
+
+// CryptoPublicKeys implements the pki.KeyMaterial interface
+func (k PublicKey) CryptoPublicKeys() []crypto.PublicKey {
+	if k.key == nil {
+		return nil
+	}
+	return []crypto.PublicKey{ed25519.PublicKey(k.key.PublicKey[:])}
+}
@@ -17,12 +17,19 @@ package minisign
 
 import (
 	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
 	"errors"
+	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
+	"strings"
 	"testing"
 
 	"go.uber.org/goleak"
+	"golang.org/x/crypto/blake2b"
 )
 
 func TestMain(m *testing.M) {
@@ -306,3 +313,85 @@ func TestVerifySignature(t *testing.T) {
 		t.Errorf("expected error when using non key to verify")
 	}
 }
+
+// signMinisign produces a signature in the format emitted by minisign; the message is prehashed
+// with BLAKE2b-512 if algorithm is "ED"
+func signMinisign(t *testing.T, priv ed25519.PrivateKey, keyID []byte, algorithm string, msg []byte, comment string) string {
+	t.Helper()
+	if algorithm == "ED" {
+		digest := blake2b.Sum512(msg)
+		msg = digest[:]
+	}
+	sig := ed25519.Sign(priv, msg)
+	global := ed25519.Sign(priv, append(append([]byte{}, sig...), comment...))
+
+	sigLine := append(append([]byte(algorithm), keyID...), sig...)
+	return fmt.Sprintf("untrusted comment: signature from minisign secret key\n%v\ntrusted comment: %v\n%v\n",
+		base64.StdEncoding.EncodeToString(sigLine), comment, base64.StdEncoding.EncodeToString(global))
+}
+
+func TestVerifyTrustedComment(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyID := []byte("01234567")
+	keyLine := append(append([]byte("Ed"), keyID...), pub...)
+	key, err := NewPublicKey(strings.NewReader("untrusted comment: minisign public key\n" + base64.StdEncoding.EncodeToString(keyLine)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg, err := ioutil.ReadFile("testdata/hello_world.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	const comment = "timestamp:1610131681\tfile:hello_world.txt\thashed"
+
+	for _, algorithm := range []string{"Ed", "ED"} {
+		t.Run(algorithm, func(t *testing.T) {
+			sigStr := signMinisign(t, priv, keyID, algorithm, msg, comment)
+			sig, err := NewSignature(strings.NewReader(sigStr))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sig.Prehashed() != (algorithm == "ED") {
+				t.Errorf("Prehashed() = %v for algorithm %v", sig.Prehashed(), algorithm)
+			}
+			if err := sig.Verify(bytes.NewReader(msg), key); err != nil {
+				t.Fatalf("unexpected error verifying signature: %v", err)
+			}
+			if err := sig.Verify(strings.NewReader("other data"), key); err == nil {
+				t.Error("expected error verifying signature over different data")
+			}
+
+			// the trusted comment must survive canonicalization and still verify
+			cv, err := sig.CanonicalValue()
+			if err != nil {
+				t.Fatal(err)
+			}
+			canonicalSig, err := NewSignature(bytes.NewReader(cv))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if canonicalSig.TrustedComment() != comment {
+				t.Errorf("TrustedComment() = %q, want %q", canonicalSig.TrustedComment(), comment)
+			}
+			if err := canonicalSig.Verify(bytes.NewReader(msg), key); err != nil {
+				t.Errorf("unexpected error verifying canonical signature: %v", err)
+			}
+
+			tampered, err := NewSignature(strings.NewReader(strings.Replace(sigStr, "timestamp:1610131681", "timestamp:1710131681", 1)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := tampered.Verify(bytes.NewReader(msg), key); err == nil {
+				t.Error("expected error verifying signature with tampered trusted comment")
+			}
+		})
+	}
+
+	if _, err := NewSignature(strings.NewReader(signMinisign(t, priv, keyID, "XX", msg, comment))); err == nil {
+		t.Error("expected error for unsupported signature algorithm")
+	}
+}
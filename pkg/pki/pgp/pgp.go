@@ -19,20 +19,53 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"crypto"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
-	"net/http"
+	"strings"
+	"time"
 
 	"golang.org/x/crypto/openpgp"
 	"golang.org/x/crypto/openpgp/armor"
+	pgperrors "golang.org/x/crypto/openpgp/errors"
 	"golang.org/x/crypto/openpgp/packet"
+
+	"github.com/sigstore/rekor/pkg/log"
+	"github.com/sigstore/rekor/pkg/pki"
+	"github.com/sigstore/rekor/pkg/util"
 )
 
+// FORMAT is the name this signature format is registered under
+const FORMAT = "pgp"
+
+func init() {
+	if err := pki.RegisterFormat(pki.Format{
+		Name: FORMAT,
+		Capabilities: pki.Capabilities{
+			Identity: true,
+		},
+		NewPublicKey: func(r io.Reader) (pki.PublicKey, error) {
+			return NewPublicKey(r)
+		},
+		NewSignature: func(r io.Reader) (pki.Signature, error) {
+			return NewSignature(r)
+		},
+	}); err != nil {
+		log.Logger.Panic(err)
+	}
+}
+
 // Signature Signature that follows the PGP standard; supports both armored & binary detached signatures
 type Signature struct {
-	isArmored bool
-	signature []byte
+	// the binary encoding of the signature packet
+	signature    []byte
+	issuerKeyID  uint64
+	hash         crypto.Hash
+	creationTime time.Time
+	// fingerprint of the (sub)key that verified the signature; set by Verify
+	signerFingerprint []byte
 }
 
 // NewSignature creates and validates a PGP signature object
@@ -49,65 +82,67 @@ func NewSignature(r io.Reader) (*Signature, error) {
 	var sigReader io.Reader
 	sigBlock, err := armor.Decode(sigByteReader)
 	if err == nil {
-		s.isArmored = true
 		if sigBlock.Type != openpgp.SignatureType {
 			return nil, fmt.Errorf("invalid PGP signature provided")
 		}
 		sigReader = sigBlock.Body
 	} else {
-		s.isArmored = false
 		if _, err := sigByteReader.Seek(0, io.SeekStart); err != nil {
 			return nil, fmt.Errorf("unable to read binary PGP signature: %w", err)
 		}
 		sigReader = sigByteReader
 	}
 
-	sigPktReader := packet.NewReader(sigReader)
+	// keep the binary encoding of the signature so that the canonical value is independent of the input encoding
+	var sigBuffer bytes.Buffer
+	sigPktReader := packet.NewReader(io.TeeReader(sigReader, &sigBuffer))
 	sigPkt, err := sigPktReader.Next()
 	if err != nil {
 		return nil, fmt.Errorf("invalid PGP signature: %w", err)
 	}
 
-	if _, ok := sigPkt.(*packet.Signature); !ok {
-		if _, ok := sigPkt.(*packet.SignatureV3); !ok {
-			return nil, fmt.Errorf("valid PGP signature was not detected")
+	switch sig := sigPkt.(type) {
+	case *packet.Signature:
+		if sig.IssuerKeyId == nil {
+			return nil, errors.New("PGP signature does not identify the signing key")
 		}
+		s.issuerKeyID = *sig.IssuerKeyId
+		s.hash = sig.Hash
+		s.creationTime = sig.CreationTime
+	case *packet.SignatureV3:
+		s.issuerKeyID = sig.IssuerKeyId
+		s.hash = sig.Hash
+		s.creationTime = sig.CreationTime
+	default:
+		return nil, fmt.Errorf("valid PGP signature was not detected")
 	}
 
-	s.signature = inputBuffer.Bytes()
+	s.signature = sigBuffer.Bytes()
 	return &s, nil
 }
 
 // FetchSignature implements pki.Signature interface
 func FetchSignature(ctx context.Context, url string) (*Signature, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("error initializing fetch for PGP signature: %w", err)
-	}
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	rc, err := util.FileOrURLReadCloser(ctx, url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching PGP signature: %w", err)
 	}
-	defer resp.Body.Close()
+	defer rc.Close()
 
-	sig, err := NewSignature(resp.Body)
+	sig, err := NewSignature(rc)
 	if err != nil {
 		return nil, err
 	}
 	return sig, nil
 }
 
-// CanonicalValue implements the pki.Signature interface
+// CanonicalValue implements the pki.Signature interface; once the signature has been verified, the
+// canonical value records the fingerprint of the signing key and the time the signature was created
 func (s Signature) CanonicalValue() ([]byte, error) {
 	if len(s.signature) == 0 {
 		return nil, fmt.Errorf("PGP signature has not been initialized")
 	}
 
-	if s.isArmored {
-		return s.signature, nil
-	}
-
 	var canonicalBuffer bytes.Buffer
 	// Use an inner function so we can defer the Close()
 	if err := func() error {
@@ -125,11 +160,31 @@ func (s Signature) CanonicalValue() ([]byte, error) {
 		return nil, err
 	}
 
-	return canonicalBuffer.Bytes(), nil
+	if s.signerFingerprint == nil {
+		return canonicalBuffer.Bytes(), nil
+	}
+
+	// armor.Encode writes headers in map iteration order, so we insert them after the armor
+	// start line ourselves to keep the canonical value deterministic
+	armored := canonicalBuffer.Bytes()
+	startLine := bytes.IndexByte(armored, '\n') + 1
+	var result bytes.Buffer
+	result.Write(armored[:startLine])
+	fmt.Fprintf(&result, "%s: %s\n", FingerprintHeader, strings.ToUpper(hex.EncodeToString(s.signerFingerprint)))
+	fmt.Fprintf(&result, "%s: %s\n", CreationTimeHeader, s.creationTime.UTC().Format(time.RFC3339))
+	result.Write(armored[startLine:])
+	return result.Bytes(), nil
 }
 
-// Verify implements the pki.Signature interface
-func (s Signature) Verify(r io.Reader, k interface{}) error {
+// armor headers included in the canonical value of a verified signature
+const (
+	FingerprintHeader  = "Signing-Key-Fingerprint"
+	CreationTimeHeader = "Signature-Creation-Time"
+)
+
+// Verify implements the pki.Signature interface; in addition to checking the signature itself, it checks that
+// the signing key (or subkey) was bound, unexpired, unrevoked and permitted to sign when the signature was created
+func (s *Signature) Verify(r io.Reader, k interface{}) error {
 	if len(s.signature) == 0 {
 		return fmt.Errorf("PGP signature has not been initialized")
 	}
@@ -142,15 +197,135 @@ func (s Signature) Verify(r io.Reader, k interface{}) error {
 		return fmt.Errorf("PGP public key has not been initialized")
 	}
 
-	verifyFn := openpgp.CheckDetachedSignature
-	if s.isArmored {
-		verifyFn = openpgp.CheckArmoredDetachedSignature
+	candidates := key.key.KeysById(s.issuerKeyID)
+	if len(candidates) == 0 {
+		return pgperrors.ErrUnknownIssuer
+	}
+	var validKeys keyRing
+	var invalidErr error
+	for _, candidate := range candidates {
+		if err := validAt(candidate, s.creationTime); err != nil {
+			invalidErr = err
+			continue
+		}
+		validKeys = append(validKeys, candidate)
+	}
+	if len(validKeys) == 0 {
+		return fmt.Errorf("signing key %X was not valid at signature creation time %v: %w", s.issuerKeyID, s.creationTime.UTC(), invalidErr)
 	}
 
-	if _, err := verifyFn(key.key, r, bytes.NewReader(s.signature)); err != nil {
+	signer, err := openpgp.CheckDetachedSignature(validKeys, r, bytes.NewReader(s.signature))
+	if err != nil {
 		return err
 	}
 
+	for _, vk := range validKeys {
+		if vk.Entity == signer {
+			s.signerFingerprint = vk.PublicKey.Fingerprint[:]
+			break
+		}
+	}
+	return nil
+}
+
+// HashAlgorithms implements the pki.HashedSignature interface
+func (s Signature) HashAlgorithms() []string {
+	return []string{pki.HashName(s.hash)}
+}
+
+// SigningTime implements the pki.SigningTimer interface
+func (s Signature) SigningTime() (time.Time, bool) {
+	return s.creationTime, !s.creationTime.IsZero()
+}
+
+// validAt checks that the key was permitted to create signatures at the specified time
+func validAt(k openpgp.Key, t time.Time) error {
+	if k.PublicKey.CreationTime.After(t) {
+		return errors.New("key was created after the signature")
+	}
+
+	for _, rev := range k.Entity.Revocations {
+		if revokedAt(rev, t) {
+			return errors.New("primary key has been revoked")
+		}
+	}
+	// the primary key must not have expired, whether it signed directly or through a subkey
+	for _, ident := range k.Entity.Identities {
+		if ident.SelfSignature != nil && ident.SelfSignature.KeyExpired(t) {
+			return errors.New("primary key had expired")
+		}
+	}
+
+	if k.PublicKey == k.Entity.PrimaryKey {
+		if k.SelfSignature != nil && k.SelfSignature.FlagsValid && !k.SelfSignature.FlagSign {
+			return errors.New("primary key is not permitted to sign")
+		}
+		return nil
+	}
+
+	// for subkeys, the signature in the key is either the binding signature or a revocation
+	binding := k.SelfSignature
+	if binding == nil {
+		return errors.New("subkey is not bound to primary key")
+	}
+	if binding.SigType == packet.SigTypeSubkeyRevocation {
+		if revokedAt(binding, t) {
+			return errors.New("subkey has been revoked")
+		}
+		// a soft revocation after the signature was made doesn't invalidate it, but we no longer have the
+		// binding signature to check the rest of the properties against
+		return nil
+	}
+	if binding.CreationTime.After(t) {
+		return errors.New("subkey was not bound to primary key when the signature was created")
+	}
+	if binding.KeyExpired(t) {
+		return errors.New("subkey had expired")
+	}
+	if binding.FlagsValid && !binding.FlagSign {
+		return errors.New("subkey is not permitted to sign")
+	}
+	return nil
+}
+
+// revokedAt determines whether a revocation applies to a signature made at the specified time; revocations
+// stating the key was superseded or retired only apply to later signatures, while all others (including
+// compromise, or no reason given) apply to every signature made by the key
+func revokedAt(rev *packet.Signature, t time.Time) bool {
+	if rev.RevocationReason != nil {
+		switch *rev.RevocationReason {
+		case revocationReasonSuperseded, revocationReasonRetired:
+			return !rev.CreationTime.After(t)
+		}
+	}
+	return true
+}
+
+// reason codes for revocation signatures as defined in RFC 4880 section 5.2.3.23
+const (
+	revocationReasonSuperseded = 1
+	revocationReasonRetired    = 3
+)
+
+// keyRing is a set of keys that implements the openpgp.KeyRing interface; method names are dictated by that interface
+type keyRing []openpgp.Key
+
+func (kr keyRing) KeysById(id uint64) []openpgp.Key { //nolint:golint
+	var keys []openpgp.Key
+	for _, k := range kr {
+		if k.PublicKey.KeyId == id {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+func (kr keyRing) KeysByIdUsage(id uint64, requiredUsage byte) []openpgp.Key { //nolint:golint
+	// usage is checked when the keyring is built
+	return kr.KeysById(id)
+}
+
+func (kr keyRing) DecryptionKeys() []openpgp.Key {
 	return nil
 }
 
@@ -222,18 +397,13 @@ func NewPublicKey(r io.Reader) (*PublicKey, error) {
 // FetchPublicKey implements pki.PublicKey interface
 func FetchPublicKey(ctx context.Context, url string) (*PublicKey, error) {
 	//TODO: detect if url is hkp and adjust accordingly
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	rc, err := util.FileOrURLReadCloser(ctx, url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching PGP public key: %w", err)
 	}
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("error fetching PGP public key: %w", err)
-	}
-	defer resp.Body.Close()
+	defer rc.Close()
 
-	key, err := NewPublicKey(resp.Body)
+	key, err := NewPublicKey(rc)
 	if err != nil {
 		return nil, err
 	}
@@ -270,6 +440,38 @@ func (k PublicKey) CanonicalValue() ([]byte, error) {
 	return canonicalBuffer.Bytes(), nil
 }
 
+// Identities implements the pki.Identifier interface; the user IDs of each key, and the email addresses in them, are
+// returned
+func (k PublicKey) Identities() []string {
+	var identities []string
+	for _, e := range k.key {
+		for _, ident := range e.Identities {
+			identities = append(identities, ident.Name)
+			if ident.UserId != nil && ident.UserId.Email != "" {
+				identities = append(identities, ident.UserId.Email)
+			}
+		}
+	}
+	return identities
+}
+
+// CryptoPublicKeys implements the pki.KeyMaterial interface; the primary keys and any subkeys that
+// can be used to create signatures are returned
+func (k PublicKey) CryptoPublicKeys() []crypto.PublicKey {
+	var keys []crypto.PublicKey
+	for _, e := range k.key {
+		if e.PrimaryKey != nil {
+			keys = append(keys, e.PrimaryKey.PublicKey)
+		}
+		for _, sk := range e.Subkeys {
+			if sk.PublicKey != nil && sk.PublicKey.PubKeyAlgo.CanSign() {
+				keys = append(keys, sk.PublicKey.PublicKey)
+			}
+		}
+	}
+	return keys
+}
+
 func (k PublicKey) KeyRing() (openpgp.KeyRing, error) {
 	if k.key == nil {
 		return nil, errors.New("PGP public key has not been initialized")
@@ -18,15 +18,23 @@ package pgp
 import (
 	"bytes"
 	"context"
+	"crypto"
+	"encoding/hex"
 	"errors"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"go.uber.org/goleak"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/packet"
+
+	"github.com/sigstore/rekor/pkg/pki"
 )
 
 func TestMain(m *testing.M) {
@@ -410,3 +418,182 @@ func TestVerifySignature(t *testing.T) {
 		t.Errorf("expected error when using non key to verify")
 	}
 }
+
+func signAt(t *testing.T, e *openpgp.Entity, data []byte, created time.Time, h crypto.Hash) []byte {
+	t.Helper()
+	sig := &packet.Signature{
+		SigType:      packet.SigTypeBinary,
+		PubKeyAlgo:   e.PrivateKey.PubKeyAlgo,
+		Hash:         h,
+		CreationTime: created,
+		IssuerKeyId:  &e.PrimaryKey.KeyId,
+	}
+	hasher := h.New()
+	hasher.Write(data)
+	if err := sig.Sign(hasher, e.PrivateKey, nil); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := sig.Serialize(&buf); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestVerifySignatureKeyValidity(t *testing.T) {
+	data := []byte("hello world")
+
+	newEntity := func() *openpgp.Entity {
+		e, err := openpgp.NewEntity("test", "", "test@example.com", &packet.Config{RSABits: 1024})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return e
+	}
+	revoke := func(e *openpgp.Entity, at time.Time, reason uint8) *openpgp.Entity {
+		e.Revocations = append(e.Revocations, &packet.Signature{
+			SigType:          packet.SigTypeKeyRevocation,
+			CreationTime:     at,
+			RevocationReason: &reason,
+		})
+		return e
+	}
+	expire := func(e *openpgp.Entity, lifetime uint32) *openpgp.Entity {
+		for _, ident := range e.Identities {
+			ident.SelfSignature.KeyLifetimeSecs = &lifetime
+		}
+		return e
+	}
+
+	valid := newEntity()
+	created := valid.PrimaryKey.CreationTime
+
+	tests := []struct {
+		caseDesc string
+		entity   *openpgp.Entity
+		sigTime  time.Time
+		hash     crypto.Hash
+		verified bool
+	}{
+		{caseDesc: "valid key", entity: valid, sigTime: created.Add(time.Minute), hash: crypto.SHA256, verified: true},
+		{caseDesc: "signature predates key", entity: valid, sigTime: created.Add(-time.Hour), hash: crypto.SHA256, verified: false},
+		{caseDesc: "signature before expiry", entity: expire(newEntity(), 3600), sigTime: created.Add(time.Minute), hash: crypto.SHA256, verified: true},
+		{caseDesc: "signature after expiry", entity: expire(newEntity(), 3600), sigTime: created.Add(2 * time.Hour), hash: crypto.SHA256, verified: false},
+		{caseDesc: "key compromised after signature", entity: revoke(newEntity(), created.Add(time.Hour), 2), sigTime: created.Add(time.Minute), hash: crypto.SHA256, verified: false},
+		{caseDesc: "key retired after signature", entity: revoke(newEntity(), created.Add(time.Hour), 3), sigTime: created.Add(time.Minute), hash: crypto.SHA256, verified: true},
+		{caseDesc: "key retired before signature", entity: revoke(newEntity(), created.Add(time.Minute), 3), sigTime: created.Add(time.Hour), hash: crypto.SHA256, verified: false},
+	}
+
+	for _, tc := range tests {
+		k := &PublicKey{key: openpgp.EntityList{tc.entity}}
+		s, err := NewSignature(bytes.NewReader(signAt(t, tc.entity, data, tc.sigTime, tc.hash)))
+		if err != nil {
+			t.Fatalf("%v: error reading signature: %v", tc.caseDesc, err)
+		}
+		if err := s.Verify(bytes.NewReader(data), k); (err == nil) != tc.verified {
+			t.Errorf("%v: unexpected result in verifying signature: %v", tc.caseDesc, err)
+		}
+	}
+
+}
+
+func TestSignatureHashPolicy(t *testing.T) {
+	data := []byte("hello world")
+	e, err := openpgp.NewEntity("test", "", "test@example.com", &packet.Config{RSABits: 1024})
+	if err != nil {
+		t.Fatal(err)
+	}
+	created := e.PrimaryKey.CreationTime
+	sha1Sig := signAt(t, e, data, created.Add(time.Minute), crypto.SHA1)
+	sha256Sig := signAt(t, e, data, created.Add(time.Minute), crypto.SHA256)
+	factory := pki.NewArtifactFactory(FORMAT)
+
+	// the hash is checked by the admission policy, not by Verify
+	s, err := NewSignature(bytes.NewReader(sha1Sig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Verify(bytes.NewReader(data), &PublicKey{key: openpgp.EntityList{e}}); err != nil {
+		t.Errorf("unexpected error verifying SHA-1 signature: %v", err)
+	}
+
+	pki.PolicyMap.Store(pki.DefaultPolicyKey, pki.DefaultPolicy())
+	defer pki.PolicyMap.Delete(pki.DefaultPolicyKey)
+	var pe *pki.PolicyError
+	if _, err := factory.NewSignature(bytes.NewReader(sha1Sig)); !errors.As(err, &pe) {
+		t.Errorf("expected SHA-1 signature to be rejected by the default policy, got %v", err)
+	}
+	if _, err := factory.NewSignature(bytes.NewReader(sha256Sig)); err != nil {
+		t.Errorf("unexpected error for SHA-256 signature: %v", err)
+	}
+
+	pgpPolicy := pki.DefaultPolicy()
+	pgpPolicy.AllowedHashes = append(pgpPolicy.AllowedHashes, "sha1")
+	pki.PolicyMap.Store(FORMAT, pgpPolicy)
+	defer pki.PolicyMap.Delete(FORMAT)
+	if _, err := factory.NewSignature(bytes.NewReader(sha1Sig)); err != nil {
+		t.Errorf("unexpected error for SHA-1 signature once allowed: %v", err)
+	}
+}
+
+func TestCanonicalValueSignatureRecordsSigner(t *testing.T) {
+	keyFile, _ := os.Open("testdata/valid_armored_public.pgp")
+	k, err := NewPublicKey(keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sigFile, _ := os.Open("testdata/hello_world.txt.sig")
+	s, err := NewSignature(sigFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	unverified, err := s.CanonicalValue()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(unverified, []byte(FingerprintHeader)) {
+		t.Error("unverified signature should not record signer")
+	}
+
+	dataFile, _ := os.Open("testdata/hello_world.txt")
+	if err := s.Verify(dataFile, k); err != nil {
+		t.Fatal(err)
+	}
+	verified, err := s.CanonicalValue()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fingerprint := strings.ToUpper(hex.EncodeToString(k.key[0].PrimaryKey.Fingerprint[:]))
+	if !bytes.Contains(verified, []byte(FingerprintHeader+": "+fingerprint+"\n")) {
+		t.Errorf("canonical value does not record signing key fingerprint: %s", verified)
+	}
+	if !bytes.Contains(verified, []byte(CreationTimeHeader+": 2020-11-17T17:47:53Z\n")) {
+		t.Errorf("canonical value does not record signature creation time: %s", verified)
+	}
+	again, _ := s.CanonicalValue()
+	if !bytes.Equal(verified, again) {
+		t.Error("canonical value is not deterministic")
+	}
+}
+
+func TestPublicKeyIdentities(t *testing.T) {
+	file, err := os.Open("testdata/valid_armored_public.pgp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	key, err := NewPublicKey(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, id := range key.Identities() {
+		if id == "not@real.com" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected user ID not@real.com in %v", key.Identities())
+	}
+}
@@ -25,10 +25,37 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"time"
 
 	"github.com/sassoftware/relic/lib/pkcs7"
+	"github.com/sassoftware/relic/lib/x509tools"
+
+	"github.com/sigstore/rekor/pkg/log"
+	"github.com/sigstore/rekor/pkg/pki"
+	pkix509 "github.com/sigstore/rekor/pkg/pki/x509"
 )
 
+// FORMAT is the name this signature format is registered under
+const FORMAT = "pkcs7"
+
+func init() {
+	if err := pki.RegisterFormat(pki.Format{
+		Name: FORMAT,
+		Capabilities: pki.Capabilities{
+			Identity: true,
+			Chains:   true,
+		},
+		NewPublicKey: func(r io.Reader) (pki.PublicKey, error) {
+			return NewPublicKey(r)
+		},
+		NewSignature: func(r io.Reader) (pki.Signature, error) {
+			return NewSignature(r)
+		},
+	}); err != nil {
+		log.Logger.Panic(err)
+	}
+}
+
 type Signature struct {
 	signedData pkcs7.SignedData
 	detached   bool
@@ -127,6 +154,31 @@ func (s Signature) Verify(r io.Reader, k interface{}) error {
 	return nil
 }
 
+// HashAlgorithms implements the pki.HashedSignature interface
+func (s Signature) HashAlgorithms() []string {
+	var hashes []string
+	for _, si := range s.signedData.SignerInfos {
+		h, ok := x509tools.PkixDigestToHash(si.DigestAlgorithm)
+		if !ok {
+			hashes = append(hashes, si.DigestAlgorithm.Algorithm.String())
+			continue
+		}
+		hashes = append(hashes, pki.HashName(h))
+	}
+	return hashes
+}
+
+// SigningTime implements the pki.SigningTimer interface, returning the signing time authenticated attribute if present
+func (s Signature) SigningTime() (time.Time, bool) {
+	for _, si := range s.signedData.SignerInfos {
+		var t time.Time
+		if err := si.AuthenticatedAttributes.GetOne(pkcs7.OidAttributeSigningTime, &t); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
 // PublicKey Public Key contained in cert inside PKCS7 bundle
 type PublicKey struct {
 	key     crypto.PublicKey
@@ -184,3 +236,20 @@ func (k PublicKey) CanonicalValue() ([]byte, error) {
 	}
 	return buf.Bytes(), nil
 }
+
+// CryptoPublicKeys implements the pki.KeyMaterial interface
+func (k PublicKey) CryptoPublicKeys() []crypto.PublicKey {
+	if k.key == nil {
+		return nil
+	}
+	return []crypto.PublicKey{k.key}
+}
+
+// VerifyChain implements the pki.ChainVerifier interface; the first certificate in the bundle is
+// treated as the leaf and any others are used as intermediates
+func (k PublicKey) VerifyChain(roots *x509.CertPool, at time.Time) error {
+	if len(k.certs) == 0 {
+		return pki.ErrNoCertificateChain
+	}
+	return pkix509.VerifyCertChain(k.certs[0], k.certs[1:], roots, at)
+}
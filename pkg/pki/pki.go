@@ -16,15 +16,12 @@
 package pki
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"sort"
 	"strings"
-
-	"github.com/sigstore/rekor/pkg/pki/minisign"
-	"github.com/sigstore/rekor/pkg/pki/pgp"
-	"github.com/sigstore/rekor/pkg/pki/pkcs7"
-	"github.com/sigstore/rekor/pkg/pki/ssh"
-	"github.com/sigstore/rekor/pkg/pki/x509"
+	"sync"
 )
 
 // PublicKey Generic object representing a public key (regardless of format & algorithm)
@@ -38,6 +35,79 @@ type Signature interface {
 	Verify(r io.Reader, k interface{}) error
 }
 
+// AlgorithmAgileSignature is implemented by signatures that can be created with more than one
+// combination of hash function and padding scheme
+type AlgorithmAgileSignature interface {
+	Signature
+	// SetAlgorithm restricts verification to the specified algorithm; if it is not called, the
+	// algorithm is inferred while verifying the signature
+	SetAlgorithm(alg string) error
+	// Algorithm returns the algorithm used to verify the signature, or the empty string if the
+	// default algorithm for the type of key was used
+	Algorithm() string
+}
+
+// Identifier is implemented by public keys that carry identities in addition to key material, such as the email
+// addresses of a certificate or the user IDs of a PGP key
+type Identifier interface {
+	Identities() []string
+}
+
+// FormatMap stores mapping between format strings and format implementations;
+// entries are written once at process initialization (from the init() method of each format package)
+// and read for each transaction, so we use sync.Map which is optimized for this case
+var FormatMap sync.Map
+
+// Capabilities describes the optional features that a signature format supports
+type Capabilities struct {
+	DigestOnly bool // signatures can be verified given only the digest of the artifact
+	Identity   bool // public keys carry an identity (e.g. email address, certificate subject) in addition to key material
+	Chains     bool // public keys can carry a chain of certificates up to a trust root
+}
+
+// Format is the set of constructors and capabilities that each signature format registers
+type Format struct {
+	Name         string // this is the unique string that identifies the format
+	Capabilities Capabilities
+	NewPublicKey func(r io.Reader) (PublicKey, error)
+	NewSignature func(r io.Reader) (Signature, error)
+}
+
+// RegisterFormat makes a signature format available to the ArtifactFactory; it is intended
+// to be called from the init() method of the package implementing the format
+func RegisterFormat(f Format) error {
+	if f.Name == "" {
+		return errors.New("format name must be specified")
+	}
+	if f.NewPublicKey == nil || f.NewSignature == nil {
+		return fmt.Errorf("format '%v' must provide both public key and signature constructors", f.Name)
+	}
+	if _, loaded := FormatMap.LoadOrStore(strings.ToLower(f.Name), f); loaded {
+		return fmt.Errorf("format '%v' is already registered", f.Name)
+	}
+	return nil
+}
+
+// GetFormat returns the registered implementation for the specified format, if one exists
+func GetFormat(name string) (Format, bool) {
+	f, found := FormatMap.Load(strings.ToLower(name))
+	if !found {
+		return Format{}, false
+	}
+	return f.(Format), true
+}
+
+// SupportedFormats returns the sorted list of names of all registered formats
+func SupportedFormats() []string {
+	formats := []string{}
+	FormatMap.Range(func(k, _ interface{}) bool {
+		formats = append(formats, k.(string))
+		return true
+	})
+	sort.Strings(formats)
+	return formats
+}
+
 type ArtifactFactory struct {
 	format string
 }
@@ -48,34 +118,64 @@ func NewArtifactFactory(format string) *ArtifactFactory {
 	}
 }
 
+// Capabilities returns the capabilities of the format the factory was created for
+func (a ArtifactFactory) Capabilities() (Capabilities, error) {
+	f, err := a.lookup()
+	if err != nil {
+		return Capabilities{}, err
+	}
+	return f.Capabilities, nil
+}
+
+// NewPublicKey parses a public key in the format the factory was created for; keys that are not
+// admitted by the policy configured for the format are rejected with a *PolicyError
 func (a ArtifactFactory) NewPublicKey(r io.Reader) (PublicKey, error) {
-	switch strings.ToLower(a.format) {
-	case "pgp":
-		return pgp.NewPublicKey(r)
-	case "minisign":
-		return minisign.NewPublicKey(r)
-	case "x509":
-		return x509.NewPublicKey(r)
-	case "ssh":
-		return ssh.NewPublicKey(r)
-	case "pkcs7":
-		return pkcs7.NewPublicKey(r)
+	f, err := a.lookup()
+	if err != nil {
+		return nil, err
+	}
+	k, err := f.NewPublicKey(r)
+	if err != nil {
+		return nil, err
+	}
+	if err := CheckPublicKey(f.Name, k); err != nil {
+		return nil, err
 	}
-	return nil, fmt.Errorf("unknown key format '%v'", a.format)
+	return k, nil
 }
 
+// ParsePublicKey parses a public key in the format the factory was created for without applying the admission
+// policy; it is intended for keys that were admitted when they were added to the log, under what may since have
+// become a stricter policy
+func (a ArtifactFactory) ParsePublicKey(r io.Reader) (PublicKey, error) {
+	f, err := a.lookup()
+	if err != nil {
+		return nil, err
+	}
+	return f.NewPublicKey(r)
+}
+
+// NewSignature parses a signature in the format the factory was created for; signatures that are not
+// admitted by the policy configured for the format are rejected with a *PolicyError
 func (a ArtifactFactory) NewSignature(r io.Reader) (Signature, error) {
-	switch strings.ToLower(a.format) {
-	case "pgp":
-		return pgp.NewSignature(r)
-	case "minisign":
-		return minisign.NewSignature(r)
-	case "x509":
-		return x509.NewSignature(r)
-	case "ssh":
-		return ssh.NewSignature(r)
-	case "pkcs7":
-		return pkcs7.NewSignature(r)
+	f, err := a.lookup()
+	if err != nil {
+		return nil, err
+	}
+	s, err := f.NewSignature(r)
+	if err != nil {
+		return nil, err
+	}
+	if err := CheckSignature(f.Name, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (a ArtifactFactory) lookup() (Format, error) {
+	f, found := GetFormat(a.format)
+	if !found {
+		return Format{}, fmt.Errorf("unknown key format '%v'; supported formats are %v", a.format, SupportedFormats())
 	}
-	return nil, fmt.Errorf("unknown key format '%v'", a.format)
+	return f, nil
 }
@@ -13,13 +13,25 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package pki
+package pki_test
 
 import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"io"
 	"os"
 	"testing"
+	"time"
 
 	"go.uber.org/goleak"
+
+	"github.com/sigstore/rekor/pkg/pki"
+	_ "github.com/sigstore/rekor/pkg/pki/formats"
 )
 
 func TestMain(m *testing.M) {
@@ -87,7 +99,7 @@ func TestFactoryNewKey(t *testing.T) {
 	for _, tc := range testCases {
 		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
-			factory := NewArtifactFactory(tc.format)
+			factory := pki.NewArtifactFactory(tc.format)
 			keyFile, _ := os.Open(tc.keyFile)
 			_, newKeyErr := factory.NewPublicKey(keyFile)
 
@@ -106,3 +118,109 @@ func TestFactoryNewKey(t *testing.T) {
 		})
 	}
 }
+
+func TestRegisterFormat(t *testing.T) {
+	newKey := func(r io.Reader) (pki.PublicKey, error) { return nil, nil }
+	newSig := func(r io.Reader) (pki.Signature, error) { return nil, nil }
+
+	if err := pki.RegisterFormat(pki.Format{NewPublicKey: newKey, NewSignature: newSig}); err == nil {
+		t.Error("expected error registering format without name")
+	}
+	if err := pki.RegisterFormat(pki.Format{Name: "incomplete", NewPublicKey: newKey}); err == nil {
+		t.Error("expected error registering format without signature constructor")
+	}
+	if err := pki.RegisterFormat(pki.Format{Name: "PGP", NewPublicKey: newKey, NewSignature: newSig}); err == nil {
+		t.Error("expected error registering duplicate format")
+	}
+
+	caps, err := pki.NewArtifactFactory("pkcs7").Capabilities()
+	if err != nil {
+		t.Fatalf("unexpected error fetching capabilities: %v", err)
+	}
+	if !caps.Chains || !caps.Identity {
+		t.Errorf("unexpected capabilities for pkcs7: %+v", caps)
+	}
+	if _, err := pki.NewArtifactFactory("bogus").Capabilities(); err == nil {
+		t.Error("expected error fetching capabilities of unknown format")
+	}
+}
+
+type chainKey struct {
+	err error
+}
+
+func (c chainKey) CanonicalValue() ([]byte, error) {
+	return nil, nil
+}
+
+func (c chainKey) VerifyChain(roots *x509.CertPool, at time.Time) error {
+	return c.err
+}
+
+// bareKey is a key type that cannot carry a certificate chain
+type bareKey struct{}
+
+func (b bareKey) CanonicalValue() ([]byte, error) {
+	return nil, nil
+}
+
+func TestVerifyTrust(t *testing.T) {
+	defer pki.TrustRootsMap.Delete("test")
+	untrusted := chainKey{err: errors.New("untrusted")}
+
+	if err := pki.VerifyTrust("test", untrusted, nil, time.Now()); err != nil {
+		t.Errorf("unexpected error with no trust roots configured: %v", err)
+	}
+
+	pki.TrustRootsMap.Store("test", x509.NewCertPool())
+	if err := pki.VerifyTrust("test", untrusted, nil, time.Now()); err == nil {
+		t.Error("expected error for untrusted chain")
+	}
+	if err := pki.VerifyTrust("test", chainKey{}, nil, time.Now()); err != nil {
+		t.Errorf("unexpected error for trusted chain: %v", err)
+	}
+	if err := pki.VerifyTrust("test", chainKey{err: pki.ErrNoCertificateChain}, nil, time.Now()); !errors.Is(err, pki.ErrNoCertificateChain) {
+		t.Errorf("expected error for key without chain, got %v", err)
+	}
+	if err := pki.VerifyTrust("test", bareKey{}, nil, time.Now()); !errors.Is(err, pki.ErrNoCertificateChain) {
+		t.Errorf("expected error for key type that cannot carry a chain, got %v", err)
+	}
+
+	if err := pki.SetUntrustedRootPolicy(pki.FlagUntrustedRoots); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = pki.SetUntrustedRootPolicy(pki.RejectUntrustedRoots)
+	}()
+	if err := pki.VerifyTrust("test", untrusted, nil, time.Now()); err != nil {
+		t.Errorf("unexpected error for untrusted chain when flagging: %v", err)
+	}
+	if err := pki.VerifyTrust("test", bareKey{}, nil, time.Now()); err != nil {
+		t.Errorf("unexpected error for key without chain when flagging: %v", err)
+	}
+	if err := pki.SetUntrustedRootPolicy("bogus"); err == nil {
+		t.Error("expected error setting unknown policy")
+	}
+}
+
+func TestVerifyTrustBareX509Key(t *testing.T) {
+	defer pki.TrustRootsMap.Delete("test")
+	pki.TrustRootsMap.Store("test", x509.NewCertPool())
+
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(priv.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	key, err := pki.NewArtifactFactory("x509").NewPublicKey(bytes.NewReader(keyPEM))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pki.VerifyTrust("test", key, nil, time.Now()); !errors.Is(err, pki.ErrNoCertificateChain) {
+		t.Errorf("expected bare x509 public key to be rejected when trust roots are configured, got %v", err)
+	}
+}
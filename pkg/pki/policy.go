@@ -0,0 +1,180 @@
+//
+// Copyright 2021 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pki
+
+import (
+	"crypto"
+	"crypto/dsa" // #nosec G505 only used to identify (and reject) DSA keys
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Policy restricts the key material and algorithms that are admitted for a signature format; an empty
+// list or a zero value places no restriction on the corresponding property
+type Policy struct {
+	MinRSABits      int      // minimum size of RSA moduli, in bits
+	AllowedKeyTypes []string // any of rsa, ecdsa, ed25519, dsa
+	AllowedCurves   []string // names of the elliptic curves that ECDSA keys may use, e.g. P-256
+	AllowedHashes   []string // names of the hash functions that signatures may use, e.g. sha256
+}
+
+// DefaultPolicy returns the policy recommended for all formats
+func DefaultPolicy() Policy {
+	return Policy{
+		MinRSABits:      2048,
+		AllowedKeyTypes: []string{"rsa", "ecdsa", "ed25519"},
+		AllowedCurves:   []string{"P-256", "P-384", "P-521"},
+		AllowedHashes:   []string{"sha256", "sha384", "sha512", "blake2b-512"},
+	}
+}
+
+// DefaultPolicyKey is the key under which the policy applying to all formats is stored
+const DefaultPolicyKey = "default"
+
+// PolicyMap stores the admission policy configured for each format; the policy stored under
+// DefaultPolicyKey is used for any format that does not have its own policy configured
+var PolicyMap sync.Map
+
+// PolicyForFormat returns the policy that applies to the specified format, if one has been configured
+func PolicyForFormat(format string) (Policy, bool) {
+	if p, found := PolicyMap.Load(strings.ToLower(format)); found {
+		return p.(Policy), true
+	}
+	if p, found := PolicyMap.Load(DefaultPolicyKey); found {
+		return p.(Policy), true
+	}
+	return Policy{}, false
+}
+
+// PolicyError is returned when key material or a signature is not admitted by the configured policy
+type PolicyError struct {
+	Format string
+	Reason string
+}
+
+func (e *PolicyError) Error() string {
+	return fmt.Sprintf("%v not permitted by policy for format '%v'", e.Reason, e.Format)
+}
+
+// KeyMaterial is implemented by public keys to expose the underlying keys for policy checks
+type KeyMaterial interface {
+	CryptoPublicKeys() []crypto.PublicKey
+}
+
+// HashedSignature is implemented by signatures to expose the hash functions they were created with
+// for policy checks; a signature that does not (yet) know its hash function returns an empty list
+type HashedSignature interface {
+	HashAlgorithms() []string
+}
+
+// HashName returns the name used in policies for the specified hash function
+func HashName(h crypto.Hash) string {
+	switch h {
+	case crypto.MD5:
+		return "md5"
+	case crypto.SHA1:
+		return "sha1"
+	case crypto.SHA224:
+		return "sha224"
+	case crypto.SHA256:
+		return "sha256"
+	case crypto.SHA384:
+		return "sha384"
+	case crypto.SHA512:
+		return "sha512"
+	case crypto.RIPEMD160:
+		return "ripemd160"
+	case crypto.BLAKE2b_512:
+		return "blake2b-512"
+	}
+	return strings.ToLower(h.String())
+}
+
+// CheckPublicKey checks the key material against the policy configured for the format
+func CheckPublicKey(format string, k PublicKey) error {
+	p, found := PolicyForFormat(format)
+	if !found {
+		return nil
+	}
+	km, ok := k.(KeyMaterial)
+	if !ok {
+		return nil
+	}
+	for _, key := range km.CryptoPublicKeys() {
+		if reason := p.checkKey(key); reason != "" {
+			return &PolicyError{Format: format, Reason: reason}
+		}
+	}
+	return nil
+}
+
+// CheckSignature checks the hash functions used by the signature against the policy configured for the format
+func CheckSignature(format string, s Signature) error {
+	p, found := PolicyForFormat(format)
+	if !found || len(p.AllowedHashes) == 0 {
+		return nil
+	}
+	hs, ok := s.(HashedSignature)
+	if !ok {
+		return nil
+	}
+	for _, h := range hs.HashAlgorithms() {
+		if !contains(p.AllowedHashes, h) {
+			return &PolicyError{Format: format, Reason: fmt.Sprintf("hash algorithm '%v'", h)}
+		}
+	}
+	return nil
+}
+
+func (p Policy) checkKey(key crypto.PublicKey) string {
+	var keyType string
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		keyType = "rsa"
+		if p.MinRSABits > 0 && k.N.BitLen() < p.MinRSABits {
+			return fmt.Sprintf("%v-bit RSA key (minimum is %v bits)", k.N.BitLen(), p.MinRSABits)
+		}
+	case *ecdsa.PublicKey:
+		keyType = "ecdsa"
+		curve := k.Curve.Params().Name
+		if len(p.AllowedCurves) > 0 && !contains(p.AllowedCurves, curve) {
+			return fmt.Sprintf("ECDSA key on curve '%v'", curve)
+		}
+	case ed25519.PublicKey:
+		keyType = "ed25519"
+	case *dsa.PublicKey:
+		keyType = "dsa"
+	default:
+		keyType = fmt.Sprintf("%T", key)
+	}
+	if len(p.AllowedKeyTypes) > 0 && !contains(p.AllowedKeyTypes, keyType) {
+		return fmt.Sprintf("key type '%v'", keyType)
+	}
+	return ""
+}
+
+func contains(list []string, s string) bool {
+	for _, e := range list {
+		if strings.EqualFold(e, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+//
+// Copyright 2021 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pki_test
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/sigstore/rekor/pkg/pki"
+)
+
+type hashedSignature []string
+
+func (h hashedSignature) CanonicalValue() ([]byte, error)         { return nil, nil }
+func (h hashedSignature) Verify(r io.Reader, k interface{}) error { return nil }
+func (h hashedSignature) HashAlgorithms() []string                { return h }
+
+func pemPublicKey(t *testing.T, pub crypto.PublicKey) []byte {
+	t.Helper()
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+func TestPolicy(t *testing.T) {
+	rsa1024, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsa2048, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p224, err := ecdsa.GenerateKey(elliptic.P224(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p256, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edPub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys := map[string][]byte{
+		"rsa1024": pemPublicKey(t, &rsa1024.PublicKey),
+		"rsa2048": pemPublicKey(t, &rsa2048.PublicKey),
+		"p224":    pemPublicKey(t, &p224.PublicKey),
+		"p256":    pemPublicKey(t, &p256.PublicKey),
+		"ed25519": pemPublicKey(t, edPub),
+	}
+
+	factory := pki.NewArtifactFactory("x509")
+
+	// without a policy, all keys are admitted
+	for name, k := range keys {
+		if _, err := factory.NewPublicKey(bytes.NewReader(k)); err != nil {
+			t.Errorf("%v: unexpected error without policy: %v", name, err)
+		}
+	}
+
+	pki.PolicyMap.Store(pki.DefaultPolicyKey, pki.DefaultPolicy())
+	defer pki.PolicyMap.Delete(pki.DefaultPolicyKey)
+
+	for name, rejected := range map[string]bool{"rsa1024": true, "rsa2048": false, "p224": true, "p256": false, "ed25519": false} {
+		_, err := factory.NewPublicKey(bytes.NewReader(keys[name]))
+		var pe *pki.PolicyError
+		if errors.As(err, &pe) != rejected {
+			t.Errorf("%v: unexpected result with default policy: %v", name, err)
+		}
+	}
+
+	if err := pki.CheckSignature("x509", hashedSignature{"sha256"}); err != nil {
+		t.Errorf("unexpected error for sha256 signature: %v", err)
+	}
+	if err := pki.CheckSignature("x509", hashedSignature{"sha1"}); err == nil {
+		t.Error("expected error for sha1 signature")
+	}
+
+	// policies for a specific format take precedence over the default
+	x509Policy := pki.DefaultPolicy()
+	x509Policy.MinRSABits = 3072
+	x509Policy.AllowedKeyTypes = []string{"ecdsa"}
+	pki.PolicyMap.Store("x509", x509Policy)
+	defer pki.PolicyMap.Delete("x509")
+
+	for name, rejected := range map[string]bool{"rsa2048": true, "p256": false, "ed25519": true} {
+		_, err := factory.NewPublicKey(bytes.NewReader(keys[name]))
+		var pe *pki.PolicyError
+		if errors.As(err, &pe) != rejected {
+			t.Errorf("%v: unexpected result with x509 policy: %v", name, err)
+		}
+	}
+	if _, err := pki.NewArtifactFactory("jws").NewPublicKey(bytes.NewReader(keys["ed25519"])); err != nil {
+		t.Errorf("unexpected error for format without its own policy: %v", err)
+	}
+}
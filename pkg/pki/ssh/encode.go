@@ -24,16 +24,22 @@ import (
 )
 
 const (
-	namespace = "file"
-	pemType   = "SSH SIGNATURE"
+	defaultNamespace = "file"
+	pemType          = "SSH SIGNATURE"
 )
 
+// Armor encodes a signature created in the default namespace with the default hash algorithm
 func Armor(s *ssh.Signature, p ssh.PublicKey) []byte {
+	return ArmorWithNamespace(s, p, defaultNamespace, defaultHashAlgorithm)
+}
+
+// ArmorWithNamespace encodes a signature created in the specified namespace with the specified hash algorithm
+func ArmorWithNamespace(s *ssh.Signature, p ssh.PublicKey, namespace, hashAlg string) []byte {
 	sig := WrappedSig{
 		Version:       1,
 		PublicKey:     string(p.Marshal()),
 		Namespace:     namespace,
-		HashAlgorithm: defaultHashAlgorithm,
+		HashAlgorithm: hashAlg,
 		Signature:     string(ssh.Marshal(s)),
 	}
 
@@ -68,7 +74,7 @@ func Decode(b []byte) (*Signature, error) {
 	if string(sig.MagicHeader[:]) != magicHeader {
 		return nil, fmt.Errorf("invalid magic header: %s", sig.MagicHeader)
 	}
-	if sig.Namespace != "file" {
+	if _, ok := allowedNamespaces[sig.Namespace]; !ok {
 		return nil, fmt.Errorf("invalid signature namespace: %s", sig.Namespace)
 	}
 	if _, ok := supportedHashAlgorithms[sig.HashAlgorithm]; !ok {
@@ -91,5 +97,6 @@ func Decode(b []byte) (*Signature, error) {
 		signature: &sshSig,
 		pk:        pk,
 		hashAlg:   sig.HashAlgorithm,
+		namespace: sig.Namespace,
 	}, nil
 }
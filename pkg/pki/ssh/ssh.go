@@ -16,17 +16,43 @@
 package ssh
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
 	"fmt"
 	"io"
 	"io/ioutil"
 
 	"golang.org/x/crypto/ssh"
+
+	"github.com/sigstore/rekor/pkg/log"
+	"github.com/sigstore/rekor/pkg/pki"
 )
 
+// FORMAT is the name this signature format is registered under
+const FORMAT = "ssh"
+
+func init() {
+	if err := pki.RegisterFormat(pki.Format{
+		Name:         FORMAT,
+		Capabilities: pki.Capabilities{},
+		NewPublicKey: func(r io.Reader) (pki.PublicKey, error) {
+			return NewPublicKey(r)
+		},
+		NewSignature: func(r io.Reader) (pki.Signature, error) {
+			return NewSignature(r)
+		},
+	}); err != nil {
+		log.Logger.Panic(err)
+	}
+}
+
 type Signature struct {
 	signature *ssh.Signature
 	pk        ssh.PublicKey
 	hashAlg   string
+	namespace string
 }
 
 // NewSignature creates and Validates an ssh signature object
@@ -44,7 +70,50 @@ func NewSignature(r io.Reader) (*Signature, error) {
 
 // CanonicalValue implements the pki.Signature interface
 func (s Signature) CanonicalValue() ([]byte, error) {
-	return []byte(Armor(s.signature, s.pk)), nil
+	return ArmorWithNamespace(s.signature, s.pk, s.namespace, s.hashAlg), nil
+}
+
+// hashes used by each SSH signature algorithm to sign the wrapped message
+var signatureHashes = map[string]crypto.Hash{
+	ssh.SigAlgoRSA:        crypto.SHA1,
+	ssh.SigAlgoRSASHA2256: crypto.SHA256,
+	ssh.SigAlgoRSASHA2512: crypto.SHA512,
+	ssh.KeyAlgoDSA:        crypto.SHA1,
+	ssh.KeyAlgoECDSA256:   crypto.SHA256,
+	ssh.KeyAlgoECDSA384:   crypto.SHA384,
+	ssh.KeyAlgoECDSA521:   crypto.SHA512,
+	ssh.KeyAlgoSKECDSA256: crypto.SHA256,
+	ssh.KeyAlgoSKED25519:  crypto.SHA256,
+}
+
+// HashAlgorithms implements the pki.HashedSignature interface; this includes the hash of the message
+// as well as any hash used by the signature algorithm itself
+func (s Signature) HashAlgorithms() []string {
+	hashes := []string{s.hashAlg}
+	if s.signature != nil {
+		if h, ok := signatureHashes[s.signature.Format]; ok {
+			hashes = append(hashes, pki.HashName(h))
+		}
+	}
+	return hashes
+}
+
+// Namespace returns the namespace the signature was created in (e.g. file, git)
+func (s Signature) Namespace() string {
+	return s.namespace
+}
+
+// SecurityKeyFields returns the flags and counter reported by the FIDO security key that created
+// the signature; ok is false if the signature was not created by a security key
+func (s Signature) SecurityKeyFields() (flags byte, counter uint32, ok bool) {
+	if s.signature == nil || s.pk == nil || !isSecurityKey(s.pk) {
+		return 0, 0, false
+	}
+	var skf skFields
+	if err := ssh.Unmarshal(s.signature.Rest, &skf); err != nil {
+		return 0, 0, false
+	}
+	return skf.Flags, skf.Counter, true
 }
 
 // Verify implements the pki.Signature interface
@@ -96,3 +165,38 @@ func (k PublicKey) CanonicalValue() ([]byte, error) {
 	}
 	return ssh.MarshalAuthorizedKey(k.key), nil
 }
+
+// CryptoPublicKeys implements the pki.KeyMaterial interface
+func (k PublicKey) CryptoPublicKeys() []crypto.PublicKey {
+	if k.key == nil {
+		return nil
+	}
+	if cpk, ok := k.key.(ssh.CryptoPublicKey); ok {
+		return []crypto.PublicKey{cpk.CryptoPublicKey()}
+	}
+	// security keys do not expose the underlying key, so it is decoded from the wire format
+	switch k.key.Type() {
+	case ssh.KeyAlgoSKED25519:
+		var w struct {
+			Name        string
+			KeyBytes    []byte
+			Application string
+		}
+		if err := ssh.Unmarshal(k.key.Marshal(), &w); err == nil {
+			return []crypto.PublicKey{ed25519.PublicKey(w.KeyBytes)}
+		}
+	case ssh.KeyAlgoSKECDSA256:
+		var w struct {
+			Name        string
+			Curve       string
+			KeyBytes    []byte
+			Application string
+		}
+		if err := ssh.Unmarshal(k.key.Marshal(), &w); err == nil {
+			if x, y := elliptic.Unmarshal(elliptic.P256(), w.KeyBytes); x != nil {
+				return []crypto.PublicKey{&ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}}
+			}
+		}
+	}
+	return nil
+}
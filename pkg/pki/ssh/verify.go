@@ -16,6 +16,8 @@
 package ssh
 
 import (
+	"errors"
+	"fmt"
 	"io"
 
 	"golang.org/x/crypto/ssh"
@@ -40,11 +42,80 @@ func Verify(message io.Reader, armoredSignature []byte, publicKey []byte) error
 	hm := h.Sum(nil)
 
 	toVerify := MessageWrapper{
-		Namespace:     "file",
+		Namespace:     decodedSignature.namespace,
 		HashAlgorithm: decodedSignature.hashAlg,
 		Hash:          string(hm),
 	}
 	signedMessage := ssh.Marshal(toVerify)
 	signedMessage = append([]byte(magicHeader), signedMessage...)
-	return desiredPk.Verify(signedMessage, decodedSignature.signature)
+	if err := desiredPk.Verify(signedMessage, decodedSignature.signature); err != nil {
+		return err
+	}
+	return checkSecurityKeyFlags(desiredPk, decodedSignature.signature)
+}
+
+// flags reported by FIDO authenticators, see openssh/PROTOCOL.u2f
+const (
+	skFlagUserPresent  = 0x01
+	skFlagUserVerified = 0x04
+)
+
+// skFields holds the additional fields present in signatures made by FIDO security keys
+type skFields struct {
+	Flags   byte
+	Counter uint32
+}
+
+func isSecurityKey(pk ssh.PublicKey) bool {
+	return pk.Type() == ssh.KeyAlgoSKECDSA256 || pk.Type() == ssh.KeyAlgoSKED25519
+}
+
+// checkSecurityKeyFlags enforces the configured policy on the flags reported by a FIDO security key
+func checkSecurityKeyFlags(pk ssh.PublicKey, sig *ssh.Signature) error {
+	if !isSecurityKey(pk) {
+		return nil
+	}
+	var skf skFields
+	if err := ssh.Unmarshal(sig.Rest, &skf); err != nil {
+		return fmt.Errorf("invalid security key signature: %w", err)
+	}
+	if requireUserPresence && skf.Flags&skFlagUserPresent == 0 {
+		return errors.New("security key signature was made without user presence")
+	}
+	if requireUserVerification && skf.Flags&skFlagUserVerified == 0 {
+		return errors.New("security key signature was made without user verification")
+	}
+	return nil
+}
+
+var (
+	allowedNamespaces = map[string]struct{}{
+		"file": {},
+		"git":  {},
+	}
+	requireUserPresence     = true
+	requireUserVerification = false
+)
+
+// SetAllowedNamespaces replaces the list of namespaces that signatures may be created in
+func SetAllowedNamespaces(namespaces []string) error {
+	if len(namespaces) == 0 {
+		return errors.New("at least one signature namespace must be allowed")
+	}
+	allowed := map[string]struct{}{}
+	for _, ns := range namespaces {
+		if ns == "" {
+			return errors.New("signature namespace cannot be empty")
+		}
+		allowed[ns] = struct{}{}
+	}
+	allowedNamespaces = allowed
+	return nil
+}
+
+// SetSecurityKeyPolicy configures whether signatures made by FIDO security keys must
+// assert that the user was present and/or verified when the signature was made
+func SetSecurityKeyPolicy(userPresence, userVerification bool) {
+	requireUserPresence = userPresence
+	requireUserVerification = userVerification
 }
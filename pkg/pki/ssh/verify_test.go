@@ -0,0 +1,179 @@
+//
+// Copyright 2021 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// skEd25519Key is a software stand-in for a sk-ssh-ed25519@openssh.com key held on a FIDO authenticator
+type skEd25519Key struct {
+	priv        ed25519.PrivateKey
+	application string
+}
+
+func newSKEd25519Key(t *testing.T) *skEd25519Key {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &skEd25519Key{priv: priv, application: "ssh:"}
+}
+
+func (k *skEd25519Key) publicKey(t *testing.T) ssh.PublicKey {
+	t.Helper()
+	w := struct {
+		Name        string
+		KeyBytes    []byte
+		Application string
+	}{ssh.KeyAlgoSKED25519, []byte(k.priv.Public().(ed25519.PublicKey)), k.application}
+	pk, err := ssh.ParsePublicKey(ssh.Marshal(w))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pk
+}
+
+// sign produces an armored SSHSIG signature in the same way ssh-keygen -Y sign does with a security key
+func (k *skEd25519Key) sign(t *testing.T, data []byte, namespace string, flags byte, counter uint32) []byte {
+	t.Helper()
+	h := supportedHashAlgorithms[defaultHashAlgorithm]()
+	h.Write(data)
+	mw := ssh.Marshal(MessageWrapper{
+		Namespace:     namespace,
+		HashAlgorithm: defaultHashAlgorithm,
+		Hash:          string(h.Sum(nil)),
+	})
+	signedMessage := append([]byte(magicHeader), mw...)
+
+	appDigest := sha256.Sum256([]byte(k.application))
+	dataDigest := sha256.Sum256(signedMessage)
+	blob := ssh.Marshal(struct {
+		ApplicationDigest []byte `ssh:"rest"`
+		Flags             byte
+		Counter           uint32
+		MessageDigest     []byte `ssh:"rest"`
+	}{appDigest[:], flags, counter, dataDigest[:]})
+
+	sig := &ssh.Signature{
+		Format: ssh.KeyAlgoSKED25519,
+		Blob: ssh.Marshal(struct {
+			Signature []byte `ssh:"rest"`
+		}{ed25519.Sign(k.priv, blob)}),
+		Rest: ssh.Marshal(skFields{Flags: flags, Counter: counter}),
+	}
+	return ArmorWithNamespace(sig, k.publicKey(t), namespace, defaultHashAlgorithm)
+}
+
+func TestVerifySecurityKey(t *testing.T) {
+	data := []byte("signed with a security key")
+	key := newSKEd25519Key(t)
+	pub := ssh.MarshalAuthorizedKey(key.publicKey(t))
+
+	defer SetSecurityKeyPolicy(true, false)
+
+	for _, tt := range []struct {
+		name             string
+		flags            byte
+		userVerification bool
+		wantErr          bool
+	}{
+		{name: "user present", flags: skFlagUserPresent},
+		{name: "user not present", flags: 0, wantErr: true},
+		{name: "user verified", flags: skFlagUserPresent | skFlagUserVerified, userVerification: true},
+		{name: "user not verified", flags: skFlagUserPresent, userVerification: true, wantErr: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			SetSecurityKeyPolicy(true, tt.userVerification)
+			sig := key.sign(t, data, "file", tt.flags, 7)
+			err := Verify(bytes.NewReader(data), sig, pub)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err := Verify(strings.NewReader("other data"), sig, pub); err == nil {
+				t.Error("expected error verifying different data")
+			}
+		})
+	}
+
+	s, err := NewSignature(bytes.NewReader(key.sign(t, data, "file", skFlagUserPresent, 42)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	flags, counter, ok := s.SecurityKeyFields()
+	if !ok || flags != skFlagUserPresent || counter != 42 {
+		t.Errorf("SecurityKeyFields() = %v, %v, %v", flags, counter, ok)
+	}
+}
+
+func TestVerifyNamespaces(t *testing.T) {
+	data := []byte("my good data to be signed!")
+	key := newSKEd25519Key(t)
+	pub := ssh.MarshalAuthorizedKey(key.publicKey(t))
+
+	defer func() {
+		if err := SetAllowedNamespaces([]string{"file", "git"}); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	gitSig := key.sign(t, data, "git", skFlagUserPresent, 1)
+	if err := Verify(bytes.NewReader(data), gitSig, pub); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := NewSignature(bytes.NewReader(gitSig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Namespace() != "git" {
+		t.Errorf("Namespace() = %v, want git", s.Namespace())
+	}
+	// the canonical form must retain the namespace, otherwise it would no longer verify
+	canonical, err := s.CanonicalValue()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(bytes.NewReader(data), canonical, pub); err != nil {
+		t.Errorf("canonical signature did not verify: %v", err)
+	}
+
+	customSig := key.sign(t, data, "release@example.com", skFlagUserPresent, 2)
+	if err := Verify(bytes.NewReader(data), customSig, pub); err == nil {
+		t.Error("expected error for namespace that is not allowed")
+	}
+	if err := SetAllowedNamespaces([]string{"release@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(bytes.NewReader(data), customSig, pub); err != nil {
+		t.Error(err)
+	}
+	if err := Verify(bytes.NewReader(data), gitSig, pub); err == nil {
+		t.Error("expected error for namespace that is no longer allowed")
+	}
+
+	if err := SetAllowedNamespaces(nil); err == nil {
+		t.Error("expected error for empty namespace list")
+	}
+}
@@ -0,0 +1,149 @@
+//
+// Copyright 2021 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pki
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/sigstore/rekor/pkg/log"
+)
+
+// ErrNoCertificateChain is returned by ChainVerifier implementations when the public key
+// was supplied as bare key material rather than as a certificate
+var ErrNoCertificateChain = errors.New("public key does not carry a certificate chain")
+
+// ChainVerifier is implemented by public keys that can carry a certificate chain
+type ChainVerifier interface {
+	// VerifyChain checks that the certificate chain terminates in one of the supplied roots, that
+	// every certificate in the chain was valid at the specified time, and that the leaf certificate
+	// may be used for code signing
+	VerifyChain(roots *x509.CertPool, at time.Time) error
+}
+
+// ChainedPublicKey is implemented by public keys that can be supplied with intermediate certificates, which are not
+// part of the canonical value of the key
+type ChainedPublicKey interface {
+	// CanonicalChain returns the canonical value of the key followed by the intermediate certificates
+	CanonicalChain() ([]byte, error)
+}
+
+// CanonicalEntryValue returns the value of the key to be kept in a canonicalized entry, including any intermediate
+// certificates so that the chain can be verified again later
+func CanonicalEntryValue(k PublicKey) ([]byte, error) {
+	if chained, ok := k.(ChainedPublicKey); ok {
+		return chained.CanonicalChain()
+	}
+	return k.CanonicalValue()
+}
+
+// SigningTimer is implemented by signatures that record the time at which they were created
+type SigningTimer interface {
+	SigningTime() (time.Time, bool)
+}
+
+// UntrustedRootPolicy determines what happens to entries whose certificate chains do not
+// terminate in a configured trust root
+type UntrustedRootPolicy string
+
+const (
+	// RejectUntrustedRoots causes entries with untrusted chains to be refused
+	RejectUntrustedRoots UntrustedRootPolicy = "reject"
+	// FlagUntrustedRoots causes entries with untrusted chains to be logged, but accepted
+	FlagUntrustedRoots UntrustedRootPolicy = "flag"
+)
+
+// DefaultTrustRoots is the key under which the trust roots applying to all entry kinds are stored
+const DefaultTrustRoots = "default"
+
+// TrustRootsMap stores the pool of trust roots configured for each entry kind; the pool stored
+// under DefaultTrustRoots is used for any kind that does not have its own roots configured
+var TrustRootsMap sync.Map
+
+var untrustedRootPolicy = RejectUntrustedRoots
+
+// SetUntrustedRootPolicy configures how entries with untrusted certificate chains are handled
+func SetUntrustedRootPolicy(p UntrustedRootPolicy) error {
+	switch p {
+	case RejectUntrustedRoots, FlagUntrustedRoots:
+		untrustedRootPolicy = p
+		return nil
+	}
+	return fmt.Errorf("unknown untrusted root policy '%v'", p)
+}
+
+// TrustRootsForKind returns the trust roots that apply to the specified entry kind, or nil
+// if no trust roots have been configured
+func TrustRootsForKind(kind string) *x509.CertPool {
+	if p, found := TrustRootsMap.Load(kind); found {
+		return p.(*x509.CertPool)
+	}
+	if p, found := TrustRootsMap.Load(DefaultTrustRoots); found {
+		return p.(*x509.CertPool)
+	}
+	return nil
+}
+
+// LoadCertPool reads a file of PEM encoded certificates into a pool
+func LoadCertPool(path string) (*x509.CertPool, error) {
+	b, err := ioutil.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(b) {
+		return nil, fmt.Errorf("no certificates found in '%v'", path)
+	}
+	return pool, nil
+}
+
+// VerifyTrust checks the certificate chain carried by the key against the trust roots configured for the entry
+// kind. The chain is evaluated as of the time the signature was created if the signature records it, otherwise as
+// of the time the entry is integrated into the log. Kinds without configured trust roots are not subject to this
+// check; for kinds with trust roots, keys that do not carry a certificate chain are treated as untrusted.
+func VerifyTrust(kind string, key PublicKey, sig Signature, integratedTime time.Time) error {
+	roots := TrustRootsForKind(kind)
+	if roots == nil {
+		return nil
+	}
+
+	var err error
+	if cv, ok := key.(ChainVerifier); ok {
+		at := integratedTime
+		if st, ok := sig.(SigningTimer); ok {
+			if t, found := st.SigningTime(); found {
+				at = t
+			}
+		}
+		err = cv.VerifyChain(roots, at)
+	} else {
+		err = ErrNoCertificateChain
+	}
+
+	switch {
+	case err == nil:
+		return nil
+	case untrustedRootPolicy == FlagUntrustedRoots:
+		log.Logger.Warnf("accepting %v entry with untrusted certificate chain: %v", kind, err)
+		return nil
+	}
+	return fmt.Errorf("certificate chain could not be verified: %w", err)
+}
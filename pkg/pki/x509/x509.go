@@ -21,17 +21,106 @@ import (
 	"crypto/ecdsa"
 	"crypto/ed25519"
 	"crypto/rsa"
-	"crypto/sha256"
+	_ "crypto/sha256" // registers SHA-256 for use by crypto.Hash
+	_ "crypto/sha512" // registers SHA-384 and SHA-512 for use by crypto.Hash
 	"crypto/x509"
 	"encoding/pem"
 	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/sigstore/rekor/pkg/log"
+	"github.com/sigstore/rekor/pkg/pki"
+)
+
+// FORMAT is the name this signature format is registered under
+const FORMAT = "x509"
+
+func init() {
+	if err := pki.RegisterFormat(pki.Format{
+		Name: FORMAT,
+		Capabilities: pki.Capabilities{
+			Identity: true,
+			Chains:   true,
+		},
+		NewPublicKey: func(r io.Reader) (pki.PublicKey, error) {
+			return NewPublicKey(r)
+		},
+		NewSignature: func(r io.Reader) (pki.Signature, error) {
+			return NewSignature(r)
+		},
+	}); err != nil {
+		log.Logger.Panic(err)
+	}
+}
+
+// supported combinations of key type, hash function and (for RSA) padding scheme
+const (
+	RSAPKCS1v15SHA256 = "rsa-pkcs1v15-sha256"
+	RSAPKCS1v15SHA384 = "rsa-pkcs1v15-sha384"
+	RSAPKCS1v15SHA512 = "rsa-pkcs1v15-sha512"
+	RSAPSSSHA256      = "rsa-pss-sha256"
+	RSAPSSSHA384      = "rsa-pss-sha384"
+	RSAPSSSHA512      = "rsa-pss-sha512"
+	ECDSASHA256       = "ecdsa-sha256"
+	ECDSASHA384       = "ecdsa-sha384"
+	ECDSASHA512       = "ecdsa-sha512"
+	Ed25519           = "ed25519"
+)
+
+type algorithm struct {
+	hash crypto.Hash
+	pss  bool
+}
+
+var rsaAlgorithms = map[string]algorithm{
+	RSAPKCS1v15SHA256: {hash: crypto.SHA256},
+	RSAPKCS1v15SHA384: {hash: crypto.SHA384},
+	RSAPKCS1v15SHA512: {hash: crypto.SHA512},
+	RSAPSSSHA256:      {hash: crypto.SHA256, pss: true},
+	RSAPSSSHA384:      {hash: crypto.SHA384, pss: true},
+	RSAPSSSHA512:      {hash: crypto.SHA512, pss: true},
+}
+
+var ecdsaAlgorithms = map[string]algorithm{
+	ECDSASHA256: {hash: crypto.SHA256},
+	ECDSASHA384: {hash: crypto.SHA384},
+	ECDSASHA512: {hash: crypto.SHA512},
+}
+
+// the order in which algorithms are tried when the signature does not specify one
+var (
+	rsaInferenceOrder   = []string{RSAPKCS1v15SHA256, RSAPKCS1v15SHA384, RSAPKCS1v15SHA512, RSAPSSSHA256, RSAPSSSHA384, RSAPSSSHA512}
+	ecdsaInferenceOrder = []string{ECDSASHA256, ECDSASHA384, ECDSASHA512}
 )
 
+// certificate signature algorithms hint at the scheme that the holder of the certificate will use
+var certAlgorithmHints = map[x509.SignatureAlgorithm]string{
+	x509.SHA256WithRSA:    RSAPKCS1v15SHA256,
+	x509.SHA384WithRSA:    RSAPKCS1v15SHA384,
+	x509.SHA512WithRSA:    RSAPKCS1v15SHA512,
+	x509.SHA256WithRSAPSS: RSAPSSSHA256,
+	x509.SHA384WithRSAPSS: RSAPSSSHA384,
+	x509.SHA512WithRSAPSS: RSAPSSSHA512,
+	x509.ECDSAWithSHA256:  ECDSASHA256,
+	x509.ECDSAWithSHA384:  ECDSASHA384,
+	x509.ECDSAWithSHA512:  ECDSASHA512,
+}
+
+// the algorithms that were assumed for each key type before the algorithm could vary; signatures
+// created with these do not record the algorithm, so their canonical representation is unchanged
+var defaultAlgorithms = map[string]struct{}{
+	RSAPKCS1v15SHA256: {},
+	ECDSASHA256:       {},
+	Ed25519:           {},
+}
+
 type Signature struct {
 	signature []byte
+	algorithm string
 }
 
 // NewSignature creates and validates an x509 signature object
@@ -50,19 +139,40 @@ func (s Signature) CanonicalValue() ([]byte, error) {
 	return s.signature, nil
 }
 
-// Verify implements the pki.Signature interface
-func (s Signature) Verify(r io.Reader, k interface{}) error {
+// SetAlgorithm implements the pki.AlgorithmAgileSignature interface
+func (s *Signature) SetAlgorithm(alg string) error {
+	alg = strings.ToLower(alg)
+	if _, ok := rsaAlgorithms[alg]; !ok {
+		if _, ok := ecdsaAlgorithms[alg]; !ok && alg != Ed25519 {
+			return fmt.Errorf("unsupported x509 signature algorithm '%v'", alg)
+		}
+	}
+	s.algorithm = alg
+	return nil
+}
+
+// Algorithm implements the pki.AlgorithmAgileSignature interface; the algorithm is empty
+// until the signature has been verified if it was not explicitly set, and is also empty if
+// the default algorithm for the type of key was used
+func (s *Signature) Algorithm() string {
+	if _, ok := defaultAlgorithms[s.algorithm]; ok {
+		return ""
+	}
+	return s.algorithm
+}
+
+// Verify implements the pki.Signature interface; if the algorithm has not been set, each algorithm supported
+// by the type of key is tried (starting with the one hinted at by the certificate, if present), and the one
+// that successfully verifies the signature is recorded
+func (s *Signature) Verify(r io.Reader, k interface{}) error {
 	if len(s.signature) == 0 {
 		return fmt.Errorf("X509 signature has not been initialized")
 	}
 
-	hasher := sha256.New()
-	tee := io.TeeReader(r, hasher)
-	message, err := ioutil.ReadAll(tee)
+	message, err := ioutil.ReadAll(r)
 	if err != nil {
 		return err
 	}
-	hash := hasher.Sum(nil)
 
 	key, ok := k.(*PublicKey)
 	if !ok {
@@ -70,32 +180,110 @@ func (s Signature) Verify(r io.Reader, k interface{}) error {
 	}
 
 	p := key.key
+	var hint string
 	if p == nil {
 		p = key.cert.c.PublicKey
+		hint = certAlgorithmHints[key.cert.c.SignatureAlgorithm]
 	}
 
+	var candidates []string
 	switch pub := p.(type) {
 	case *rsa.PublicKey:
-		return rsa.VerifyPKCS1v15(pub, crypto.SHA256, hash, s.signature)
+		candidates = inferenceOrder(rsaAlgorithms, rsaInferenceOrder, hint, s.algorithm)
 	case ed25519.PublicKey:
-		if ed25519.Verify(pub, message, s.signature) {
-			return nil
+		candidates = []string{Ed25519}
+		if s.algorithm != "" && s.algorithm != Ed25519 {
+			candidates = nil
 		}
-		return errors.New("supplied signature does not match key")
 	case *ecdsa.PublicKey:
-		if ecdsa.VerifyASN1(pub, hash, s.signature) {
-			return nil
+		// prefer the hash that matches the strength of the curve
+		switch pub.Curve.Params().BitSize {
+		case 384:
+			hint = ECDSASHA384
+		case 521:
+			hint = ECDSASHA512
 		}
-		return errors.New("supplied signature does not match key")
+		candidates = inferenceOrder(ecdsaAlgorithms, ecdsaInferenceOrder, hint, s.algorithm)
 	default:
 		return fmt.Errorf("invalid public key type: %T", pub)
 	}
+	if len(candidates) == 0 {
+		return fmt.Errorf("signature algorithm '%v' cannot be used with public key of type %T", s.algorithm, p)
+	}
+
+	for _, alg := range candidates {
+		if verify(p, alg, message, s.signature) {
+			s.algorithm = alg
+			return nil
+		}
+	}
+	return errors.New("supplied signature does not match key")
+}
+
+// HashAlgorithms implements the pki.HashedSignature interface; the hash function is only known once the
+// algorithm has been set or inferred during verification
+func (s *Signature) HashAlgorithms() []string {
+	if a, ok := rsaAlgorithms[s.algorithm]; ok {
+		return []string{pki.HashName(a.hash)}
+	}
+	if a, ok := ecdsaAlgorithms[s.algorithm]; ok {
+		return []string{pki.HashName(a.hash)}
+	}
+	return nil
+}
+
+// inferenceOrder returns the algorithms to try, starting with the hinted algorithm; if an algorithm has been
+// explicitly chosen, only that algorithm is returned (as long as it is valid for the key)
+func inferenceOrder(valid map[string]algorithm, order []string, hint, chosen string) []string {
+	if chosen != "" {
+		if _, ok := valid[chosen]; ok {
+			return []string{chosen}
+		}
+		return nil
+	}
+	result := []string{}
+	if _, ok := valid[hint]; ok {
+		result = append(result, hint)
+	}
+	for _, alg := range order {
+		if alg != hint {
+			result = append(result, alg)
+		}
+	}
+	return result
+}
+
+func verify(p crypto.PublicKey, alg string, message, signature []byte) bool {
+	if alg == Ed25519 {
+		return ed25519.Verify(p.(ed25519.PublicKey), message, signature)
+	}
+
+	a, ok := rsaAlgorithms[alg]
+	if !ok {
+		a = ecdsaAlgorithms[alg]
+	}
+	hasher := a.hash.New()
+	_, _ = hasher.Write(message)
+	digest := hasher.Sum(nil)
+
+	switch pub := p.(type) {
+	case *rsa.PublicKey:
+		if a.pss {
+			return rsa.VerifyPSS(pub, a.hash, digest, signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthAuto}) == nil
+		}
+		return rsa.VerifyPKCS1v15(pub, a.hash, digest, signature) == nil
+	case *ecdsa.PublicKey:
+		return ecdsa.VerifyASN1(pub, digest, signature)
+	}
+	return false
 }
 
 // PublicKey Public Key that follows the x509 standard
 type PublicKey struct {
 	key  interface{}
 	cert *cert
+	// intermediate certificates supplied alongside the leaf certificate, if any
+	chain []*cert
 }
 
 type cert struct {
@@ -110,7 +298,7 @@ func NewPublicKey(r io.Reader) (*PublicKey, error) {
 		return nil, err
 	}
 
-	block, _ := pem.Decode(rawPub)
+	block, rest := pem.Decode(rawPub)
 	if block == nil {
 		return nil, fmt.Errorf("invalid public key: %s", string(rawPub))
 	}
@@ -127,19 +315,48 @@ func NewPublicKey(r io.Reader) (*PublicKey, error) {
 		if err != nil {
 			return nil, err
 		}
+		// any further certificates are treated as intermediates between the leaf and a trust root
+		var chain []*cert
+		for len(bytes.TrimSpace(rest)) > 0 {
+			var ib *pem.Block
+			ib, rest = pem.Decode(rest)
+			if ib == nil || ib.Type != "CERTIFICATE" {
+				return nil, errors.New("invalid certificate chain: only PEM encoded certificates may follow the leaf certificate")
+			}
+			ic, err := x509.ParseCertificate(ib.Bytes)
+			if err != nil {
+				return nil, err
+			}
+			chain = append(chain, &cert{
+				c: ic,
+				b: ib.Bytes,
+			})
+		}
 		return &PublicKey{
 			cert: &cert{
 				c: c,
 				b: block.Bytes,
-			}}, nil
+			},
+			chain: chain,
+		}, nil
 	}
 	return nil, fmt.Errorf("invalid public key: %s", string(rawPub))
 }
 
-// CanonicalValue implements the pki.PublicKey interface
+// CanonicalValue implements the pki.PublicKey interface; for certificates, only the leaf certificate is included, so
+// that the key is found by the same value whatever intermediates it was supplied with
 func (k PublicKey) CanonicalValue() ([]byte, error) {
+	return k.canonicalValue(false)
+}
+
+// CanonicalChain implements the pki.ChainedPublicKey interface
+func (k PublicKey) CanonicalChain() ([]byte, error) {
+	return k.canonicalValue(true)
+}
+
+func (k PublicKey) canonicalValue(withChain bool) ([]byte, error) {
 
-	var p pem.Block
+	var blocks []*pem.Block
 	switch {
 	case k.key != nil:
 		b, err := x509.MarshalPKIXPublicKey(k.key)
@@ -147,22 +364,93 @@ func (k PublicKey) CanonicalValue() ([]byte, error) {
 			return nil, err
 		}
 
-		p = pem.Block{
+		blocks = append(blocks, &pem.Block{
 			Type:  "PUBLIC KEY",
 			Bytes: b,
-		}
+		})
 	case k.cert != nil:
-		p = pem.Block{
+		blocks = append(blocks, &pem.Block{
 			Type:  "CERTIFICATE",
 			Bytes: k.cert.b,
+		})
+		if withChain {
+			for _, c := range k.chain {
+				blocks = append(blocks, &pem.Block{
+					Type:  "CERTIFICATE",
+					Bytes: c.b,
+				})
+			}
 		}
 	default:
 		return nil, fmt.Errorf("x509 public key has not been initialized")
 	}
 
 	var buf bytes.Buffer
-	if err := pem.Encode(&buf, &p); err != nil {
-		return nil, err
+	for _, p := range blocks {
+		if err := pem.Encode(&buf, p); err != nil {
+			return nil, err
+		}
 	}
 	return buf.Bytes(), nil
 }
+
+// Identities implements the pki.Identifier interface; the subject common name, email addresses and URIs of the
+// leaf certificate are returned, or nothing if the key was not supplied as a certificate
+func (k PublicKey) Identities() []string {
+	if k.cert == nil {
+		return nil
+	}
+	var identities []string
+	if cn := k.cert.c.Subject.CommonName; cn != "" {
+		identities = append(identities, cn)
+	}
+	identities = append(identities, k.cert.c.EmailAddresses...)
+	for _, uri := range k.cert.c.URIs {
+		identities = append(identities, uri.String())
+	}
+	return identities
+}
+
+// CryptoPublicKeys implements the pki.KeyMaterial interface; for certificates, the key of the leaf is returned
+func (k PublicKey) CryptoPublicKeys() []crypto.PublicKey {
+	switch {
+	case k.key != nil:
+		return []crypto.PublicKey{k.key}
+	case k.cert != nil:
+		return []crypto.PublicKey{k.cert.c.PublicKey}
+	}
+	return nil
+}
+
+// VerifyChain implements the pki.ChainVerifier interface
+func (k PublicKey) VerifyChain(roots *x509.CertPool, at time.Time) error {
+	if k.cert == nil {
+		return pki.ErrNoCertificateChain
+	}
+	intermediates := make([]*x509.Certificate, 0, len(k.chain))
+	for _, c := range k.chain {
+		intermediates = append(intermediates, c.c)
+	}
+	return VerifyCertChain(k.cert.c, intermediates, roots, at)
+}
+
+// VerifyCertChain checks that the leaf certificate chains up to one of the roots through the supplied
+// intermediates, that all certificates in the chain were valid at the specified time, and that the leaf
+// certificate is permitted to be used for code signing
+func VerifyCertChain(leaf *x509.Certificate, intermediates []*x509.Certificate, roots *x509.CertPool, at time.Time) error {
+	if leaf.KeyUsage != 0 && leaf.KeyUsage&x509.KeyUsageDigitalSignature == 0 {
+		return errors.New("certificate key usage does not permit digital signatures")
+	}
+
+	pool := x509.NewCertPool()
+	for _, c := range intermediates {
+		pool.AddCert(c)
+	}
+	_, err := leaf.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: pool,
+		CurrentTime:   at,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	})
+	return err
+}
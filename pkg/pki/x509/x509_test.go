@@ -20,13 +20,21 @@ import (
 	"crypto"
 	"crypto/ecdsa"
 	"crypto/ed25519"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/sha256"
 	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/url"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/sigstore/rekor/pkg/pki"
 )
 
 // Generated with:
@@ -206,3 +214,348 @@ func TestSignature_VerifyFail(t *testing.T) {
 		})
 	}
 }
+
+func createCert(t *testing.T, template, parent *x509.Certificate, pub, priv interface{}) *x509.Certificate {
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, pub, priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestPublicKey_VerifyChain(t *testing.T) {
+	now := time.Now()
+	rootKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	rootTmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "root"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	root := createCert(t, rootTmpl, rootTmpl, rootKey.Public(), rootKey)
+
+	interKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	interTmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(2),
+		Subject:               pkix.Name{CommonName: "intermediate"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	inter := createCert(t, interTmpl, root, interKey.Public(), rootKey)
+
+	leafKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	leafTmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject:      pkix.Name{CommonName: "leaf"},
+		NotBefore:    now.Add(-time.Minute),
+		NotAfter:     now.Add(time.Minute),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	}
+	leaf := createCert(t, leafTmpl, inter, leafKey.Public(), interKey)
+
+	tlsTmpl := *leafTmpl
+	tlsTmpl.SerialNumber = big.NewInt(4)
+	tlsTmpl.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+	tlsLeaf := createCert(t, &tlsTmpl, inter, leafKey.Public(), interKey)
+
+	encode := func(certs ...*x509.Certificate) []byte {
+		var b bytes.Buffer
+		for _, c := range certs {
+			if err := pem.Encode(&b, &pem.Block{Type: "CERTIFICATE", Bytes: c.Raw}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return b.Bytes()
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(root)
+	otherRoots := x509.NewCertPool()
+	otherRoots.AddCert(inter)
+
+	tests := []struct {
+		name          string
+		pem           []byte
+		roots         *x509.CertPool
+		at            time.Time
+		expectSuccess bool
+	}{
+		{
+			name:          "valid chain",
+			pem:           encode(leaf, inter),
+			roots:         roots,
+			at:            now,
+			expectSuccess: true,
+		},
+		{
+			name:          "missing intermediate",
+			pem:           encode(leaf),
+			roots:         roots,
+			at:            now,
+			expectSuccess: false,
+		},
+		{
+			name:          "unknown root",
+			pem:           encode(leaf, inter),
+			roots:         x509.NewCertPool(),
+			at:            now,
+			expectSuccess: false,
+		},
+		{
+			name:          "intermediate as root",
+			pem:           encode(leaf),
+			roots:         otherRoots,
+			at:            now,
+			expectSuccess: true,
+		},
+		{
+			name:          "expired at signing time",
+			pem:           encode(leaf, inter),
+			roots:         roots,
+			at:            now.Add(10 * time.Minute),
+			expectSuccess: false,
+		},
+		{
+			name:          "not valid for code signing",
+			pem:           encode(tlsLeaf, inter),
+			roots:         roots,
+			at:            now,
+			expectSuccess: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pub, err := NewPublicKey(bytes.NewReader(tt.pem))
+			if err != nil {
+				t.Fatal(err)
+			}
+			cb, err := pub.CanonicalChain()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(cb, tt.pem) {
+				t.Errorf("canonical chain does not contain full chain")
+			}
+			cv, err := pub.CanonicalValue()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if block, _ := pem.Decode(tt.pem); !bytes.Equal(cv, pem.EncodeToMemory(block)) {
+				t.Errorf("canonical value is not the leaf certificate alone")
+			}
+			if err := pub.VerifyChain(tt.roots, tt.at); (err == nil) != tt.expectSuccess {
+				t.Errorf("VerifyChain() error = %v, expected success = %v", err, tt.expectSuccess)
+			}
+		})
+	}
+
+	// the key is found by the same value whatever intermediates it is supplied with
+	var values [][]byte
+	for _, p := range [][]byte{encode(leaf), encode(leaf, inter), encode(leaf, inter, root)} {
+		pub, err := NewPublicKey(bytes.NewReader(p))
+		if err != nil {
+			t.Fatal(err)
+		}
+		cv, err := pub.CanonicalValue()
+		if err != nil {
+			t.Fatal(err)
+		}
+		values = append(values, cv)
+	}
+	if !bytes.Equal(values[0], values[1]) || !bytes.Equal(values[0], values[2]) {
+		t.Errorf("canonical value depends on the intermediates supplied")
+	}
+
+	pub, err := NewPublicKey(strings.NewReader(ecdsaPub))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pub.VerifyChain(roots, now); !errors.Is(err, pki.ErrNoCertificateChain) {
+		t.Errorf("expected ErrNoCertificateChain for bare public key, got %v", err)
+	}
+}
+
+func TestSignature_VerifyAlgorithms(t *testing.T) {
+	data := []byte("hey! this is my test data")
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p384Key, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	p521Key, _ := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+
+	digest := func(h crypto.Hash) []byte {
+		hasher := h.New()
+		hasher.Write(data)
+		return hasher.Sum(nil)
+	}
+	mustSign := func(b []byte, err error) []byte {
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+	marshal := func(pub interface{}) string {
+		b, err := x509.MarshalPKIXPublicKey(pub)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: b}))
+	}
+
+	tests := []struct {
+		name          string
+		sig           []byte
+		pub           string
+		setAlgorithm  string
+		expectSuccess bool
+		expectAlg     string
+	}{
+		{
+			name:          "rsa pkcs1v15 sha384",
+			sig:           mustSign(rsa.SignPKCS1v15(rand.Reader, rsaKey, crypto.SHA384, digest(crypto.SHA384))),
+			pub:           marshal(rsaKey.Public()),
+			expectSuccess: true,
+			expectAlg:     RSAPKCS1v15SHA384,
+		},
+		{
+			name:          "rsa pss sha512",
+			sig:           mustSign(rsa.SignPSS(rand.Reader, rsaKey, crypto.SHA512, digest(crypto.SHA512), nil)),
+			pub:           marshal(rsaKey.Public()),
+			expectSuccess: true,
+			expectAlg:     RSAPSSSHA512,
+		},
+		{
+			name:          "rsa pss sha256 explicitly set",
+			sig:           mustSign(rsa.SignPSS(rand.Reader, rsaKey, crypto.SHA256, digest(crypto.SHA256), nil)),
+			pub:           marshal(rsaKey.Public()),
+			setAlgorithm:  RSAPSSSHA256,
+			expectSuccess: true,
+			expectAlg:     RSAPSSSHA256,
+		},
+		{
+			name:          "rsa pss sha256 with wrong algorithm set",
+			sig:           mustSign(rsa.SignPSS(rand.Reader, rsaKey, crypto.SHA256, digest(crypto.SHA256), nil)),
+			pub:           marshal(rsaKey.Public()),
+			setAlgorithm:  RSAPKCS1v15SHA256,
+			expectSuccess: false,
+		},
+		{
+			name:          "rsa pkcs1v15 sha256 is not recorded",
+			sig:           mustSign(rsa.SignPKCS1v15(rand.Reader, rsaKey, crypto.SHA256, digest(crypto.SHA256))),
+			pub:           marshal(rsaKey.Public()),
+			expectSuccess: true,
+			expectAlg:     "",
+		},
+		{
+			name:          "ecdsa p384 sha384",
+			sig:           mustSign(ecdsa.SignASN1(rand.Reader, p384Key, digest(crypto.SHA384))),
+			pub:           marshal(p384Key.Public()),
+			expectSuccess: true,
+			expectAlg:     ECDSASHA384,
+		},
+		{
+			name:          "ecdsa p384 sha256",
+			sig:           mustSign(ecdsa.SignASN1(rand.Reader, p384Key, digest(crypto.SHA256))),
+			pub:           marshal(p384Key.Public()),
+			expectSuccess: true,
+			expectAlg:     "",
+		},
+		{
+			name:          "ecdsa p521 sha512",
+			sig:           mustSign(ecdsa.SignASN1(rand.Reader, p521Key, digest(crypto.SHA512))),
+			pub:           marshal(p521Key.Public()),
+			expectSuccess: true,
+			expectAlg:     ECDSASHA512,
+		},
+		{
+			name:          "ecdsa with rsa algorithm set",
+			sig:           mustSign(ecdsa.SignASN1(rand.Reader, p384Key, digest(crypto.SHA384))),
+			pub:           marshal(p384Key.Public()),
+			setAlgorithm:  RSAPSSSHA384,
+			expectSuccess: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewSignature(bytes.NewReader(tt.sig))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.setAlgorithm != "" {
+				if err := s.SetAlgorithm(tt.setAlgorithm); err != nil {
+					t.Fatal(err)
+				}
+			}
+			pub, err := NewPublicKey(strings.NewReader(tt.pub))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = s.Verify(bytes.NewReader(data), pub)
+			if (err == nil) != tt.expectSuccess {
+				t.Fatalf("Signature.Verify() error = %v, expected success = %v", err, tt.expectSuccess)
+			}
+			if tt.expectSuccess && s.Algorithm() != tt.expectAlg {
+				t.Errorf("Signature.Algorithm() = %v, expected %v", s.Algorithm(), tt.expectAlg)
+			}
+		})
+	}
+
+	var s pki.Signature = &Signature{}
+	agileSig, ok := s.(pki.AlgorithmAgileSignature)
+	if !ok {
+		t.Fatal("x509 signatures should support setting the algorithm")
+	}
+	if err := agileSig.SetAlgorithm("dsa-sha1"); err == nil {
+		t.Error("expected error setting unsupported algorithm")
+	}
+}
+
+func TestPublicKey_Identities(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	uri, _ := url.Parse("https://github.com/example/repo/.github/workflows/release.yml@refs/heads/main")
+	tmpl := &x509.Certificate{
+		SerialNumber:   big.NewInt(1),
+		Subject:        pkix.Name{CommonName: "release signer"},
+		NotBefore:      time.Now().Add(-time.Hour),
+		NotAfter:       time.Now().Add(time.Hour),
+		EmailAddresses: []string{"release@example.com"},
+		URIs:           []*url.URL{uri},
+	}
+	c := createCert(t, tmpl, tmpl, key.Public(), key)
+
+	pub, err := NewPublicKey(bytes.NewReader(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.Raw})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := pub.Identities()
+	want := []string{"release signer", "release@example.com", uri.String()}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("expected identities %v, got %v", want, got)
+	}
+
+	// bare keys carry no identity
+	der, err := x509.MarshalPKIXPublicKey(key.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err = NewPublicKey(bytes.NewReader(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ids := pub.Identities(); len(ids) != 0 {
+		t.Errorf("expected no identities for bare key, got %v", ids)
+	}
+}
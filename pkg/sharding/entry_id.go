@@ -0,0 +1,67 @@
+//
+// Copyright 2021 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sharding describes how entries are identified in a log that is split across several trees
+package sharding
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+)
+
+const (
+	// TreeIDHexStringLen is the length of the tree ID prefix of an entry ID
+	TreeIDHexStringLen = 16
+	// UUIDHexStringLen is the length of the RFC 6962 leaf hash of an entry, which identifies it within a tree
+	UUIDHexStringLen = 64
+	// EntryIDHexStringLen is the length of an entry ID, which identifies an entry across all of the trees of a log
+	EntryIDHexStringLen = TreeIDHexStringLen + UUIDHexStringLen
+)
+
+// CreateEntryID returns the ID of the entry with the UUID in the tree given
+func CreateEntryID(treeID int64, uuid string) string {
+	return fmt.Sprintf("%016x%s", uint64(treeID), uuid)
+}
+
+// ParseEntryID returns the tree ID and UUID of an entry ID; a UUID without a tree ID prefix is also accepted, in
+// which case the tree ID returned is 0
+func ParseEntryID(id string) (int64, string, error) {
+	switch len(id) {
+	case UUIDHexStringLen:
+		if _, err := hex.DecodeString(id); err != nil {
+			return 0, "", fmt.Errorf("UUID %v is not a hexadecimal string: %w", id, err)
+		}
+		return 0, id, nil
+	case EntryIDHexStringLen:
+		treeID, err := strconv.ParseUint(id[:TreeIDHexStringLen], 16, 64)
+		if err != nil {
+			return 0, "", fmt.Errorf("tree ID of entry ID %v is not a hexadecimal string: %w", id, err)
+		}
+		uuid := id[TreeIDHexStringLen:]
+		if _, err := hex.DecodeString(uuid); err != nil {
+			return 0, "", fmt.Errorf("UUID of entry ID %v is not a hexadecimal string: %w", id, err)
+		}
+		return int64(treeID), uuid, nil
+	default:
+		return 0, "", fmt.Errorf("entry ID %v must be %d or %d hexadecimal characters", id, UUIDHexStringLen, EntryIDHexStringLen)
+	}
+}
+
+// UUIDFromEntryID returns the UUID of an entry ID, which may or may not have a tree ID prefix
+func UUIDFromEntryID(id string) (string, error) {
+	_, uuid, err := ParseEntryID(id)
+	return uuid, err
+}
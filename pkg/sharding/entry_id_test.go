@@ -0,0 +1,87 @@
+//
+// Copyright 2021 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sharding
+
+import (
+	"strings"
+	"testing"
+)
+
+const testUUID = "7facde166eec76b9b88ac64d6f896206b185a612cc3d02b901ba2dd69a0104be"
+
+func TestEntryID(t *testing.T) {
+	tests := []struct {
+		caseDesc       string
+		id             string
+		expectedTreeID int64
+		expectSuccess  bool
+	}{
+		{
+			caseDesc:       "UUID without tree ID",
+			id:             testUUID,
+			expectedTreeID: 0,
+			expectSuccess:  true,
+		},
+		{
+			caseDesc:       "entry ID",
+			id:             CreateEntryID(5617093393417395328, testUUID),
+			expectedTreeID: 5617093393417395328,
+			expectSuccess:  true,
+		},
+		{
+			caseDesc:       "upper case entry ID",
+			id:             strings.ToUpper(CreateEntryID(1, testUUID)),
+			expectedTreeID: 1,
+			expectSuccess:  true,
+		},
+		{
+			caseDesc:      "tree ID is not hexadecimal",
+			id:            "zzzzzzzzzzzzzzzz" + testUUID,
+			expectSuccess: false,
+		},
+		{
+			caseDesc:      "UUID is not hexadecimal",
+			id:            strings.Repeat("z", UUIDHexStringLen),
+			expectSuccess: false,
+		},
+		{
+			caseDesc:      "wrong length",
+			id:            testUUID[1:],
+			expectSuccess: false,
+		},
+	}
+
+	for _, tc := range tests {
+		treeID, uuid, err := ParseEntryID(tc.id)
+		if (err == nil) != tc.expectSuccess {
+			t.Errorf("unexpected result parsing '%v': %v", tc.caseDesc, err)
+			continue
+		}
+		if !tc.expectSuccess {
+			continue
+		}
+		if treeID != tc.expectedTreeID {
+			t.Errorf("unexpected tree ID in '%v': expected %d, got %d", tc.caseDesc, tc.expectedTreeID, treeID)
+		}
+		if !strings.EqualFold(uuid, testUUID) {
+			t.Errorf("unexpected UUID in '%v': %v", tc.caseDesc, uuid)
+		}
+	}
+
+	if id := CreateEntryID(1, testUUID); len(id) != EntryIDHexStringLen || !strings.HasPrefix(id, "0000000000000001") {
+		t.Errorf("unexpected entry ID %v", id)
+	}
+}
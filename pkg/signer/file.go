@@ -0,0 +1,83 @@
+/*
+Copyright The Rekor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package signer
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/sigstore/sigstore/pkg/signature"
+)
+
+const FileScheme = "file://"
+
+// NewFile returns a signer for the PEM encoded private key in the file, which signs with the scheme that matches
+// the key: ECDSA P-256 keys sign SHA-256 digests and P-384 keys SHA-384 digests, RSA keys sign SHA-256 digests with
+// PKCS #1 v1.5 padding, and Ed25519 keys sign the payload itself
+func NewFile(path string) (signature.Signer, error) {
+	b, err := ioutil.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, errors.Wrap(err, "reading private key")
+	}
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM encoded private key found in %v", path)
+	}
+
+	var key crypto.PrivateKey
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type '%v' in %v", block.Type, path)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing private key")
+	}
+
+	switch key := key.(type) {
+	case *ecdsa.PrivateKey:
+		switch key.Curve {
+		case elliptic.P256():
+			return signature.NewECDSASignerVerifier(key, crypto.SHA256), nil
+		case elliptic.P384():
+			return signature.NewECDSASignerVerifier(key, crypto.SHA384), nil
+		default:
+			return nil, fmt.Errorf("unsupported elliptic curve %v", key.Curve.Params().Name)
+		}
+	case *rsa.PrivateKey:
+		return signature.NewRSASignerVerifier(key, crypto.SHA256), nil
+	case ed25519.PrivateKey:
+		// ed25519 signs the payload rather than a digest of it
+		return signature.GenericSigner{Signer: key, SignerOpts: crypto.Hash(0)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+}
@@ -0,0 +1,111 @@
+/*
+Copyright The Rekor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package signer
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/trillian/types"
+
+	"github.com/sigstore/rekor/pkg/verify"
+)
+
+func TestFile(t *testing.T) {
+	ctx := context.Background()
+	p256, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	p384, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	p224, _ := ecdsa.GenerateKey(elliptic.P224(), rand.Reader)
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+
+	pkcs8 := func(key crypto.PrivateKey) *pem.Block {
+		der, err := x509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			t.Fatalf("marshalling key: %v", err)
+		}
+		return &pem.Block{Type: "PRIVATE KEY", Bytes: der}
+	}
+	ecKey := func(key *ecdsa.PrivateKey) *pem.Block {
+		der, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			t.Fatalf("marshalling key: %v", err)
+		}
+		return &pem.Block{Type: "EC PRIVATE KEY", Bytes: der}
+	}
+
+	tests := []struct {
+		name    string
+		block   *pem.Block
+		wantErr bool
+	}{
+		{name: "ecdsa p256 pkcs8", block: pkcs8(p256)},
+		{name: "ecdsa p256 sec1", block: ecKey(p256)},
+		{name: "ecdsa p384", block: ecKey(p384)},
+		{name: "ecdsa p224", block: ecKey(p224), wantErr: true},
+		{name: "rsa pkcs8", block: pkcs8(rsaKey)},
+		{name: "rsa pkcs1", block: &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)}},
+		{name: "ed25519", block: pkcs8(edKey)},
+		{name: "unsupported block", block: &pem.Block{Type: "CERTIFICATE", Bytes: []byte("foo")}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "key.pem")
+			if err := ioutil.WriteFile(path, pem.EncodeToMemory(tt.block), 0600); err != nil {
+				t.Fatalf("writing key: %v", err)
+			}
+			s, err := New(ctx, FileScheme+path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			// log roots signed with the key can be verified with its public key
+			logRoot, err := (&types.LogRootV1{TreeSize: 1, RootHash: make([]byte, 32)}).MarshalBinary()
+			if err != nil {
+				t.Fatalf("marshalling log root: %v", err)
+			}
+			sig, _, err := s.Sign(ctx, logRoot)
+			if err != nil {
+				t.Fatalf("signing: %v", err)
+			}
+			pub, err := s.PublicKey(ctx)
+			if err != nil {
+				t.Fatalf("public key: %v", err)
+			}
+			if _, err := verify.SignedLogRoot(pub, logRoot, sig); err != nil {
+				t.Errorf("verifying log root: %v", err)
+			}
+		})
+	}
+
+	if _, err := NewFile(filepath.Join(t.TempDir(), "missing.pem")); err == nil {
+		t.Errorf("expected error for missing file")
+	}
+}
@@ -31,6 +31,8 @@ func New(ctx context.Context, signer string) (signature.Signer, error) {
 		return gcp.NewGCP(ctx, signer)
 	case signer == MemoryScheme:
 		return NewMemory()
+	case strings.HasPrefix(signer, FileScheme):
+		return NewFile(strings.TrimPrefix(signer, FileScheme))
 	default:
 		return nil, fmt.Errorf("please provide a valid signer, %v is not valid", signer)
 	}
@@ -24,6 +24,7 @@ import (
 
 	"github.com/mitchellh/mapstructure"
 	"github.com/sigstore/rekor/pkg/generated/models"
+	"github.com/sigstore/rekor/pkg/pki"
 )
 
 // EntryImpl specifies the behavior of a versioned type
@@ -37,6 +38,19 @@ type EntryImpl interface {
 	Validate() error                                  // performs any cross-field validation that is not expressed in the OpenAPI spec
 }
 
+// KeyedEntry is implemented by entries that can give the public key of the signer from the entry as it is stored in
+// the log, without fetching external entities, verifying the signature or applying the admission policy
+type KeyedEntry interface {
+	SignerPublicKey() (pki.PublicKey, error)
+}
+
+// ArtifactEntry is implemented by entries whose canonical form holds the digest of the signed artifact but not the
+// artifact itself, so that an entry read back from the log can only be canonicalized again once the artifact is attached
+type ArtifactEntry interface {
+	ArtifactHash() string          // the hex encoded SHA-256 digest of the artifact, or empty if the entry does not give one
+	AttachArtifact(content []byte) // sets the content of the artifact, which is checked against the digest when canonicalized
+}
+
 // EntryFactory describes a factory function that can generate structs for a specific versioned type
 type EntryFactory func() EntryImpl
 
@@ -28,9 +28,12 @@ import (
 	"io/ioutil"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/sigstore/rekor/pkg/log"
 	"github.com/sigstore/rekor/pkg/pki"
+	"github.com/sigstore/rekor/pkg/pki/pkcs7"
+	"github.com/sigstore/rekor/pkg/pki/x509"
 	"github.com/sigstore/rekor/pkg/types"
 	"github.com/sigstore/rekor/pkg/types/jar"
 	"github.com/sigstore/rekor/pkg/util"
@@ -70,7 +73,7 @@ func NewEntry() types.EntryImpl {
 	return &V001Entry{}
 }
 
-func (v V001Entry) IndexKeys() []string {
+func (v *V001Entry) IndexKeys() []string {
 	var result []string
 
 	if v.HasExternalEntities() {
@@ -99,6 +102,32 @@ func (v V001Entry) IndexKeys() []string {
 	return result
 }
 
+// SignerPublicKey implements the types.KeyedEntry interface; entries in the log hold the signer's certificate,
+// extracted from the PKCS7 signature in the JAR
+func (v *V001Entry) SignerPublicKey() (pki.PublicKey, error) {
+	sig := v.JARModel.Signature
+	if sig == nil || sig.PublicKey == nil || sig.PublicKey.Content == nil {
+		return nil, errors.New("entry does not contain the certificate of the signer")
+	}
+	return pki.NewArtifactFactory(x509.FORMAT).ParsePublicKey(bytes.NewReader(*sig.PublicKey.Content))
+}
+
+// ArtifactHash implements the types.ArtifactEntry interface
+func (v *V001Entry) ArtifactHash() string {
+	if v.JARModel.Archive == nil || v.JARModel.Archive.Hash == nil {
+		return ""
+	}
+	return swag.StringValue(v.JARModel.Archive.Hash.Value)
+}
+
+// AttachArtifact implements the types.ArtifactEntry interface
+func (v *V001Entry) AttachArtifact(content []byte) {
+	if v.JARModel.Archive == nil {
+		v.JARModel.Archive = &models.JarV001SchemaArchive{}
+	}
+	v.JARModel.Archive.Content = content
+}
+
 func (v *V001Entry) Unmarshal(pe models.ProposedEntry) error {
 	jar, ok := pe.(*models.Jar)
 	if !ok {
@@ -142,7 +171,7 @@ func (v *V001Entry) FetchExternalEntities(ctx context.Context) error {
 		oldSHA = swag.StringValue(v.JARModel.Archive.Hash.Value)
 	}
 
-	dataReadCloser, err := util.FileOrURLReadCloser(ctx, v.JARModel.Archive.URL.String(), v.JARModel.Archive.Content)
+	dataReadCloser, err := util.FileOrURLReadCloserWithDigest(ctx, v.JARModel.Archive.URL.String(), v.JARModel.Archive.Content, oldSHA)
 	if err != nil {
 		return err
 	}
@@ -181,7 +210,7 @@ func (v *V001Entry) FetchExternalEntities(ctx context.Context) error {
 	}
 	v.jarObj = jarObj[0]
 
-	af := pki.NewArtifactFactory("pkcs7")
+	af := pki.NewArtifactFactory(pkcs7.FORMAT)
 	// we need to find and extract the PKCS7 bundle from the JAR file manually
 	sigPKCS7, err := extractPKCS7SignatureFromJAR(zipReader)
 	if err != nil {
@@ -198,6 +227,10 @@ func (v *V001Entry) FetchExternalEntities(ctx context.Context) error {
 		return err
 	}
 
+	if err := pki.VerifyTrust(jar.KIND, v.keyObj, v.sigObj, time.Now()); err != nil {
+		return err
+	}
+
 	// if we get here, all goroutines succeeded without error
 	if oldSHA == "" {
 		v.JARModel.Archive.Hash = &models.JarV001SchemaArchiveHash{}
@@ -16,6 +16,7 @@
 package rekord
 
 import (
+	"bytes"
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
@@ -24,6 +25,7 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"time"
 
 	"github.com/asaskevich/govalidator"
 	"github.com/go-openapi/strfmt"
@@ -63,7 +65,7 @@ func NewEntry() types.EntryImpl {
 	return &V001Entry{}
 }
 
-func (v V001Entry) IndexKeys() []string {
+func (v *V001Entry) IndexKeys() []string {
 	var result []string
 
 	if v.HasExternalEntities() {
@@ -92,6 +94,31 @@ func (v V001Entry) IndexKeys() []string {
 	return result
 }
 
+// SignerPublicKey implements the types.KeyedEntry interface
+func (v *V001Entry) SignerPublicKey() (pki.PublicKey, error) {
+	sig := v.RekordObj.Signature
+	if sig == nil || sig.PublicKey == nil || len(sig.PublicKey.Content) == 0 {
+		return nil, errors.New("entry does not contain the content of the public key")
+	}
+	return pki.NewArtifactFactory(sig.Format).ParsePublicKey(bytes.NewReader(sig.PublicKey.Content))
+}
+
+// ArtifactHash implements the types.ArtifactEntry interface
+func (v *V001Entry) ArtifactHash() string {
+	if v.RekordObj.Data == nil || v.RekordObj.Data.Hash == nil {
+		return ""
+	}
+	return swag.StringValue(v.RekordObj.Data.Hash.Value)
+}
+
+// AttachArtifact implements the types.ArtifactEntry interface
+func (v *V001Entry) AttachArtifact(content []byte) {
+	if v.RekordObj.Data == nil {
+		v.RekordObj.Data = &models.RekordV001SchemaData{}
+	}
+	v.RekordObj.Data.Content = content
+}
+
 func (v *V001Entry) Unmarshal(pe models.ProposedEntry) error {
 	rekord, ok := pe.(*models.Rekord)
 	if !ok {
@@ -168,7 +195,7 @@ func (v *V001Entry) FetchExternalEntities(ctx context.Context) error {
 		defer hashW.Close()
 		defer sigW.Close()
 
-		dataReadCloser, err := util.FileOrURLReadCloser(ctx, v.RekordObj.Data.URL.String(), v.RekordObj.Data.Content)
+		dataReadCloser, err := util.FileOrURLReadCloserWithDigest(ctx, v.RekordObj.Data.URL.String(), v.RekordObj.Data.Content, oldSHA)
 		if err != nil {
 			return closePipesOnError(err)
 		}
@@ -221,6 +248,16 @@ func (v *V001Entry) FetchExternalEntities(ctx context.Context) error {
 			return closePipesOnError(err)
 		}
 
+		if alg := v.RekordObj.Signature.Algorithm; alg != "" {
+			agileSig, ok := signature.(pki.AlgorithmAgileSignature)
+			if !ok {
+				return closePipesOnError(fmt.Errorf("signature format '%v' does not support specifying an algorithm", v.RekordObj.Signature.Format))
+			}
+			if err := agileSig.SetAlgorithm(alg); err != nil {
+				return closePipesOnError(err)
+			}
+		}
+
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
@@ -266,6 +303,15 @@ func (v *V001Entry) FetchExternalEntities(ctx context.Context) error {
 			return closePipesOnError(err)
 		}
 
+		// the hash function used by some signatures is only known once they have been verified
+		if err = pki.CheckSignature(v.RekordObj.Signature.Format, v.sigObj); err != nil {
+			return closePipesOnError(err)
+		}
+
+		if err = pki.VerifyTrust(rekord.KIND, v.keyObj, v.sigObj, time.Now()); err != nil {
+			return closePipesOnError(err)
+		}
+
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
@@ -309,6 +355,10 @@ func (v *V001Entry) Canonicalize(ctx context.Context) ([]byte, error) {
 	canonicalEntry.Signature = &models.RekordV001SchemaSignature{}
 	// signature URL (if known) is not set deliberately
 	canonicalEntry.Signature.Format = v.RekordObj.Signature.Format
+	// the algorithm is taken from the verified signature, as it may have been inferred
+	if agileSig, ok := v.sigObj.(pki.AlgorithmAgileSignature); ok {
+		canonicalEntry.Signature.Algorithm = agileSig.Algorithm()
+	}
 
 	var err error
 	canonicalEntry.Signature.Content, err = v.sigObj.CanonicalValue()
@@ -318,7 +368,7 @@ func (v *V001Entry) Canonicalize(ctx context.Context) ([]byte, error) {
 
 	// key URL (if known) is not set deliberately
 	canonicalEntry.Signature.PublicKey = &models.RekordV001SchemaSignaturePublicKey{}
-	canonicalEntry.Signature.PublicKey.Content, err = v.keyObj.CanonicalValue()
+	canonicalEntry.Signature.PublicKey.Content, err = pki.CanonicalEntryValue(v.keyObj)
 	if err != nil {
 		return nil, err
 	}
@@ -16,21 +16,33 @@
 package rekord
 
 import (
+	"bytes"
 	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
 	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
 	"errors"
 	"io/ioutil"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/go-openapi/strfmt"
 	"github.com/go-openapi/swag"
 	"go.uber.org/goleak"
 
 	"github.com/sigstore/rekor/pkg/generated/models"
+	_ "github.com/sigstore/rekor/pkg/pki/pgp"
+	_ "github.com/sigstore/rekor/pkg/pki/x509"
 )
 
 func TestMain(m *testing.M) {
@@ -553,3 +565,98 @@ func TestCrossFieldValidation(t *testing.T) {
 		}
 	}
 }
+
+func TestCertificateChain(t *testing.T) {
+	newCert := func(name string, pub crypto.PublicKey, parent *x509.Certificate, parentKey crypto.Signer) *x509.Certificate {
+		t.Helper()
+		tmpl := &x509.Certificate{
+			SerialNumber:          big.NewInt(time.Now().UnixNano()),
+			Subject:               pkix.Name{CommonName: name},
+			NotBefore:             time.Now().Add(-time.Hour),
+			NotAfter:              time.Now().Add(time.Hour),
+			IsCA:                  parent == nil || name != "leaf",
+			BasicConstraintsValid: true,
+		}
+		if parent == nil {
+			parent = tmpl
+		}
+		der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, pub, parentKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c, err := x509.ParseCertificate(der)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+	newKey := func() ed25519.PrivateKey {
+		_, priv, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return priv
+	}
+	rootKey, interKey, leafKey := newKey(), newKey(), newKey()
+	root := newCert("root", rootKey.Public(), nil, rootKey)
+	inter := newCert("intermediate", interKey.Public(), root, rootKey)
+	leaf := newCert("leaf", leafKey.Public(), inter, interKey)
+
+	encode := func(certs ...*x509.Certificate) []byte {
+		var buf bytes.Buffer
+		for _, c := range certs {
+			if err := pem.Encode(&buf, &pem.Block{Type: "CERTIFICATE", Bytes: c.Raw}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return buf.Bytes()
+	}
+	data := []byte("artifact")
+	sig := ed25519.Sign(leafKey, data)
+
+	var indexKeys []string
+	for _, chain := range [][]byte{encode(leaf), encode(leaf, inter), encode(leaf, inter, root)} {
+		entry := V001Entry{
+			RekordObj: models.RekordV001Schema{
+				Signature: &models.RekordV001SchemaSignature{
+					Format:  "x509",
+					Content: strfmt.Base64(sig),
+					PublicKey: &models.RekordV001SchemaSignaturePublicKey{
+						Content: strfmt.Base64(chain),
+					},
+				},
+				Data: &models.RekordV001SchemaData{
+					Content: strfmt.Base64(data),
+				},
+			},
+		}
+		b, err := entry.Canonicalize(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		var canonical struct {
+			Spec models.RekordV001Schema `json:"spec"`
+		}
+		if err := json.Unmarshal(b, &canonical); err != nil {
+			t.Fatal(err)
+		}
+		// the chain is kept in the entry, so that it can be verified again later
+		if !bytes.Equal(canonical.Spec.Signature.PublicKey.Content, chain) {
+			t.Errorf("canonicalized entry does not contain the certificate chain supplied")
+		}
+
+		keys := entry.IndexKeys()
+		if len(keys) == 0 {
+			t.Fatal("expected the entry to have index keys")
+		}
+		indexKeys = append(indexKeys, keys[0])
+	}
+	// the entry is found by its leaf certificate whatever intermediates were supplied
+	if indexKeys[0] != indexKeys[1] || indexKeys[0] != indexKeys[2] {
+		t.Errorf("index key of the public key depends on the intermediates supplied: %v", indexKeys)
+	}
+	leafHash := sha256.Sum256(encode(leaf))
+	if indexKeys[0] != hex.EncodeToString(leafHash[:]) {
+		t.Errorf("expected the index key to be the hash of the leaf certificate, got %v", indexKeys[0])
+	}
+}
@@ -16,6 +16,7 @@
 package rpm
 
 import (
+	"bytes"
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
@@ -67,7 +68,7 @@ func NewEntry() types.EntryImpl {
 	return &V001Entry{}
 }
 
-func (v V001Entry) IndexKeys() []string {
+func (v *V001Entry) IndexKeys() []string {
 	var result []string
 
 	if v.HasExternalEntities() {
@@ -96,6 +97,31 @@ func (v V001Entry) IndexKeys() []string {
 	return result
 }
 
+// SignerPublicKey implements the types.KeyedEntry interface
+func (v *V001Entry) SignerPublicKey() (pki.PublicKey, error) {
+	key := v.RPMModel.PublicKey
+	if key == nil || len(key.Content) == 0 {
+		return nil, errors.New("entry does not contain the content of the public key")
+	}
+	return pki.NewArtifactFactory(pgp.FORMAT).ParsePublicKey(bytes.NewReader(key.Content))
+}
+
+// ArtifactHash implements the types.ArtifactEntry interface
+func (v *V001Entry) ArtifactHash() string {
+	if v.RPMModel.Package == nil || v.RPMModel.Package.Hash == nil {
+		return ""
+	}
+	return swag.StringValue(v.RPMModel.Package.Hash.Value)
+}
+
+// AttachArtifact implements the types.ArtifactEntry interface
+func (v *V001Entry) AttachArtifact(content []byte) {
+	if v.RPMModel.Package == nil {
+		v.RPMModel.Package = &models.RpmV001SchemaPackage{}
+	}
+	v.RPMModel.Package.Content = content
+}
+
 func (v *V001Entry) Unmarshal(pe models.ProposedEntry) error {
 	rpm, ok := pe.(*models.Rpm)
 	if !ok {
@@ -164,14 +190,14 @@ func (v *V001Entry) FetchExternalEntities(ctx context.Context) error {
 	if v.RPMModel.Package.Hash != nil && v.RPMModel.Package.Hash.Value != nil {
 		oldSHA = swag.StringValue(v.RPMModel.Package.Hash.Value)
 	}
-	artifactFactory := pki.NewArtifactFactory("pgp")
+	artifactFactory := pki.NewArtifactFactory(pgp.FORMAT)
 
 	g.Go(func() error {
 		defer hashW.Close()
 		defer sigW.Close()
 		defer rpmW.Close()
 
-		dataReadCloser, err := util.FileOrURLReadCloser(ctx, v.RPMModel.Package.URL.String(), v.RPMModel.Package.Content)
+		dataReadCloser, err := util.FileOrURLReadCloserWithDigest(ctx, v.RPMModel.Package.URL.String(), v.RPMModel.Package.Content, oldSHA)
 		if err != nil {
 			return closePipesOnError(err)
 		}
@@ -0,0 +1,64 @@
+//
+// Copyright 2021 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// AttachmentScheme is the URL scheme used by entries to refer to content that was supplied alongside the
+// entry (as a part of a multipart/form-data request) rather than inline or at a remote location
+const AttachmentScheme = "attachment"
+
+// ErrAttachmentNotFound is returned when an entry refers to an attachment that was not supplied
+var ErrAttachmentNotFound = errors.New("attachment not supplied")
+
+// Attachments maps the name of each attachment to the file holding its content
+type Attachments map[string]string
+
+type attachmentsKey struct{}
+
+// WithAttachments returns a context in which entries may refer to the supplied attachments
+func WithAttachments(ctx context.Context, a Attachments) context.Context {
+	return context.WithValue(ctx, attachmentsKey{}, a)
+}
+
+// AttachmentURL returns the URL that refers to the named attachment
+func AttachmentURL(name string) string {
+	return AttachmentScheme + ":" + name
+}
+
+// IsAttachmentURL returns true if the URL refers to an attachment
+func IsAttachmentURL(url string) bool {
+	return strings.HasPrefix(url, AttachmentScheme+":")
+}
+
+// openAttachment opens the attachment referred to by the URL from those in the context
+func openAttachment(ctx context.Context, url string) (io.ReadCloser, error) {
+	name := strings.TrimPrefix(url, AttachmentScheme+":")
+	a, _ := ctx.Value(attachmentsKey{}).(Attachments)
+	path, ok := a[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: '%v'", ErrAttachmentNotFound, name)
+	}
+	return os.Open(filepath.Clean(path))
+}
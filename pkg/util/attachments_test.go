@@ -0,0 +1,49 @@
+//
+// Copyright 2021 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestAttachments(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "artifact")
+	if err := ioutil.WriteFile(path, []byte("attached content"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	ctx := WithAttachments(context.Background(), Attachments{"artifact": path})
+
+	rc, err := FileOrURLReadCloser(ctx, AttachmentURL("artifact"), nil)
+	if err != nil {
+		t.Fatalf("unexpected error opening attachment: %v", err)
+	}
+	b, err := ioutil.ReadAll(rc)
+	rc.Close()
+	if err != nil || string(b) != "attached content" {
+		t.Errorf("unexpected attachment content %q: %v", b, err)
+	}
+
+	if _, err := FileOrURLReadCloser(ctx, AttachmentURL("signature"), nil); !errors.Is(err, ErrAttachmentNotFound) {
+		t.Errorf("expected ErrAttachmentNotFound for missing attachment, got %v", err)
+	}
+	if _, err := FileOrURLReadCloser(context.Background(), AttachmentURL("artifact"), nil); !errors.Is(err, ErrAttachmentNotFound) {
+		t.Errorf("expected ErrAttachmentNotFound without attachments in context, got %v", err)
+	}
+}
@@ -0,0 +1,290 @@
+//
+// Copyright 2021 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sigstore/rekor/pkg/log"
+)
+
+// FetchCacheConfig controls the cache of external entities fetched by URL
+type FetchCacheConfig struct {
+	MaxMemorySize int64         // total size in bytes of entities held in memory; 0 disables the cache
+	MaxEntrySize  int64         // entities larger than this are never cached
+	TTL           time.Duration // how long an entity fetched without an expected digest may be served from the cache
+	Dir           string        // if set, entities evicted from memory are kept in this directory, and reused after a restart
+	MaxDiskSize   int64         // total size in bytes of entities held in Dir
+}
+
+// DefaultFetchCacheConfig returns the cache configuration recommended for a server
+func DefaultFetchCacheConfig() FetchCacheConfig {
+	return FetchCacheConfig{
+		MaxMemorySize: 256 << 20,
+		MaxEntrySize:  32 << 20,
+		TTL:           10 * time.Minute,
+		MaxDiskSize:   1 << 30,
+	}
+}
+
+// cacheKey identifies a fetch; when the expected digest of the entity is known, a cached entity is only
+// served if its content has that digest
+type cacheKey struct {
+	url    string
+	digest string
+}
+
+type cacheIndexEntry struct {
+	digest  string
+	expires time.Time
+}
+
+// cachedBlob is an entity held in the cache, addressed by the SHA256 digest of its content
+type cachedBlob struct {
+	digest string
+	data   []byte // nil if the blob is only held on disk
+	size   int64
+}
+
+// fetchCache is a content-addressed cache of entities; an index maps URLs (and expected digests) to the
+// digest of the content, and blobs are held in memory with least-recently-used eviction to an optional
+// on-disk tier
+type fetchCache struct {
+	config FetchCacheConfig
+
+	mu         sync.Mutex
+	index      map[cacheKey]cacheIndexEntry
+	memory     *list.List // of *cachedBlob, most recently used first
+	memoryMap  map[string]*list.Element
+	memorySize int64
+	disk       *list.List // of *cachedBlob, most recently written first
+	diskMap    map[string]*list.Element
+	diskSize   int64
+}
+
+var (
+	fetchCacheMu sync.RWMutex
+	activeCache  *fetchCache
+)
+
+// SetFetchCacheConfig enables (or, with a zero MaxMemorySize, disables) the cache used by FileOrURLReadCloser
+func SetFetchCacheConfig(c FetchCacheConfig) error {
+	var fc *fetchCache
+	if c.MaxMemorySize > 0 {
+		if c.Dir != "" {
+			if err := os.MkdirAll(c.Dir, 0700); err != nil {
+				return err
+			}
+		}
+		fc = &fetchCache{
+			config:    c,
+			index:     map[cacheKey]cacheIndexEntry{},
+			memory:    list.New(),
+			memoryMap: map[string]*list.Element{},
+			disk:      list.New(),
+			diskMap:   map[string]*list.Element{},
+		}
+		if c.Dir != "" {
+			if err := fc.loadDisk(); err != nil {
+				return err
+			}
+		}
+	}
+	fetchCacheMu.Lock()
+	defer fetchCacheMu.Unlock()
+	activeCache = fc
+	return nil
+}
+
+func currentFetchCache() *fetchCache {
+	fetchCacheMu.RLock()
+	defer fetchCacheMu.RUnlock()
+	return activeCache
+}
+
+// get returns the cached content for the key, if present and not expired
+func (c *fetchCache) get(k cacheKey) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	digest := k.digest
+	if ie, ok := c.index[k]; ok {
+		if k.digest == "" && time.Now().After(ie.expires) {
+			delete(c.index, k)
+			return nil, false
+		}
+		digest = ie.digest
+	}
+	if digest == "" {
+		return nil, false
+	}
+
+	if e, ok := c.memoryMap[digest]; ok {
+		c.memory.MoveToFront(e)
+		return e.Value.(*cachedBlob).data, true
+	}
+	if _, ok := c.diskMap[digest]; !ok {
+		return nil, false
+	}
+
+	// content on disk is addressed by its digest, so it can be served for any URL once it is verified
+	b, err := ioutil.ReadFile(c.blobPath(digest))
+	if err != nil {
+		return nil, false
+	}
+	if computed := sha256.Sum256(b); hex.EncodeToString(computed[:]) != digest {
+		log.Logger.Warnf("removing corrupt cache entry %v", digest)
+		c.removeFromDisk(digest)
+		return nil, false
+	}
+	c.addToMemory(&cachedBlob{digest: digest, data: b, size: int64(len(b))})
+	return b, true
+}
+
+// put stores the content for the key, unless it does not match the expected digest
+func (c *fetchCache) put(k cacheKey, data []byte) {
+	sum := sha256.Sum256(data)
+	digest := hex.EncodeToString(sum[:])
+	if k.digest != "" && !strings.EqualFold(k.digest, digest) {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.index[k] = cacheIndexEntry{digest: digest, expires: time.Now().Add(c.config.TTL)}
+	if e, ok := c.memoryMap[digest]; ok {
+		c.memory.MoveToFront(e)
+		return
+	}
+	c.addToMemory(&cachedBlob{digest: digest, data: data, size: int64(len(data))})
+}
+
+// loadDisk tracks the entities left in Dir by an earlier process, most recently written first, so that they
+// count towards MaxDiskSize; the oldest are removed if they exceed it. Files that are not named by a digest
+// were not written by the cache, and are left alone.
+func (c *fetchCache) loadDisk() error {
+	infos, err := ioutil.ReadDir(c.config.Dir)
+	if err != nil {
+		return err
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].ModTime().After(infos[j].ModTime()) })
+	for _, fi := range infos {
+		if !fi.Mode().IsRegular() {
+			continue
+		}
+		if b, err := hex.DecodeString(fi.Name()); err != nil || len(b) != sha256.Size || fi.Name() != strings.ToLower(fi.Name()) {
+			continue
+		}
+		c.diskMap[fi.Name()] = c.disk.PushBack(&cachedBlob{digest: fi.Name(), size: fi.Size()})
+		c.diskSize += fi.Size()
+	}
+	for c.diskSize > c.config.MaxDiskSize && c.disk.Len() > 0 {
+		c.removeFromDisk(c.disk.Back().Value.(*cachedBlob).digest)
+	}
+	return nil
+}
+
+// addToMemory must be called with c.mu held
+func (c *fetchCache) addToMemory(b *cachedBlob) {
+	c.memoryMap[b.digest] = c.memory.PushFront(b)
+	c.memorySize += b.size
+	for c.memorySize > c.config.MaxMemorySize && c.memory.Len() > 0 {
+		e := c.memory.Back()
+		evicted := e.Value.(*cachedBlob)
+		c.memory.Remove(e)
+		delete(c.memoryMap, evicted.digest)
+		c.memorySize -= evicted.size
+		c.addToDisk(evicted)
+	}
+}
+
+// addToDisk must be called with c.mu held
+func (c *fetchCache) addToDisk(b *cachedBlob) {
+	if c.config.Dir == "" || b.size > c.config.MaxDiskSize {
+		return
+	}
+	if _, ok := c.diskMap[b.digest]; ok {
+		return
+	}
+	if err := ioutil.WriteFile(c.blobPath(b.digest), b.data, 0600); err != nil {
+		log.Logger.Warnf("unable to write cache entry to disk: %v", err)
+		return
+	}
+	c.diskMap[b.digest] = c.disk.PushFront(&cachedBlob{digest: b.digest, size: b.size})
+	c.diskSize += b.size
+	for c.diskSize > c.config.MaxDiskSize && c.disk.Len() > 0 {
+		c.removeFromDisk(c.disk.Back().Value.(*cachedBlob).digest)
+	}
+}
+
+// removeFromDisk must be called with c.mu held
+func (c *fetchCache) removeFromDisk(digest string) {
+	if e, ok := c.diskMap[digest]; ok {
+		c.diskSize -= e.Value.(*cachedBlob).size
+		c.disk.Remove(e)
+		delete(c.diskMap, digest)
+	}
+	if err := os.Remove(c.blobPath(digest)); err != nil && !os.IsNotExist(err) {
+		log.Logger.Warnf("unable to remove cache entry from disk: %v", err)
+	}
+}
+
+func (c *fetchCache) blobPath(digest string) string {
+	return filepath.Join(c.config.Dir, filepath.Base(digest))
+}
+
+// cachingReadCloser passes the entity through to the caller, and adds it to the cache once it has been
+// read completely (as long as it is not too large to cache)
+type cachingReadCloser struct {
+	rc    io.ReadCloser
+	cache *fetchCache
+	key   cacheKey
+	buf   bytes.Buffer
+	full  bool
+}
+
+func (c *cachingReadCloser) Read(p []byte) (int, error) {
+	n, err := c.rc.Read(p)
+	if !c.full {
+		if int64(c.buf.Len()+n) > c.cache.config.MaxEntrySize {
+			c.full = true
+			c.buf = bytes.Buffer{}
+		} else {
+			_, _ = c.buf.Write(p[:n])
+		}
+	}
+	if err == io.EOF && !c.full {
+		c.cache.put(c.key, c.buf.Bytes())
+		c.full = true
+	}
+	return n, err
+}
+
+func (c *cachingReadCloser) Close() error {
+	return c.rc.Close()
+}
@@ -0,0 +1,191 @@
+//
+// Copyright 2021 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFetchCache(t *testing.T) {
+	var requests int32
+	content := map[string]string{
+		"/a":     "artifact a",
+		"/b":     "artifact b",
+		"/large": strings.Repeat("x", 64),
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		_, _ = w.Write([]byte(content[r.URL.Path]))
+	}))
+	defer server.Close()
+	defer func() { _ = SetFetchCacheConfig(FetchCacheConfig{}) }()
+
+	digest := func(s string) string {
+		sum := sha256.Sum256([]byte(s))
+		return hex.EncodeToString(sum[:])
+	}
+	read := func(path, sha string) string {
+		t.Helper()
+		rc, err := FileOrURLReadCloserWithDigest(context.Background(), server.URL+path, nil, sha)
+		if err != nil {
+			t.Fatalf("fetching %v: %v", path, err)
+		}
+		defer rc.Close()
+		b, err := ioutil.ReadAll(rc)
+		if err != nil {
+			t.Fatalf("reading %v: %v", path, err)
+		}
+		return string(b)
+	}
+	expectRequests := func(name string, want int32) {
+		t.Helper()
+		if got := atomic.SwapInt32(&requests, 0); got != want {
+			t.Errorf("%v: expected %d requests, got %d", name, want, got)
+		}
+	}
+
+	dir := t.TempDir()
+	if err := SetFetchCacheConfig(FetchCacheConfig{
+		MaxMemorySize: 16,
+		MaxEntrySize:  32,
+		TTL:           time.Hour,
+		Dir:           dir,
+		MaxDiskSize:   1024,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	read("/a", "")
+	read("/a", "")
+	expectRequests("repeated fetch by URL", 1)
+
+	// the content is already cached, so it is found by its digest
+	read("/a", digest("artifact a"))
+	expectRequests("fetch by URL and digest", 0)
+
+	// a digest that does not match the content is never cached
+	read("/a", digest("something else"))
+	read("/a", digest("something else"))
+	expectRequests("fetch with mismatched digest", 2)
+
+	// entities larger than the maximum entry size are not cached
+	read("/large", "")
+	read("/large", "")
+	expectRequests("fetch of large entity", 2)
+
+	// fetching b evicts a from memory to disk; it can then be served by digest from any URL
+	read("/b", "")
+	expectRequests("fetch of b", 1)
+	if _, err := os.Stat(filepath.Join(dir, digest("artifact a"))); err != nil {
+		t.Errorf("expected a to be evicted to disk: %v", err)
+	}
+	if got := read("/elsewhere", digest("artifact a")); got != "artifact a" {
+		t.Errorf("unexpected content from disk: %q", got)
+	}
+	expectRequests("fetch of a from disk", 0)
+
+	// reading a from disk evicted b to disk; corrupted entries on disk are discarded
+	if err := ioutil.WriteFile(filepath.Join(dir, digest("artifact b")), []byte("tampered"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if got := read("/b", digest("artifact b")); got != "artifact b" {
+		t.Errorf("unexpected content after corruption: %q", got)
+	}
+
+	// entries fetched without a digest expire
+	if err := SetFetchCacheConfig(FetchCacheConfig{MaxMemorySize: 1024, MaxEntrySize: 1024, TTL: -time.Second}); err != nil {
+		t.Fatal(err)
+	}
+	atomic.StoreInt32(&requests, 0)
+	read("/a", "")
+	read("/a", "")
+	expectRequests("fetch of expired entity", 2)
+}
+
+func TestFetchCacheRestart(t *testing.T) {
+	defer func() { _ = SetFetchCacheConfig(FetchCacheConfig{}) }()
+	dir := t.TempDir()
+	digest := func(s string) string {
+		sum := sha256.Sum256([]byte(s))
+		return hex.EncodeToString(sum[:])
+	}
+
+	// the entities left on disk by an earlier process, oldest first
+	now := time.Now()
+	for i, content := range []string{"oldest entity", "middle entity", "newest entity"} {
+		path := filepath.Join(dir, digest(content))
+		if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		mtime := now.Add(time.Duration(i-3) * time.Minute)
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	other := filepath.Join(dir, "not-a-cache-entry")
+	if err := ioutil.WriteFile(other, []byte(strings.Repeat("x", 100)), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// only two of the entities fit, so the oldest is removed
+	if err := SetFetchCacheConfig(FetchCacheConfig{
+		MaxMemorySize: 1,
+		MaxEntrySize:  32,
+		TTL:           time.Hour,
+		Dir:           dir,
+		MaxDiskSize:   26,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	c := currentFetchCache()
+	if c.diskSize != 26 || c.disk.Len() != 2 {
+		t.Errorf("disk tier holds %d entities of %d bytes, want 2 of 26 bytes", c.disk.Len(), c.diskSize)
+	}
+	if _, err := os.Stat(filepath.Join(dir, digest("oldest entity"))); !os.IsNotExist(err) {
+		t.Errorf("expected the oldest entity to be removed: %v", err)
+	}
+	if _, err := os.Stat(other); err != nil {
+		t.Errorf("expected files not written by the cache to be left alone: %v", err)
+	}
+
+	// the remaining entities are served by digest
+	for _, content := range []string{"middle entity", "newest entity"} {
+		if b, ok := c.get(cacheKey{url: "https://example.com", digest: digest(content)}); !ok || string(b) != content {
+			t.Errorf("expected %q from disk, got %q, %v", content, b, ok)
+		}
+	}
+	if _, ok := c.get(cacheKey{url: "https://example.com", digest: digest("oldest entity")}); ok {
+		t.Errorf("expected the removed entity not to be served")
+	}
+
+	// entities evicted after the restart count towards the same limit
+	c.put(cacheKey{url: "https://example.com/new"}, []byte("another entity"))
+	c.put(cacheKey{url: "https://example.com/next"}, []byte("yet another one"))
+	if c.diskSize > 26 {
+		t.Errorf("disk tier holds %d bytes, more than the maximum of 26", c.diskSize)
+	}
+}
@@ -18,33 +18,312 @@ package util
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
 )
 
-// FileOrURLReadCloser Note: caller is responsible for closing ReadCloser returned from method!
-func FileOrURLReadCloser(ctx context.Context, url string, content []byte) (io.ReadCloser, error) {
-	var dataReader io.ReadCloser
-	if url != "" {
-		//TODO: set timeout here, SSL settings?
-		client := &http.Client{}
-		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-		if err != nil {
-			return nil, err
+var (
+	// ErrFetchNotPermitted is returned when the URL of an external entity, or an address it resolves to,
+	// is not permitted by the fetch configuration
+	ErrFetchNotPermitted = errors.New("fetching external entity is not permitted")
+	// ErrEntityTooLarge is returned when an external entity exceeds the maximum configured size
+	ErrEntityTooLarge = errors.New("external entity exceeds maximum size")
+)
+
+// FetchConfig controls how external entities referenced by URL are retrieved
+type FetchConfig struct {
+	ConnectTimeout        time.Duration // maximum time to establish a connection, including the TLS handshake
+	ReadTimeout           time.Duration // maximum time to receive the response, including the body
+	MaxBodySize           int64         // maximum size of an entity in bytes; 0 means unlimited
+	MaxRedirects          int           // maximum number of redirects that are followed
+	AllowedSchemes        []string      // URL schemes that may be fetched
+	AllowedHosts          []string      // hosts that may be fetched; "*.example.com" matches any subdomain; empty means any host
+	BlockPrivateAddresses bool          // refuse to connect to loopback, private, link-local and other non-public addresses
+}
+
+// DefaultFetchConfig returns the configuration recommended for a server accepting entries from untrusted clients
+func DefaultFetchConfig() FetchConfig {
+	return FetchConfig{
+		ConnectTimeout:        10 * time.Second,
+		ReadTimeout:           60 * time.Second,
+		MaxBodySize:           128 << 20,
+		MaxRedirects:          3,
+		AllowedSchemes:        []string{"https", "http"},
+		BlockPrivateAddresses: true,
+	}
+}
+
+var (
+	fetcherMu sync.RWMutex
+	fetcher   = newFetcher(FetchConfig{
+		ConnectTimeout: 30 * time.Second,
+		MaxRedirects:   10,
+		AllowedSchemes: []string{"https", "http"},
+	})
+)
+
+// SetFetchConfig replaces the configuration used by FileOrURLReadCloser
+func SetFetchConfig(c FetchConfig) {
+	f := newFetcher(c)
+	fetcherMu.Lock()
+	defer fetcherMu.Unlock()
+	fetcher = f
+}
+
+type urlFetcher struct {
+	config FetchConfig
+	client *http.Client
+	// addresses of proxies returned by the environment; these are trusted, as they are configured by the operator
+	proxies sync.Map
+}
+
+func newFetcher(c FetchConfig) *urlFetcher {
+	f := &urlFetcher{config: c}
+
+	dialer := &net.Dialer{
+		Timeout:   c.ConnectTimeout,
+		KeepAlive: 30 * time.Second,
+	}
+	safeDialer := &net.Dialer{
+		Timeout:   c.ConnectTimeout,
+		KeepAlive: 30 * time.Second,
+		// this is invoked with the resolved address immediately before connecting, so it cannot be
+		// bypassed by DNS records that change between validation and use
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+				return fmt.Errorf("%w: address %v is not publicly routable", ErrFetchNotPermitted, host)
+			}
+			return nil
+		},
+	}
+
+	transport := &http.Transport{
+		Proxy: func(req *http.Request) (*url.URL, error) {
+			proxy, err := http.ProxyFromEnvironment(req)
+			if proxy != nil && err == nil {
+				f.proxies.Store(canonicalAddr(proxy), true)
+				// the proxy resolves the destination, so check the addresses it resolves to here instead
+				if c.BlockPrivateAddresses {
+					if err := checkHostAddresses(req.Context(), req.URL.Hostname()); err != nil {
+						return nil, err
+					}
+				}
+			}
+			return proxy, err
+		},
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			if _, isProxy := f.proxies.Load(addr); isProxy || !c.BlockPrivateAddresses {
+				return dialer.DialContext(ctx, network, addr)
+			}
+			return safeDialer.DialContext(ctx, network, addr)
+		},
+		TLSHandshakeTimeout:   c.ConnectTimeout,
+		ResponseHeaderTimeout: c.ReadTimeout,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+
+	f.client = &http.Client{
+		Transport: transport,
+		Timeout:   c.ReadTimeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) > c.MaxRedirects {
+				return fmt.Errorf("%w: stopped after %d redirects", ErrFetchNotPermitted, c.MaxRedirects)
+			}
+			return f.checkURL(req.URL)
+		},
+	}
+	return f
+}
+
+// canonicalAddr returns the host:port that the transport dials to reach the URL
+func canonicalAddr(u *url.URL) string {
+	port := u.Port()
+	if port == "" {
+		port = map[string]string{"http": "80", "https": "443", "socks5": "1080"}[u.Scheme]
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+// checkURL validates the scheme and host of the URL against the configuration
+func (f *urlFetcher) checkURL(u *url.URL) error {
+	if !containsFold(f.config.AllowedSchemes, u.Scheme) {
+		return fmt.Errorf("%w: scheme '%v' is not allowed", ErrFetchNotPermitted, u.Scheme)
+	}
+	if len(f.config.AllowedHosts) == 0 {
+		return nil
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, allowed := range f.config.AllowedHosts {
+		allowed = strings.ToLower(allowed)
+		if host == allowed || (strings.HasPrefix(allowed, "*.") && strings.HasSuffix(host, allowed[1:])) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: host '%v' is not allowed", ErrFetchNotPermitted, host)
+}
+
+func (f *urlFetcher) fetch(ctx context.Context, rawURL string) (io.ReadCloser, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.checkURL(u); err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("error received while fetching artifact: %v", resp.Status)
+	}
+	if f.config.MaxBodySize > 0 {
+		if resp.ContentLength > f.config.MaxBodySize {
+			resp.Body.Close()
+			return nil, fmt.Errorf("%w: %v is %d bytes, maximum is %d bytes", ErrEntityTooLarge, rawURL, resp.ContentLength, f.config.MaxBodySize)
 		}
-		resp, err := client.Do(req)
+		return &limitedReadCloser{rc: resp.Body, remaining: f.config.MaxBodySize}, nil
+	}
+	return resp.Body, nil
+}
+
+// limitedReadCloser fails reads once more than the permitted number of bytes have been read, rather
+// than silently truncating the entity
+type limitedReadCloser struct {
+	rc        io.ReadCloser
+	remaining int64
+}
+
+func (l *limitedReadCloser) Read(p []byte) (int, error) {
+	if l.remaining < 0 {
+		return 0, ErrEntityTooLarge
+	}
+	// read one byte more than permitted so that an entity of exactly the maximum size is accepted
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+	n, err := l.rc.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		return n + int(l.remaining), ErrEntityTooLarge
+	}
+	return n, err
+}
+
+func (l *limitedReadCloser) Close() error {
+	return l.rc.Close()
+}
+
+// non-public address ranges not covered by the predicates on net.IP
+var reservedNetworks = func() []*net.IPNet {
+	var nets []*net.IPNet
+	for _, cidr := range []string{
+		"0.0.0.0/8",      // "this" network
+		"10.0.0.0/8",     // private
+		"100.64.0.0/10",  // carrier-grade NAT
+		"172.16.0.0/12",  // private
+		"192.0.0.0/24",   // IETF protocol assignments
+		"192.168.0.0/16", // private
+		"198.18.0.0/15",  // benchmarking
+		"240.0.0.0/4",    // reserved
+		"64:ff9b::/96",   // NAT64, which can embed any IPv4 address
+		"fc00::/7",       // unique local
+		"2001:db8::/32",  // documentation
+	} {
+		_, n, err := net.ParseCIDR(cidr)
 		if err != nil {
-			return nil, err
+			panic(err)
 		}
-		if resp.StatusCode < 200 || resp.StatusCode > 299 {
-			return nil, fmt.Errorf("error received while fetching artifact: %v", resp.Status)
+		nets = append(nets, n)
+	}
+	return nets
+}()
+
+func isPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() || ip.IsUnspecified() {
+		return false
+	}
+	for _, n := range reservedNetworks {
+		if n.Contains(ip) {
+			return false
 		}
+	}
+	return true
+}
 
-		dataReader = resp.Body
-	} else {
-		dataReader = ioutil.NopCloser(bytes.NewReader(content))
+func checkHostAddresses(ctx context.Context, host string) error {
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return err
+	}
+	for _, a := range addrs {
+		if !isPublicIP(a.IP) {
+			return fmt.Errorf("%w: %v resolves to address %v which is not publicly routable", ErrFetchNotPermitted, host, a.IP)
+		}
+	}
+	return nil
+}
+
+func containsFold(list []string, s string) bool {
+	for _, e := range list {
+		if strings.EqualFold(e, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// FileOrURLReadCloser Note: caller is responsible for closing ReadCloser returned from method!
+func FileOrURLReadCloser(ctx context.Context, url string, content []byte) (io.ReadCloser, error) {
+	return FileOrURLReadCloserWithDigest(ctx, url, content, "")
+}
+
+// FileOrURLReadCloserWithDigest behaves like FileOrURLReadCloser, but when the SHA256 digest of the entity
+// is known in advance, a cached copy is only served if its content matches the digest (and can be served
+// regardless of the URL it was originally fetched from). Note: caller is responsible for closing ReadCloser!
+func FileOrURLReadCloserWithDigest(ctx context.Context, url string, content []byte, sha256Digest string) (io.ReadCloser, error) {
+	if url == "" {
+		return ioutil.NopCloser(bytes.NewReader(content)), nil
+	}
+	if IsAttachmentURL(url) {
+		return openAttachment(ctx, url)
+	}
+	fetcherMu.RLock()
+	f := fetcher
+	fetcherMu.RUnlock()
+
+	cache := currentFetchCache()
+	if cache == nil {
+		return f.fetch(ctx, url)
+	}
+	key := cacheKey{url: url, digest: strings.ToLower(sha256Digest)}
+	if b, ok := cache.get(key); ok {
+		return ioutil.NopCloser(bytes.NewReader(b)), nil
+	}
+	rc, err := f.fetch(ctx, url)
+	if err != nil {
+		return nil, err
 	}
-	return dataReader, nil
+	return &cachingReadCloser{rc: rc, cache: cache, key: key}, nil
 }
@@ -0,0 +1,113 @@
+//
+// Copyright 2021 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestFileOrURLReadCloser(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/small", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("0123456789"))
+	})
+	mux.HandleFunc("/large", func(w http.ResponseWriter, r *http.Request) {
+		// no Content-Length is sent for a streamed body, so the limit must be enforced while reading
+		w.(http.Flusher).Flush()
+		_, _ = w.Write([]byte(strings.Repeat("x", 100)))
+	})
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/redirect", http.StatusFound)
+	})
+	mux.HandleFunc("/missing", http.NotFound)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	defer SetFetchConfig(FetchConfig{AllowedSchemes: []string{"http", "https"}, MaxRedirects: 10})
+
+	read := func(url string) (string, error) {
+		rc, err := FileOrURLReadCloser(context.Background(), url, nil)
+		if err != nil {
+			return "", err
+		}
+		defer rc.Close()
+		b, err := ioutil.ReadAll(rc)
+		return string(b), err
+	}
+
+	if got, err := read(server.URL + "/small"); err != nil || got != "0123456789" {
+		t.Errorf("default configuration: got %q, %v", got, err)
+	}
+	if _, err := read(server.URL + "/missing"); err == nil {
+		t.Error("expected error for missing entity")
+	}
+
+	c := DefaultFetchConfig()
+	c.MaxBodySize = 10
+	SetFetchConfig(c)
+	if _, err := read(server.URL + "/small"); !errors.Is(err, ErrFetchNotPermitted) {
+		t.Errorf("expected loopback address to be blocked, got %v", err)
+	}
+
+	c.BlockPrivateAddresses = false
+	SetFetchConfig(c)
+	if got, err := read(server.URL + "/small"); err != nil || got != "0123456789" {
+		t.Errorf("entity of maximum size: got %q, %v", got, err)
+	}
+	if _, err := read(server.URL + "/large"); !errors.Is(err, ErrEntityTooLarge) {
+		t.Errorf("expected entity to be too large, got %v", err)
+	}
+	if _, err := read(server.URL + "/redirect"); !errors.Is(err, ErrFetchNotPermitted) {
+		t.Errorf("expected redirect loop to be stopped, got %v", err)
+	}
+	if _, err := read("file:///etc/passwd"); !errors.Is(err, ErrFetchNotPermitted) {
+		t.Errorf("expected file scheme to be rejected, got %v", err)
+	}
+
+	c.AllowedHosts = []string{"*.example.com"}
+	SetFetchConfig(c)
+	if _, err := read(server.URL + "/small"); !errors.Is(err, ErrFetchNotPermitted) {
+		t.Errorf("expected host to be rejected, got %v", err)
+	}
+}
+
+func TestIsPublicIP(t *testing.T) {
+	for addr, public := range map[string]bool{
+		"8.8.8.8":          true,
+		"2606:4700::1111":  true,
+		"127.0.0.1":        false,
+		"10.1.2.3":         false,
+		"172.20.0.1":       false,
+		"192.168.1.1":      false,
+		"169.254.169.254":  false,
+		"100.64.0.1":       false,
+		"0.0.0.0":          false,
+		"::1":              false,
+		"fe80::1":          false,
+		"fd00::1":          false,
+		"::ffff:127.0.0.1": false,
+	} {
+		if got := isPublicIP(net.ParseIP(addr)); got != public {
+			t.Errorf("isPublicIP(%v) = %v, want %v", addr, got, public)
+		}
+	}
+}
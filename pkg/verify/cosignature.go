@@ -0,0 +1,56 @@
+//
+// Copyright 2021 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/google/trillian/types"
+)
+
+// cosignatureHeader starts each message that a witness signs, so that a cosignature cannot be mistaken for the
+// signature of a log over a log root, or the reverse
+const cosignatureHeader = "rekor witness cosignature v1"
+
+// CosignedMessage returns the message that a witness signs to cosign the log root of the tree given; the tree ID is
+// included because log roots do not name the tree they are of
+func CosignedMessage(treeID int64, logRoot []byte) []byte {
+	return []byte(fmt.Sprintf("%s\n%d\n%s\n", cosignatureHeader, treeID, base64.StdEncoding.EncodeToString(logRoot)))
+}
+
+// Cosignature verifies the cosignature over the log root of the tree given with the key of the witness named by the
+// key hint, and returns the contents of the log root. Witnesses sign with the scheme given by HashForKey for their
+// key, as logs do.
+func (k LogKeys) Cosignature(keyHint []byte, treeID int64, logRoot, signature []byte) (*types.LogRootV1, error) {
+	key := k.ByHint(keyHint)
+	if key == nil {
+		return nil, fmt.Errorf("log root was cosigned by unknown witness %x", keyHint)
+	}
+	hash, err := HashForKey(key.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	if err := verify(key.PublicKey, hash, CosignedMessage(treeID, logRoot), signature); err != nil {
+		return nil, err
+	}
+
+	var lr types.LogRootV1
+	if err := lr.UnmarshalBinary(logRoot); err != nil {
+		return nil, err
+	}
+	return &lr, nil
+}
@@ -0,0 +1,75 @@
+//
+// Copyright 2021 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/trillian/types"
+
+	"github.com/sigstore/rekor/pkg/signer"
+)
+
+func TestCosignature(t *testing.T) {
+	ctx := context.Background()
+	s, err := signer.NewMemory()
+	if err != nil {
+		t.Fatalf("getting signer: %v", err)
+	}
+	pub, err := s.PublicKey(ctx)
+	if err != nil {
+		t.Fatalf("getting public key: %v", err)
+	}
+	key, err := NewLogKey(pub)
+	if err != nil {
+		t.Fatalf("getting log key: %v", err)
+	}
+	witnesses := LogKeys{key}
+
+	logRoot, err := (&types.LogRootV1{TreeSize: 3, RootHash: make([]byte, 32)}).MarshalBinary()
+	if err != nil {
+		t.Fatalf("marshalling log root: %v", err)
+	}
+	sig, _, err := s.Sign(ctx, CosignedMessage(42, logRoot))
+	if err != nil {
+		t.Fatalf("signing: %v", err)
+	}
+
+	lr, err := witnesses.Cosignature(key.ID, 42, logRoot, sig)
+	if err != nil {
+		t.Fatalf("verifying cosignature: %v", err)
+	}
+	if lr.TreeSize != 3 {
+		t.Errorf("expected tree size 3, got %d", lr.TreeSize)
+	}
+
+	// the cosignature is bound to the tree
+	if _, err := witnesses.Cosignature(key.ID, 43, logRoot, sig); err == nil {
+		t.Errorf("expected error for cosignature of another tree")
+	}
+	if _, err := witnesses.Cosignature([]byte("unknown"), 42, logRoot, sig); err == nil {
+		t.Errorf("expected error for unknown witness")
+	}
+	// a signature over the log root itself, as a log makes, is not a cosignature
+	logSig, _, err := s.Sign(ctx, logRoot)
+	if err != nil {
+		t.Fatalf("signing: %v", err)
+	}
+	if _, err := witnesses.Cosignature(key.ID, 42, logRoot, logSig); err == nil {
+		t.Errorf("expected error for signature over the log root")
+	}
+}
@@ -0,0 +1,213 @@
+//
+// Copyright 2021 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"time"
+
+	"github.com/google/trillian/types"
+	"github.com/pkg/errors"
+)
+
+// headers of the PEM blocks of a set of log keys
+const (
+	keyIDHeader     = "Key-ID"
+	notBeforeHeader = "Not-Before"
+	notAfterHeader  = "Not-After"
+)
+
+// LogKey is a public key that a log signs, or has signed, its tree heads with
+type LogKey struct {
+	// ID is the SHA-256 digest of the DER encoded SubjectPublicKeyInfo of the key; it is the key hint of the tree
+	// heads signed with the key
+	ID        []byte
+	PublicKey crypto.PublicKey
+	// NotBefore and NotAfter bound the period in which the log signed with the key; each is zero if unbounded
+	NotBefore time.Time
+	NotAfter  time.Time
+}
+
+// LogKeys is the set of keys that a log signs, or has signed, its tree heads with
+type LogKeys []*LogKey
+
+// KeyID returns the ID of the public key, which is the SHA-256 digest of its DER encoded SubjectPublicKeyInfo
+func KeyID(pub crypto.PublicKey) ([]byte, error) {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshalling public key")
+	}
+	digest := sha256.Sum256(der)
+	return digest[:], nil
+}
+
+// NewLogKey returns the log key for the public key, with no bounds on its validity
+func NewLogKey(pub crypto.PublicKey) (*LogKey, error) {
+	id, err := KeyID(pub)
+	if err != nil {
+		return nil, err
+	}
+	return &LogKey{ID: id, PublicKey: pub}, nil
+}
+
+// ParseLogKeys parses a series of PEM encoded public keys; the Key-ID, Not-Before and Not-After headers of each
+// block are read if present
+func ParseLogKeys(data []byte) (LogKeys, error) {
+	var keys LogKeys
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "PUBLIC KEY" {
+			continue
+		}
+		pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, errors.Wrap(err, "parsing public key")
+		}
+		key, err := NewLogKey(pub)
+		if err != nil {
+			return nil, err
+		}
+		if id, ok := block.Headers[keyIDHeader]; ok && id != hex.EncodeToString(key.ID) {
+			return nil, fmt.Errorf("%v %v does not match public key %x", keyIDHeader, id, key.ID)
+		}
+		if key.NotBefore, err = parseHeaderTime(block, notBeforeHeader); err != nil {
+			return nil, err
+		}
+		if key.NotAfter, err = parseHeaderTime(block, notAfterHeader); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no public keys found")
+	}
+	return keys, nil
+}
+
+// LoadLogKeys reads the keys from the PEM files given; each file may hold several keys, and no key may be given
+// more than once
+func LoadLogKeys(paths []string) (LogKeys, error) {
+	var keys LogKeys
+	for _, path := range paths {
+		b, err := ioutil.ReadFile(filepath.Clean(path))
+		if err != nil {
+			return nil, errors.Wrap(err, "reading key")
+		}
+		parsed, err := ParseLogKeys(b)
+		if err != nil {
+			return nil, errors.Wrapf(err, "key %v", path)
+		}
+		for _, k := range parsed {
+			if keys.ByHint(k.ID) != nil {
+				return nil, fmt.Errorf("key %x in %v is given more than once", k.ID, path)
+			}
+			keys = append(keys, k)
+		}
+	}
+	return keys, nil
+}
+
+func parseHeaderTime(block *pem.Block, header string) (time.Time, error) {
+	v, ok := block.Headers[header]
+	if !ok {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "parsing %v", header)
+	}
+	return t, nil
+}
+
+// MarshalPEM returns the keys as a series of PEM blocks, which can be read by ParseLogKeys
+func (k LogKeys) MarshalPEM() ([]byte, error) {
+	var buf bytes.Buffer
+	for _, key := range k {
+		der, err := x509.MarshalPKIXPublicKey(key.PublicKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "marshalling public key")
+		}
+		headers := map[string]string{keyIDHeader: hex.EncodeToString(key.ID)}
+		if !key.NotBefore.IsZero() {
+			headers[notBeforeHeader] = key.NotBefore.UTC().Format(time.RFC3339)
+		}
+		if !key.NotAfter.IsZero() {
+			headers[notAfterHeader] = key.NotAfter.UTC().Format(time.RFC3339)
+		}
+		if err := pem.Encode(&buf, &pem.Block{Type: "PUBLIC KEY", Headers: headers, Bytes: der}); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// ByHint returns the key with the ID given as the key hint of a signed tree head, or nil if there is none
+func (k LogKeys) ByHint(keyHint []byte) *LogKey {
+	for _, key := range k {
+		if bytes.Equal(key.ID, keyHint) {
+			return key
+		}
+	}
+	return nil
+}
+
+// SignedLogRoot verifies the signed log root with the key named by the key hint, and returns its contents; tree
+// heads without a key hint (from logs that predate key hints) are verified with the only key of the set. Tree heads
+// dated outside the period in which the log signed with the key are rejected, so that a retired key cannot sign
+// further tree heads.
+func (k LogKeys) SignedLogRoot(keyHint, logRoot, logRootSignature []byte) (*types.LogRootV1, error) {
+	var key *LogKey
+	if len(keyHint) == 0 {
+		if len(k) != 1 {
+			return nil, errors.New("signed tree head has no key hint, and there is not exactly one key to verify it with")
+		}
+		key = k[0]
+	} else if key = k.ByHint(keyHint); key == nil {
+		return nil, fmt.Errorf("signed tree head was signed with unknown key %x", keyHint)
+	}
+	lr, err := SignedLogRoot(key.PublicKey, logRoot, logRootSignature)
+	if err != nil {
+		return nil, err
+	}
+	if err := key.checkValidity(lr); err != nil {
+		return nil, err
+	}
+	return lr, nil
+}
+
+// checkValidity returns an error if the log root is dated outside the period in which the log signed with the key
+func (k *LogKey) checkValidity(lr *types.LogRootV1) error {
+	ts := time.Unix(0, int64(lr.TimestampNanos)).UTC()
+	if !k.NotBefore.IsZero() && ts.Before(k.NotBefore) {
+		return fmt.Errorf("tree head dated %v was signed with key %x, which the log did not sign with before %v", ts, k.ID, k.NotBefore)
+	}
+	if !k.NotAfter.IsZero() && ts.After(k.NotAfter) {
+		return fmt.Errorf("tree head dated %v was signed with key %x, which the log stopped signing with at %v", ts, k.ID, k.NotAfter)
+	}
+	return nil
+}
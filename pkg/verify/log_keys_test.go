@@ -0,0 +1,205 @@
+//
+// Copyright 2021 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/trillian/types"
+
+	"github.com/sigstore/rekor/pkg/signer"
+)
+
+func TestLogKeys(t *testing.T) {
+	ctx := context.Background()
+	var keys LogKeys
+	var signers []*signer.Memory
+	for i := 0; i < 2; i++ {
+		s, err := signer.NewMemory()
+		if err != nil {
+			t.Fatalf("getting signer: %v", err)
+		}
+		pub, err := s.PublicKey(ctx)
+		if err != nil {
+			t.Fatalf("getting public key: %v", err)
+		}
+		key, err := NewLogKey(pub)
+		if err != nil {
+			t.Fatalf("getting log key: %v", err)
+		}
+		signers = append(signers, s)
+		keys = append(keys, key)
+	}
+	keys[0].NotAfter = time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	keys[1].NotBefore = keys[0].NotAfter
+
+	// the key set survives a round trip through PEM
+	b, err := keys.MarshalPEM()
+	if err != nil {
+		t.Fatalf("marshalling keys: %v", err)
+	}
+	parsed, err := ParseLogKeys(b)
+	if err != nil {
+		t.Fatalf("parsing keys: %v", err)
+	}
+	if len(parsed) != len(keys) {
+		t.Fatalf("expected %d keys, got %d", len(keys), len(parsed))
+	}
+	for i := range keys {
+		if !bytes.Equal(parsed[i].ID, keys[i].ID) || !parsed[i].NotBefore.Equal(keys[i].NotBefore) || !parsed[i].NotAfter.Equal(keys[i].NotAfter) {
+			t.Errorf("key %d: expected %+v, got %+v", i, keys[i], parsed[i])
+		}
+	}
+
+	// a key ID that does not match the key is rejected
+	if _, err := ParseLogKeys(bytes.Replace(b, []byte(hex.EncodeToString(keys[0].ID)), []byte(hex.EncodeToString(keys[1].ID)), 1)); err == nil {
+		t.Errorf("expected error for mismatched key ID")
+	}
+	if _, err := ParseLogKeys([]byte("not a key")); err == nil {
+		t.Errorf("expected error for no keys")
+	}
+
+	root := types.LogRootV1{TreeSize: 1, RootHash: make([]byte, 32)}
+	logRoot, err := root.MarshalBinary()
+	if err != nil {
+		t.Fatalf("marshalling log root: %v", err)
+	}
+	sig, _, err := signers[0].Sign(ctx, logRoot)
+	if err != nil {
+		t.Fatalf("signing: %v", err)
+	}
+
+	// the tree head is verified with the key named by the hint
+	if _, err := keys.SignedLogRoot(keys[0].ID, logRoot, sig); err != nil {
+		t.Errorf("verifying with key hint: %v", err)
+	}
+	if _, err := keys.SignedLogRoot(keys[1].ID, logRoot, sig); err == nil {
+		t.Errorf("expected error verifying with the wrong key")
+	}
+	if _, err := keys.SignedLogRoot([]byte("unknown"), logRoot, sig); err == nil {
+		t.Errorf("expected error for unknown key hint")
+	}
+
+	// tree heads without a hint can only be verified with a single key
+	if _, err := keys.SignedLogRoot(nil, logRoot, sig); err == nil {
+		t.Errorf("expected error for missing key hint with several keys")
+	}
+	if _, err := keys[:1].SignedLogRoot(nil, logRoot, sig); err != nil {
+		t.Errorf("verifying without key hint: %v", err)
+	}
+
+	// tree heads dated outside the validity period of the key are rejected
+	for _, tt := range []struct {
+		name    string
+		key     int
+		at      time.Time
+		wantErr bool
+	}{
+		{name: "retired key before it was retired", key: 0, at: keys[0].NotAfter.Add(-time.Hour)},
+		{name: "retired key after it was retired", key: 0, at: keys[0].NotAfter.Add(time.Hour), wantErr: true},
+		{name: "current key after it was introduced", key: 1, at: keys[1].NotBefore.Add(time.Hour)},
+		{name: "current key before it was introduced", key: 1, at: keys[1].NotBefore.Add(-time.Hour), wantErr: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			dated, err := (&types.LogRootV1{TreeSize: 1, RootHash: make([]byte, 32), TimestampNanos: uint64(tt.at.UnixNano())}).MarshalBinary()
+			if err != nil {
+				t.Fatalf("marshalling log root: %v", err)
+			}
+			sig, _, err := signers[tt.key].Sign(ctx, dated)
+			if err != nil {
+				t.Fatalf("signing: %v", err)
+			}
+			if _, err := keys.SignedLogRoot(keys[tt.key].ID, dated, sig); (err != nil) != tt.wantErr {
+				t.Errorf("SignedLogRoot() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if _, err := keys[tt.key:tt.key+1].SignedLogRoot(nil, dated, sig); (err != nil) != tt.wantErr {
+				t.Errorf("SignedLogRoot() without key hint error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadLogKeys(t *testing.T) {
+	var keys LogKeys
+	for i := 0; i < 3; i++ {
+		s, err := signer.NewMemory()
+		if err != nil {
+			t.Fatalf("getting signer: %v", err)
+		}
+		pub, err := s.PublicKey(context.Background())
+		if err != nil {
+			t.Fatalf("getting public key: %v", err)
+		}
+		key, err := NewLogKey(pub)
+		if err != nil {
+			t.Fatalf("getting log key: %v", err)
+		}
+		keys = append(keys, key)
+	}
+	dir := t.TempDir()
+	writeKeys := func(name string, keys LogKeys) string {
+		t.Helper()
+		b, err := keys.MarshalPEM()
+		if err != nil {
+			t.Fatalf("marshalling keys: %v", err)
+		}
+		path := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(path, b, 0600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	single := writeKeys("single.pem", keys[:1])
+	several := writeKeys("several.pem", keys[1:])
+	repeated := writeKeys("repeated.pem", LogKeys{keys[1]})
+	empty := filepath.Join(dir, "empty.pem")
+	if err := ioutil.WriteFile(empty, []byte("not a key"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadLogKeys([]string{single, several})
+	if err != nil {
+		t.Fatalf("loading keys: %v", err)
+	}
+	if len(loaded) != 3 {
+		t.Fatalf("expected 3 keys, got %d", len(loaded))
+	}
+	for i, k := range keys {
+		if !bytes.Equal(loaded[i].ID, k.ID) {
+			t.Errorf("key %d: expected ID %x, got %x", i, k.ID, loaded[i].ID)
+		}
+	}
+
+	if loaded, err := LoadLogKeys(nil); err != nil || len(loaded) != 0 {
+		t.Errorf("expected no keys from no files, got %v, %v", loaded, err)
+	}
+	for name, paths := range map[string][]string{
+		"repeated key":  {several, repeated},
+		"repeated file": {single, single},
+		"no keys":       {single, empty},
+		"missing file":  {filepath.Join(dir, "missing.pem")},
+	} {
+		if _, err := LoadLogKeys(paths); err == nil {
+			t.Errorf("%v: expected error", name)
+		}
+	}
+}
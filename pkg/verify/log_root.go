@@ -18,6 +18,9 @@ package verify
 import (
 	"crypto"
 	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
 	"fmt"
 
 	"github.com/google/trillian/types"
@@ -27,9 +30,13 @@ import (
 // this verification copied from https://github.com/google/trillian/blob/v1.3.13/crypto/verifier.go
 // which has since been deleted
 
-// SignedLogRoot verifies the signed log root and returns its contents
+// SignedLogRoot verifies the signed log root and returns its contents; the log root is expected to be signed with
+// the scheme given by HashForKey for the key
 func SignedLogRoot(pub crypto.PublicKey, logRoot, logRootSignature []byte) (*types.LogRootV1, error) {
-	hash := crypto.SHA256
+	hash, err := HashForKey(pub)
+	if err != nil {
+		return nil, err
+	}
 	if err := verify(pub, hash, logRoot, logRootSignature); err != nil {
 		return nil, err
 	}
@@ -41,12 +48,42 @@ func SignedLogRoot(pub crypto.PublicKey, logRoot, logRootSignature []byte) (*typ
 	return &lr, nil
 }
 
-// verify cryptographically verifies the output of Signer.
+// HashForKey returns the hash that a log signs with for its key: SHA-256 for ECDSA P-256 and RSA keys, SHA-384 for
+// ECDSA P-384 keys, and none for Ed25519 keys, which sign the message itself
+func HashForKey(pub crypto.PublicKey) (crypto.Hash, error) {
+	switch pub := pub.(type) {
+	case *ecdsa.PublicKey:
+		switch pub.Curve {
+		case elliptic.P256():
+			return crypto.SHA256, nil
+		case elliptic.P384():
+			return crypto.SHA384, nil
+		default:
+			return 0, fmt.Errorf("unsupported elliptic curve %v", pub.Curve.Params().Name)
+		}
+	case *rsa.PublicKey:
+		return crypto.SHA256, nil
+	case ed25519.PublicKey:
+		return 0, nil
+	default:
+		return 0, fmt.Errorf("unknown public key type: %T", pub)
+	}
+}
+
+// verify cryptographically verifies the output of Signer; RSA signatures may use PKCS #1 v1.5 or PSS padding, and
+// the hash is not used for Ed25519 keys
 func verify(pub crypto.PublicKey, hasher crypto.Hash, data, sig []byte) error {
 	if sig == nil {
 		return errors.New("signature is nil")
 	}
 
+	if pub, ok := pub.(ed25519.PublicKey); ok {
+		if !ed25519.Verify(pub, data, sig) {
+			return errors.New("verification failed")
+		}
+		return nil
+	}
+
 	h := hasher.New()
 	if _, err := h.Write(data); err != nil {
 		return errors.Wrap(err, "write")
@@ -58,6 +95,10 @@ func verify(pub crypto.PublicKey, hasher crypto.Hash, data, sig []byte) error {
 		if !ecdsa.VerifyASN1(pub, digest, sig) {
 			return errors.New("verification failed")
 		}
+	case *rsa.PublicKey:
+		if rsa.VerifyPKCS1v15(pub, hasher, digest, sig) != nil && rsa.VerifyPSS(pub, hasher, digest, sig, nil) != nil {
+			return errors.New("verification failed")
+		}
 	default:
 		return fmt.Errorf("unknown public key type: %T", pub)
 	}
@@ -18,8 +18,15 @@ package verify
 import (
 	"context"
 	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
 	"testing"
 
+	"github.com/google/trillian/types"
+
 	"github.com/sigstore/rekor/pkg/signer"
 )
 
@@ -53,3 +60,106 @@ func TestVerify(t *testing.T) {
 		t.Fatalf("expected failure with incorrect signature")
 	}
 }
+
+func TestSignedLogRoot(t *testing.T) {
+	root := types.LogRootV1{TreeSize: 1, RootHash: make([]byte, 32)}
+	logRoot, err := root.MarshalBinary()
+	if err != nil {
+		t.Fatalf("marshalling log root: %v", err)
+	}
+	digest := func(h crypto.Hash) []byte {
+		d := h.New()
+		_, _ = d.Write(logRoot)
+		return d.Sum(nil)
+	}
+
+	p256, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	p384, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	p521, _ := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	edPub, edPriv, _ := ed25519.GenerateKey(rand.Reader)
+
+	tests := []struct {
+		name    string
+		pub     crypto.PublicKey
+		sign    func() ([]byte, error)
+		wantErr bool
+	}{
+		{
+			name: "ecdsa p256",
+			pub:  &p256.PublicKey,
+			sign: func() ([]byte, error) { return ecdsa.SignASN1(rand.Reader, p256, digest(crypto.SHA256)) },
+		},
+		{
+			name: "ecdsa p384",
+			pub:  &p384.PublicKey,
+			sign: func() ([]byte, error) { return ecdsa.SignASN1(rand.Reader, p384, digest(crypto.SHA384)) },
+		},
+		{
+			name:    "ecdsa p384 with wrong hash",
+			pub:     &p384.PublicKey,
+			sign:    func() ([]byte, error) { return ecdsa.SignASN1(rand.Reader, p384, digest(crypto.SHA256)) },
+			wantErr: true,
+		},
+		{
+			name:    "ecdsa p521 unsupported",
+			pub:     &p521.PublicKey,
+			sign:    func() ([]byte, error) { return ecdsa.SignASN1(rand.Reader, p521, digest(crypto.SHA512)) },
+			wantErr: true,
+		},
+		{
+			name: "rsa pkcs1v15",
+			pub:  &rsaKey.PublicKey,
+			sign: func() ([]byte, error) {
+				return rsa.SignPKCS1v15(rand.Reader, rsaKey, crypto.SHA256, digest(crypto.SHA256))
+			},
+		},
+		{
+			name: "rsa pss",
+			pub:  &rsaKey.PublicKey,
+			sign: func() ([]byte, error) {
+				return rsa.SignPSS(rand.Reader, rsaKey, crypto.SHA256, digest(crypto.SHA256), nil)
+			},
+		},
+		{
+			name: "rsa with wrong hash",
+			pub:  &rsaKey.PublicKey,
+			sign: func() ([]byte, error) {
+				return rsa.SignPKCS1v15(rand.Reader, rsaKey, crypto.SHA384, digest(crypto.SHA384))
+			},
+			wantErr: true,
+		},
+		{
+			name: "ed25519",
+			pub:  edPub,
+			sign: func() ([]byte, error) { return ed25519.Sign(edPriv, logRoot), nil },
+		},
+		{
+			name:    "ed25519 over digest",
+			pub:     edPub,
+			sign:    func() ([]byte, error) { return ed25519.Sign(edPriv, digest(crypto.SHA256)), nil },
+			wantErr: true,
+		},
+		{
+			name:    "wrong key",
+			pub:     &p256.PublicKey,
+			sign:    func() ([]byte, error) { return ed25519.Sign(edPriv, logRoot), nil },
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sig, err := tt.sign()
+			if err != nil {
+				t.Fatalf("signing: %v", err)
+			}
+			lr, err := SignedLogRoot(tt.pub, logRoot, sig)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SignedLogRoot() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && lr.TreeSize != root.TreeSize {
+				t.Errorf("expected tree size %d, got %d", root.TreeSize, lr.TreeSize)
+			}
+		})
+	}
+}
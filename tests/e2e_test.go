@@ -128,6 +128,7 @@ func TestLogInfo(t *testing.T) {
 	// TODO: figure out some way to check the length, add something, and make sure the length increments!
 	out := runCli(t, "loginfo")
 	outputContains(t, out, "Verification Successful!")
+	outputContains(t, out, "Supported Formats: cose, jws, minisign, pgp, pkcs7, ssh, x509")
 }
 
 func TestGet(t *testing.T) {